@@ -2,17 +2,27 @@ package main
 
 import (
 	"log"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"ecommerce/internal/config"
 	"ecommerce/internal/domain" // <--- ADICIONADO: Necessário para acessar as Structs
 	"ecommerce/internal/handler"
+	"ecommerce/internal/jwtkeys"
+	"ecommerce/internal/mail"
 	"ecommerce/internal/middleware"
+	"ecommerce/internal/nfe"
+	"ecommerce/internal/oidc"
 	"ecommerce/internal/repository"
 	"ecommerce/internal/service"
+	"ecommerce/internal/throttle"
 	"ecommerce/seeds"
 
 	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func main() {
@@ -29,11 +39,34 @@ func main() {
 	// ADICIONADO: Auto Migração (Cria as tabelas no Banco)
 	// ============================================================
 	log.Println("Running database migrations...")
+	if err := config.RunDataMigrations(db); err != nil {
+		log.Fatalf("failed to run data migrations: %v", err)
+	}
 	err = db.AutoMigrate(
 		&domain.User{},
 		&domain.Product{},
 		&domain.Order{},
 		&domain.OrderItem{},
+		&domain.FiscalProfile{},
+		&domain.Invoice{},
+		&domain.Cart{},
+		&domain.CartItem{},
+		&domain.ProductPrice{},
+		&domain.ExchangeRate{},
+		&domain.RefreshToken{},
+		&domain.Session{},
+		&domain.UserToken{},
+		&domain.SellerApplication{},
+		&domain.Store{},
+		&domain.AuditLog{},
+		&domain.UserAddress{},
+		&domain.PrivacyRequest{},
+		&domain.RecoveryCode{},
+		&domain.RolePolicy{},
+		&domain.UserIdentity{},
+		&domain.OIDCLoginState{},
+		&domain.APIKey{},
+		&domain.StoreMember{},
 	)
 	if err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
@@ -52,27 +85,108 @@ func main() {
 	productRepo := repository.NewProductRepository(db)
 	userRepo := repository.NewUserRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	cartRepo := repository.NewCartRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	storeRepo := repository.NewStoreRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	userAddressRepo := repository.NewUserAddressRepository(db)
+	privacyRepo := repository.NewPrivacyRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	storeMemberRepo := repository.NewStoreMemberRepository(db)
 
-	// ===== SERVICES =====
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key" // Fallback for development
+	// Logins made before sessions existed get one, so their tokens keep working
+	if created, err := sessionRepo.Backfill(); err != nil {
+		log.Printf("failed to backfill sessions: %v", err)
+	} else if created > 0 {
+		log.Printf("Created %d sessions for existing logins", created)
 	}
 
-	authService := service.NewAuthService(userRepo, jwtSecret)
-	productService := service.NewProductService(productRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo)
+	ensureAdmin(userRepo)
+
+	// ===== SERVICES =====
+	accessTTL := durationFromEnv("JWT_EXPIRATION", service.DefaultAccessTokenTTL)
+	signingKeys := newSigningKeys(accessTTL)
+	refreshTTL := durationFromEnv("REFRESH_TOKEN_EXPIRATION", service.DefaultRefreshTokenTTL)
+	totpIssuer := os.Getenv("TOTP_ISSUER")
+	if totpIssuer == "" {
+		totpIssuer = "E-commerce Fashion"
+	}
+	twoFactorService := service.NewTwoFactorService(userRepo, twoFactorRepo, auditRepo, totpIssuer)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, auditRepo)
+	loginGuard := service.NewLoginGuard(newThrottleStore(), accountLoginPolicy(), service.DefaultIPLoginPolicy, service.DefaultRegistrationPolicy)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, sessionRepo, userTokenRepo, auditRepo, twoFactorService, apiKeyService, loginGuard, signingKeys, accessTTL, refreshTTL)
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:5173"
+	}
+	mailer := newMailer()
+	oidcProviders, localOIDC := newOIDCProviders()
+	oidcRedirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	if oidcRedirectURL == "" {
+		oidcRedirectURL = frontendURL + "/auth/oidc/callback"
+	}
+	socialLoginService := service.NewSocialLoginService(oidcProviders, identityRepo, userRepo, auditRepo, authService, oidcRedirectURL)
+	accountService := service.NewAccountService(userRepo, userTokenRepo, refreshTokenRepo, auditRepo, mailer, frontendURL)
+	currencyConverter := service.NewCurrencyConverter(exchangeRateRepo)
+	productService := service.NewProductService(productRepo, currencyConverter)
+	addressLookup := newAddressLookup()
+	originState := os.Getenv("TAX_ORIGIN_STATE")
+	if originState == "" {
+		originState = "SP"
+	}
+	taxCalculator := newTaxCalculator(originState)
+	shippingCalculator := newShippingCalculator(originState)
+	orderService := service.NewOrderService(orderRepo, productRepo, userAddressRepo, addressLookup, taxCalculator, shippingCalculator, currencyConverter)
+	cartService := service.NewCartService(cartRepo, productRepo)
+	sellerService := service.NewSellerService(storeRepo, productRepo, addressLookup, currencyConverter, mailer, frontendURL)
+	storeTeamService := service.NewStoreTeamService(storeMemberRepo, storeRepo, userRepo, mailer, frontendURL)
+	addressBookService := service.NewAddressBookService(userAddressRepo, addressLookup)
+	privacyService := service.NewPrivacyService(privacyRepo, userRepo, userAddressRepo, orderRepo, auditRepo, storeRepo, sessionRepo, mailer, frontendURL)
+	if err := privacyService.ResumeUnfinished(); err != nil {
+		log.Printf("failed to resume privacy requests: %v", err)
+	}
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo, auditRepo)
+	adminService := service.NewAdminService(userRepo, productRepo, orderRepo, refreshTokenRepo, metricsRepo, auditRepo, storeRepo)
+	invoiceService := service.NewInvoiceService(invoiceRepo, orderRepo, newNFeSigner(), nfe.NewStubTransmitter())
 
 	// ===== HANDLERS =====
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, cartService, accountService)
+	accountHandler := handler.NewAccountHandler(accountService)
 	productHandler := handler.NewProductHandler(productService)
 	orderHandler := handler.NewOrderHandler(orderService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+	cartHandler := handler.NewCartHandler(cartService)
+	currencyHandler := handler.NewCurrencyHandler(currencyConverter)
+	sellerHandler := handler.NewSellerHandler(sellerService)
+	storeTeamHandler := handler.NewStoreTeamHandler(storeTeamService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	addressHandler := handler.NewAddressHandler(addressBookService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
+	socialLoginHandler := handler.NewSocialLoginHandler(socialLoginService, cartService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	jwksHandler := handler.NewJWKSHandler(signingKeys)
+	impersonationHandler := handler.NewImpersonationHandler(authService)
 
 	// ===== ROUTER =====
 	r := gin.Default()
 	r.Use(middleware.CORSMiddleware())
+	if localOIDC != nil {
+		r.Any("/dev/oidc/*path", gin.WrapH(localOIDC))
+	}
+	// Public keys of access tokens, for other services verifying them
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 
 	api := r.Group("/api")
+	api.Use(middleware.CurrencyMiddleware())
 	{
 		// Health check
 		api.GET("/health", func(c *gin.Context) {
@@ -86,33 +200,183 @@ func main() {
 			products.GET("/:id", productHandler.GetProduct)
 		}
 
+		api.GET("/stores/:slug", sellerHandler.GetStore)
+		api.GET("/currencies", currencyHandler.ListCurrencies)
+
+		// ===== CHECKOUT ROUTES =====
+		api.POST("/checkout/quote", middleware.OptionalAuthMiddleware(authService), orderHandler.QuoteOrder)
+
 		// ===== AUTH ROUTES =====
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/login/2fa", authHandler.CompleteLogin)
 			auth.POST("/register", authHandler.Register)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", middleware.AuthMiddleware(authService), authHandler.Logout)
+			auth.POST("/logout-all", middleware.AuthMiddleware(authService), middleware.BlockImpersonation(), authHandler.LogoutAll)
+			auth.POST("/forgot-password", accountHandler.ForgotPassword)
+			auth.POST("/reset-password", accountHandler.ResetPassword)
+			auth.GET("/verify-email", accountHandler.VerifyEmail)
+			auth.GET("/confirm-email-change", accountHandler.ConfirmEmailChange)
+			auth.POST("/resend-verification", middleware.AuthMiddleware(authService), accountHandler.ResendVerification)
 			auth.GET("/me", middleware.AuthMiddleware(authService), authHandler.GetMe)
+			auth.PATCH("/me", middleware.AuthMiddleware(authService), middleware.BlockImpersonation(), accountHandler.UpdateProfile)
+			auth.POST("/change-password", middleware.AuthMiddleware(authService), middleware.BlockImpersonation(), accountHandler.ChangePassword)
+			auth.POST("/change-email", middleware.AuthMiddleware(authService), middleware.BlockImpersonation(), accountHandler.ChangeEmail)
+		}
+
+		// ===== SOCIAL LOGIN ROUTES (OIDC) =====
+		api.GET("/auth/oidc", socialLoginHandler.ListProviders)
+		api.POST("/auth/oidc/:provider/start", socialLoginHandler.Start)
+		api.POST("/auth/oidc/:provider/callback", socialLoginHandler.Callback)
+		api.GET("/auth/identities", middleware.AuthMiddleware(authService), socialLoginHandler.ListIdentities)
+
+		// ===== TWO-FACTOR ROUTES =====
+		twoFactor := api.Group("/auth/2fa")
+		twoFactor.Use(middleware.AuthMiddleware(authService), middleware.BlockImpersonation())
+		{
+			twoFactor.GET("", twoFactorHandler.GetStatus)
+			twoFactor.POST("/enroll", twoFactorHandler.Enroll)
+			twoFactor.POST("/confirm", twoFactorHandler.Confirm)
+			twoFactor.POST("/disable", twoFactorHandler.Disable)
+			twoFactor.POST("/recovery-codes", twoFactorHandler.RenewRecoveryCodes)
+		}
+
+		// ===== CART ROUTES (guests identified by X-Cart-Token) =====
+		cart := api.Group("/cart")
+		cart.Use(middleware.OptionalAuthMiddleware(authService))
+		{
+			cart.GET("", cartHandler.GetCart)
+			cart.DELETE("", cartHandler.ClearCart)
+			cart.POST("/items", cartHandler.AddItem)
+			cart.PATCH("/items/:id", cartHandler.UpdateItem)
+			cart.DELETE("/items/:id", cartHandler.RemoveItem)
+		}
+
+		// ===== PRIVACY, SESSION AND MEMBERSHIP ROUTES (LGPD data-subject requests, signed-in devices, store teams) =====
+		me := api.Group("/me")
+		me.Use(middleware.AuthMiddleware(authService))
+		{
+			me.DELETE("", middleware.BlockImpersonation(), privacyHandler.RequestErasure)
+			me.POST("/data-export", middleware.BlockImpersonation(), privacyHandler.RequestExport)
+			me.GET("/data-export/:id", privacyHandler.GetExport)
+			me.GET("/data-export/:id/download", middleware.BlockImpersonation(), privacyHandler.DownloadExport)
+			me.GET("/sessions", sessionHandler.ListSessions)
+			me.DELETE("/sessions", middleware.BlockImpersonation(), sessionHandler.RevokeOtherSessions)
+			me.PATCH("/sessions/:id", sessionHandler.RenameSession)
+			me.DELETE("/sessions/:id", middleware.BlockImpersonation(), sessionHandler.RevokeSession)
+			me.GET("/stores", storeTeamHandler.ListMyStores)
+			me.DELETE("/stores/:id", middleware.BlockImpersonation(), storeTeamHandler.LeaveStore)
+			me.GET("/store-invitations", storeTeamHandler.ListInvitations)
+			me.POST("/store-invitations/:id/accept", middleware.BlockImpersonation(), storeTeamHandler.AcceptInvitation)
+			me.DELETE("/store-invitations/:id", middleware.BlockImpersonation(), storeTeamHandler.DeclineInvitation)
+		}
+		api.GET("/privacy/erasure/:id", privacyHandler.GetErasure)
+
+		// ===== ADDRESS BOOK ROUTES =====
+		addresses := api.Group("/me/addresses")
+		addresses.Use(middleware.AuthMiddleware(authService))
+		{
+			addresses.GET("", addressHandler.ListAddresses)
+			addresses.POST("", middleware.BlockImpersonation(), addressHandler.CreateAddress)
+			addresses.GET("/:id", addressHandler.GetAddress)
+			addresses.PUT("/:id", middleware.BlockImpersonation(), addressHandler.UpdateAddress)
+			addresses.DELETE("/:id", middleware.BlockImpersonation(), addressHandler.DeleteAddress)
 		}
 
 		// ===== PROTECTED CUSTOMER ROUTES =====
 		customer := api.Group("/orders")
 		customer.Use(middleware.AuthMiddleware(authService))
 		{
-			customer.POST("", orderHandler.CreateOrder)
+			customer.POST("", checkoutPolicy(orderHandler.CreateOrder)...)
 			customer.GET("/my-orders", orderHandler.GetMyOrders)
 			customer.GET("/:id", orderHandler.GetOrder)
 		}
 
 		// ===== SELLER ROUTES =====
+		// Applying to sell is done by the account itself: API keys are not accepted here.
+		sellerApplication := api.Group("/seller")
+		sellerApplication.Use(middleware.AuthMiddleware(authService), middleware.RequireTwoFactorPolicy(twoFactorService))
+		{
+			sellerApplication.POST("/apply", middleware.BlockImpersonation(), middleware.Require(domain.PermSellerApply), sellerHandler.Apply)
+			sellerApplication.GET("/application", sellerHandler.GetMyApplication)
+		}
+
+		// Route groups share the /seller prefix and differ only in the permission they require.
+		// Seller API keys (Authorization: ApiKey ...) are accepted here, within their scopes.
+		// Requests act for the store named by X-Store-ID (default: one's own or only store), and
+		// store permissions come from the user's role in it: owner or team member.
 		seller := api.Group("/seller")
-		seller.Use(middleware.AuthMiddleware(authService))
+		seller.Use(middleware.AllowAPIKeys(), middleware.AuthMiddleware(authService), middleware.ResolveStore(storeTeamService), middleware.RequireTwoFactorPolicy(twoFactorService))
 		{
-			seller.GET("/analytics", orderHandler.GetSellerAnalytics)
-			seller.GET("/orders", orderHandler.GetSellerOrders)
-			seller.GET("/products", productHandler.GetSellerProducts)
-			seller.POST("/products", productHandler.CreateProduct)
-			seller.PUT("/products/:id", productHandler.UpdateProduct)
-			seller.DELETE("/products/:id", productHandler.DeleteProduct)
+			catalog := seller.Group("", middleware.Require(domain.PermProductWrite))
+			catalog.GET("/products", productHandler.GetSellerProducts)
+			catalog.POST("/products", productHandler.CreateProduct)
+			catalog.PUT("/products/:id", productHandler.UpdateProduct)
+			catalog.DELETE("/products/:id", middleware.BlockImpersonation(), productHandler.DeleteProduct)
+			catalog.PUT("/products/:id/prices", middleware.BlockImpersonation(), productHandler.SetProductPrices)
+
+			fulfilment := seller.Group("", middleware.Require(domain.PermOrderFulfil))
+			fulfilment.GET("/orders", orderHandler.GetSellerOrders)
+			fulfilment.POST("/orders/:id/invoice", middleware.BlockImpersonation(), invoiceHandler.IssueInvoice)
+			fulfilment.GET("/orders/:id/invoice", invoiceHandler.GetInvoice)
+			fulfilment.GET("/orders/:id/invoice/xml", invoiceHandler.DownloadXML)
+			fulfilment.GET("/orders/:id/invoice/danfe", invoiceHandler.DownloadDANFE)
+
+			store := seller.Group("", middleware.Require(domain.PermStoreManage))
+			store.GET("/store", sellerHandler.GetMyStore)
+			store.PUT("/store", middleware.BlockImpersonation(), sellerHandler.UpdateMyStore)
+			store.GET("/fiscal-profile", invoiceHandler.GetFiscalProfile)
+			store.PUT("/fiscal-profile", middleware.BlockImpersonation(), invoiceHandler.SaveFiscalProfile)
+
+			seller.GET("/analytics", middleware.Require(domain.PermStoreAnalytics), orderHandler.GetSellerAnalytics)
+
+			team := seller.Group("/team", middleware.Require(domain.PermStoreTeam))
+			team.GET("", storeTeamHandler.ListMembers)
+			team.POST("", middleware.BlockImpersonation(), storeTeamHandler.InviteMember)
+			team.PUT("/:id", middleware.BlockImpersonation(), storeTeamHandler.UpdateMember)
+			team.DELETE("/:id", middleware.BlockImpersonation(), storeTeamHandler.RemoveMember)
+
+			apiKeys := seller.Group("/api-keys", middleware.Require(domain.PermAPIKeyManage))
+			apiKeys.GET("", apiKeyHandler.ListAPIKeys)
+			apiKeys.POST("", middleware.BlockImpersonation(), apiKeyHandler.CreateAPIKey)
+			apiKeys.DELETE("/:id", middleware.BlockImpersonation(), apiKeyHandler.RevokeAPIKey)
+		}
+
+		// ===== ADMIN ROUTES =====
+		admin := api.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(authService), middleware.RequireTwoFactorPolicy(twoFactorService))
+		{
+			admin.GET("/metrics", middleware.Require(domain.PermAdminMetrics), adminHandler.GetMetrics)
+
+			users := admin.Group("/users", middleware.Require(domain.PermAdminUsers))
+			users.GET("", adminHandler.ListUsers)
+			users.GET("/:id", adminHandler.GetUser)
+			users.POST("/:id/suspend", adminHandler.SuspendUser)
+			users.POST("/:id/reactivate", adminHandler.ReactivateUser)
+			users.PUT("/:id/role", adminHandler.ChangeRole)
+			users.GET("/:id/audit-log", adminHandler.ListAuditLog)
+			users.POST("/:id/impersonate", middleware.Require(domain.PermAdminImpersonate), impersonationHandler.Impersonate)
+
+			security := admin.Group("/security", middleware.Require(domain.PermAdminUsers))
+			security.GET("/roles", twoFactorHandler.ListRolePolicies)
+			security.PUT("/roles/:role", twoFactorHandler.UpdateRolePolicy)
+
+			products := admin.Group("/products", middleware.Require(domain.PermAdminCatalog))
+			products.GET("", adminHandler.ListProducts)
+			products.PATCH("/:id", adminHandler.ModerateProduct)
+			products.DELETE("/:id", adminHandler.DeleteProduct)
+
+			orders := admin.Group("/orders", middleware.Require(domain.PermAdminOrders))
+			orders.GET("", adminHandler.ListOrders)
+			orders.GET("/:id", adminHandler.GetOrder)
+			orders.PATCH("/:id", adminHandler.UpdateOrder)
+
+			applications := admin.Group("/seller-applications", middleware.Require(domain.PermAdminSellers))
+			applications.GET("", sellerHandler.ListApplications)
+			applications.POST("/:id/approve", sellerHandler.ApproveApplication)
+			applications.POST("/:id/reject", sellerHandler.RejectApplication)
 		}
 	}
 
@@ -124,3 +388,335 @@ func main() {
 		log.Fatalf("server failed: %v", err)
 	}
 }
+
+// newAddressLookup picks the CEP lookup provider from ADDRESS_LOOKUP:
+// "local" (default, bundled dataset or CEP_DATASET_PATH), "http" (CEP_LOOKUP_URL) or "none"
+func newAddressLookup() service.AddressLookup {
+	switch os.Getenv("ADDRESS_LOOKUP") {
+	case "none":
+		return nil
+	case "http":
+		baseURL := os.Getenv("CEP_LOOKUP_URL")
+		if baseURL == "" {
+			baseURL = "https://viacep.com.br/ws"
+		}
+		return service.NewHTTPAddressLookup(baseURL, 3*time.Second)
+	}
+
+	if path := os.Getenv("CEP_DATASET_PATH"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("failed to open CEP dataset: %v", err)
+		}
+		defer f.Close()
+
+		lookup, err := service.NewLocalAddressLookup(f)
+		if err != nil {
+			log.Fatalf("failed to load CEP dataset: %v", err)
+		}
+		return lookup
+	}
+
+	lookup, err := service.NewBundledAddressLookup()
+	if err != nil {
+		log.Fatalf("failed to load bundled CEP dataset: %v", err)
+	}
+	return lookup
+}
+
+// newTaxCalculator builds the ICMS calculator from TAX_RATES_PATH (or the bundled table)
+func newTaxCalculator(origin string) service.TaxCalculator {
+	table, err := service.BundledICMSRateTable()
+	if path := os.Getenv("TAX_RATES_PATH"); path != "" {
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			log.Fatalf("failed to open tax rate table: %v", openErr)
+		}
+		defer f.Close()
+		table, err = service.LoadICMSRateTable(f)
+	}
+	if err != nil {
+		log.Fatalf("failed to load tax rate table: %v", err)
+	}
+
+	calculator, err := service.NewICMSCalculator(table, origin)
+	if err != nil {
+		log.Fatalf("failed to configure tax calculator: %v", err)
+	}
+	log.Printf("ICMS rate table %s loaded (origin %s)", table.Version, origin)
+	return calculator
+}
+
+// newShippingCalculator builds the flat-rate freight table, with free standard delivery
+// from FREE_SHIPPING_THRESHOLD (default 299.00, 0 disables it)
+func newShippingCalculator(origin string) service.ShippingCalculator {
+	threshold := domain.Cents(29900)
+	if value := os.Getenv("FREE_SHIPPING_THRESHOLD"); value != "" {
+		parsed, err := domain.ParseMoney(value, domain.DefaultCurrency)
+		if err != nil {
+			log.Fatalf("invalid FREE_SHIPPING_THRESHOLD: %v", err)
+		}
+		threshold = parsed
+	}
+
+	calculator, err := service.NewFlatRateShipping(origin, threshold)
+	if err != nil {
+		log.Fatalf("failed to configure shipping calculator: %v", err)
+	}
+	return calculator
+}
+
+// newMailer picks the mail transport from MAILER: "log" (default, development),
+// "file" (one .eml per message in MAIL_DIR) or "smtp" (SMTP_HOST/SMTP_PORT/SMTP_USER/SMTP_PASSWORD)
+func newMailer() mail.Mailer {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			log.Fatalf("invalid SMTP_PORT: %v", err)
+		}
+		mailer, err := mail.NewSMTPMailer(os.Getenv("SMTP_HOST"), port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASSWORD"), os.Getenv("EMAIL_FROM"))
+		if err != nil {
+			log.Fatalf("failed to configure SMTP mailer: %v", err)
+		}
+		return mailer
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		mailer, err := mail.NewFileMailer(dir)
+		if err != nil {
+			log.Fatalf("failed to configure file mailer: %v", err)
+		}
+		return mailer
+	}
+	return mail.NewLogMailer()
+}
+
+// newOIDCProviders configures the social login providers listed in OIDC_PROVIDERS (comma-separated),
+// each from OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID and OIDC_<NAME>_CLIENT_SECRET. Google's issuer
+// is known. "local" is the built-in test provider served at /dev/oidc (OIDC_LOCAL_ISSUER), which lets
+// anyone sign in as any email and is refused in production.
+func newOIDCProviders() ([]*oidc.Provider, *oidc.LocalProvider) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	var providers []*oidc.Provider
+	var local *oidc.LocalProvider
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if name == "local" {
+			if os.Getenv("ENVIRONMENT") == "production" {
+				log.Fatalf("the local OIDC provider cannot be enabled in production")
+			}
+			issuer := os.Getenv("OIDC_LOCAL_ISSUER")
+			if issuer == "" {
+				port := os.Getenv("PORT")
+				if port == "" {
+					port = "8080"
+				}
+				issuer = "http://localhost:" + port + "/dev/oidc"
+			}
+			var err error
+			local, err = oidc.NewLocalProvider(issuer)
+			if err != nil {
+				log.Fatalf("failed to start local OIDC provider: %v", err)
+			}
+			providers = append(providers, oidc.NewProvider(local.Config(), client))
+			log.Printf("local OIDC provider enabled at %s", issuer)
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+		}
+		if config.Issuer == "" && name == "google" {
+			config.Issuer = "https://accounts.google.com"
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			log.Fatalf("%sISSUER and %sCLIENT_ID are required for the %s login provider", prefix, prefix, name)
+		}
+		providers = append(providers, oidc.NewProvider(config, client))
+	}
+	return providers, local
+}
+
+// newThrottleStore picks where login attempt counters live from LOGIN_THROTTLE_STORE:
+// "memory" (default, single instance) or "redis" (REDIS_URL, shared by every instance)
+func newThrottleStore() throttle.Store {
+	if os.Getenv("LOGIN_THROTTLE_STORE") != "redis" {
+		return throttle.NewMemoryStore()
+	}
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis://localhost:6379/0"
+	}
+	store, err := throttle.NewRedisStore(redisURL)
+	if err != nil {
+		log.Fatalf("failed to connect to the login throttle store: %v", err)
+	}
+	return store
+}
+
+// accountLoginPolicy is the default per-account throttling with the lockout from
+// LOGIN_MAX_FAILURES (default 10) and LOGIN_LOCKOUT_DURATION (default 15m)
+func accountLoginPolicy() service.ThrottlePolicy {
+	policy := service.DefaultAccountLoginPolicy
+	if value := os.Getenv("LOGIN_MAX_FAILURES"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= policy.FreeAttempts {
+			log.Fatalf("invalid LOGIN_MAX_FAILURES: %q (must be more than %d)", value, policy.FreeAttempts)
+		}
+		policy.LockoutAfter = n
+	}
+	policy.LockoutDuration = durationFromEnv("LOGIN_LOCKOUT_DURATION", policy.LockoutDuration)
+	return policy
+}
+
+// newSigningKeys loads the access token signing keys from JWT_KEYS_DIR and reloads them every
+// JWT_KEYS_RELOAD_INTERVAL (default 5m), so scheduled keys can be added without a restart.
+// Without a directory an in-memory key is generated, which production refuses.
+func newSigningKeys(accessTTL time.Duration) *jwtkeys.Ring {
+	if os.Getenv("JWT_SECRET") != "" {
+		log.Println("JWT_SECRET is no longer used: access tokens are signed with the keys in JWT_KEYS_DIR")
+	}
+
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		if os.Getenv("ENVIRONMENT") == "production" {
+			log.Fatalf("JWT_KEYS_DIR is required in production")
+		}
+		ring, err := jwtkeys.Generate(accessTTL)
+		if err != nil {
+			log.Fatalf("failed to generate a signing key: %v", err)
+		}
+		log.Println("JWT_KEYS_DIR is not set: signing access tokens with a temporary key, they will not survive a restart")
+		return ring
+	}
+
+	ring, err := jwtkeys.LoadDir(dir, accessTTL)
+	if err != nil {
+		log.Fatalf("failed to load signing keys: %v", err)
+	}
+	log.Printf("Signing access tokens with key %s (%s)", ring.Current().ID, ring.Current().Algorithm())
+
+	interval := durationFromEnv("JWT_KEYS_RELOAD_INTERVAL", 5*time.Minute)
+	go func() {
+		for range time.Tick(interval) {
+			if err := ring.Reload(); err != nil {
+				log.Printf("failed to reload signing keys, keeping the current ones: %v", err)
+			}
+		}
+	}()
+	return ring
+}
+
+// newNFeSigner loads the A1 certificate from NFE_CERT_PATH/NFE_CERT_PASSWORD.
+// Without a certificate invoices are generated unsigned, which only the local stub transmitter accepts.
+func newNFeSigner() nfe.Signer {
+	path := os.Getenv("NFE_CERT_PATH")
+	if path == "" {
+		log.Println("NFE_CERT_PATH not set: NF-e will be generated unsigned")
+		return nil
+	}
+
+	signer, err := nfe.LoadA1Signer(path, os.Getenv("NFE_CERT_PASSWORD"))
+	if err != nil {
+		log.Fatalf("failed to load NF-e certificate: %v", err)
+	}
+	return signer
+}
+
+// ensureAdmin promotes ADMIN_EMAIL to admin, creating the account with ADMIN_PASSWORD
+// when it does not exist yet. Nothing happens when ADMIN_EMAIL is unset. An existing
+// account is only promoted once its email is verified: anyone can register an address.
+func ensureAdmin(userRepo repository.UserRepository) {
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("ADMIN_EMAIL")))
+	if email == "" {
+		return
+	}
+
+	user, err := userRepo.GetByEmail(email)
+	if err != nil {
+		log.Fatalf("failed to load admin account: %v", err)
+	}
+	if user != nil {
+		if user.Role != domain.RoleAdmin {
+			if !user.IsEmailVerified() {
+				log.Fatalf("refusing to promote %s to admin: the account's email is not verified; verify it or delete the account so it is created with ADMIN_PASSWORD", email)
+			}
+			user.Role = domain.RoleAdmin
+			if err := userRepo.Update(user); err != nil {
+				log.Fatalf("failed to promote admin account: %v", err)
+			}
+			log.Printf("%s promoted to admin", email)
+		}
+		return
+	}
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if len(password) < 8 {
+		log.Fatalf("ADMIN_PASSWORD must have at least 8 characters to create the admin account %s", email)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Fatalf("failed to hash admin password: %v", err)
+	}
+	now := time.Now()
+	err = userRepo.Create(&domain.User{
+		Name:            "Admin",
+		Email:           email,
+		PasswordHash:    string(hash),
+		Role:            domain.RoleAdmin,
+		IsActive:        true,
+		EmailVerifiedAt: &now,
+	})
+	if err != nil {
+		log.Fatalf("failed to create admin account: %v", err)
+	}
+	log.Printf("admin account %s created", email)
+}
+
+// checkoutPolicy prepends the checkout checks to a handler: no paying while impersonating,
+// and the checks REQUIRE_VERIFIED_EMAIL_FOR_CHECKOUT enables
+func checkoutPolicy(h gin.HandlerFunc) []gin.HandlerFunc {
+	chain := []gin.HandlerFunc{middleware.BlockImpersonation()}
+	if boolFromEnv("REQUIRE_VERIFIED_EMAIL_FOR_CHECKOUT", false) {
+		chain = append(chain, middleware.RequireVerifiedEmail())
+	}
+	return append(chain, h)
+}
+
+// boolFromEnv parses a boolean such as "true" or "0", falling back when unset
+func boolFromEnv(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %q", name, value)
+	}
+	return b
+}
+
+// durationFromEnv parses a duration such as "15m" or "168h", falling back when unset
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid %s: %q", name, value)
+	}
+	return d
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigration records a data migration that has been applied
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// dataMigration rewrites existing rows before AutoMigrate changes column types.
+// Migrations run once, in order, each in its own transaction.
+type dataMigration struct {
+	id  string
+	run func(tx *gorm.DB) error
+}
+
+var dataMigrations = []dataMigration{
+	{id: "0003_money_to_cents", run: moneyToCents},
+	{id: "0018_invoice_reservation", run: partialAccessKeyIndex},
+}
+
+// RunDataMigrations applies pending data migrations. It must run before AutoMigrate.
+func RunDataMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	for _, m := range dataMigrations {
+		var count int64
+		if err := db.Model(&schemaMigration{}).Where("id = ?", m.id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.id, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s: %w", m.id, err)
+		}
+		log.Printf("Applied data migration %s", m.id)
+	}
+	return nil
+}
+
+// moneyColumns lists the amounts that used to be stored as REAL in major units
+var moneyColumns = map[string][]string{
+	"products":    {"price", "compare_at_price", "cost_price"},
+	"orders":      {"total_amount", "shipping_fee", "discount_amount", "tax_amount"},
+	"order_items": {"price_at_time", "tax_base", "tax_icms_amount", "tax_difal_amount", "tax_fcp_amount", "tax_total_tax"},
+	"cart_items":  {"price_at_add"},
+	"invoices":    {"total_amount"},
+}
+
+// moneyToCents converts REAL amounts (889.9) into integer centavos (88990).
+// Values with sub-cent precision abort the migration instead of being rounded,
+// so the conversion is lossless or does not happen at all.
+func moneyToCents(tx *gorm.DB) error {
+	for table, columns := range moneyColumns {
+		if !tx.Migrator().HasTable(table) {
+			continue
+		}
+		for _, column := range columns {
+			if !tx.Migrator().HasColumn(table, column) {
+				continue
+			}
+
+			var inexact int64
+			err := tx.Table(table).
+				Where(fmt.Sprintf("%[1]s IS NOT NULL AND ABS(%[1]s * 100 - ROUND(%[1]s * 100)) > 0.000001", column)).
+				Count(&inexact).Error
+			if err != nil {
+				return err
+			}
+			if inexact > 0 {
+				return fmt.Errorf("%s.%s has %d values with fractions of a cent; fix them before migrating", table, column, inexact)
+			}
+
+			err = tx.Exec(fmt.Sprintf("UPDATE %[1]s SET %[2]s = CAST(ROUND(%[2]s * 100) AS INTEGER) WHERE %[2]s IS NOT NULL", table, column)).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// partialAccessKeyIndex drops the unique index on invoices.access_key so AutoMigrate recreates it
+// without the empty keys of pending invoices
+func partialAccessKeyIndex(tx *gorm.DB) error {
+	return tx.Exec("DROP INDEX IF EXISTS idx_invoices_access_key").Error
+}
@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultCountry is the country addresses are in unless they say otherwise. Only its
+// addresses are checked against CEP and UF rules, and only its orders carry ICMS.
+const DefaultCountry = "Brasil"
+
+// BrazilianStates maps each UF (unidade federativa) to its full name
+var BrazilianStates = map[string]string{
+	"AC": "Acre",
+	"AL": "Alagoas",
+	"AP": "Amapá",
+	"AM": "Amazonas",
+	"BA": "Bahia",
+	"CE": "Ceará",
+	"DF": "Distrito Federal",
+	"ES": "Espírito Santo",
+	"GO": "Goiás",
+	"MA": "Maranhão",
+	"MT": "Mato Grosso",
+	"MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais",
+	"PA": "Pará",
+	"PB": "Paraíba",
+	"PR": "Paraná",
+	"PE": "Pernambuco",
+	"PI": "Piauí",
+	"RJ": "Rio de Janeiro",
+	"RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul",
+	"RO": "Rondônia",
+	"RR": "Roraima",
+	"SC": "Santa Catarina",
+	"SP": "São Paulo",
+	"SE": "Sergipe",
+	"TO": "Tocantins",
+}
+
+// IsValidState reports whether uf is a known Brazilian state code
+func IsValidState(uf string) bool {
+	_, ok := BrazilianStates[strings.ToUpper(strings.TrimSpace(uf))]
+	return ok
+}
+
+// OnlyDigits strips every non-digit character from s
+func OnlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// NormalizePostalCode returns the CEP as 8 digits, or "" when it is malformed.
+// Accepts the usual "01310-100", "01310100" and "01.310-100" spellings.
+func NormalizePostalCode(cep string) string {
+	trimmed := strings.TrimSpace(cep)
+	for _, r := range trimmed {
+		if !unicode.IsDigit(r) && r != '-' && r != '.' && r != ' ' {
+			return ""
+		}
+	}
+
+	digits := OnlyDigits(trimmed)
+	if len(digits) != 8 || digits == "00000000" {
+		return ""
+	}
+	return digits
+}
+
+// FormatPostalCode formats an 8-digit CEP as "00000-000"
+func FormatPostalCode(digits string) string {
+	if len(digits) != 8 {
+		return digits
+	}
+	return digits[:5] + "-" + digits[5:]
+}
+
+// Normalize trims every field and canonicalizes CEP, UF and country in place.
+// Malformed values are left as typed so Validate can report them.
+func (sa *ShippingAddress) Normalize() {
+	sa.Street = collapseSpaces(sa.Street)
+	sa.Number = collapseSpaces(sa.Number)
+	sa.Complement = collapseSpaces(sa.Complement)
+	sa.Neighborhood = collapseSpaces(sa.Neighborhood)
+	sa.City = collapseSpaces(sa.City)
+	sa.Recipient = collapseSpaces(sa.Recipient)
+	sa.State = strings.ToUpper(strings.TrimSpace(sa.State))
+	sa.Phone = strings.TrimSpace(sa.Phone)
+
+	if digits := NormalizePostalCode(sa.PostalCode); digits != "" {
+		sa.PostalCode = FormatPostalCode(digits)
+	} else {
+		sa.PostalCode = strings.TrimSpace(sa.PostalCode)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(sa.Country)) {
+	case "", "br", "bra", "brasil", "brazil":
+		sa.Country = DefaultCountry
+	default:
+		sa.Country = strings.TrimSpace(sa.Country)
+	}
+}
+
+// IsDomestic reports whether the address is in Brazil. Call Normalize first.
+func (sa *ShippingAddress) IsDomestic() bool {
+	return sa.Country == "" || sa.Country == DefaultCountry
+}
+
+// Validate checks required fields, and the CEP format and the UF of Brazilian addresses.
+// Foreign addresses need no neighborhood or state and take any postal code format.
+// Field keys use the JSON names of ShippingAddress.
+func (sa *ShippingAddress) Validate() *ValidationError {
+	v := NewValidationError()
+	if !sa.IsDomestic() {
+		sa.validateForeign(v)
+		return v
+	}
+
+	required := []struct {
+		field string
+		value string
+	}{
+		{"recipient", sa.Recipient},
+		{"street", sa.Street},
+		{"number", sa.Number},
+		{"neighborhood", sa.Neighborhood},
+		{"city", sa.City},
+		{"state", sa.State},
+		{"postalCode", sa.PostalCode},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			v.Add(r.field, "is required")
+		}
+	}
+
+	if sa.PostalCode != "" && NormalizePostalCode(sa.PostalCode) == "" {
+		v.Add("postalCode", "must be a valid 8-digit CEP")
+	}
+	if sa.State != "" && !IsValidState(sa.State) {
+		v.Add("state", "must be a valid UF (e.g. SP, RJ, MG)")
+	}
+	if sa.Phone != "" {
+		if digits := OnlyDigits(sa.Phone); len(digits) < 10 || len(digits) > 13 {
+			v.Add("phone", "must have a valid area code and number")
+		}
+	}
+
+	return v
+}
+
+// validateForeign checks an address outside Brazil
+func (sa *ShippingAddress) validateForeign(v *ValidationError) {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"recipient", sa.Recipient},
+		{"street", sa.Street},
+		{"city", sa.City},
+		{"postalCode", sa.PostalCode},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			v.Add(r.field, "is required")
+		}
+	}
+
+	if len(sa.PostalCode) > 12 {
+		v.Add("postalCode", "must have at most 12 characters")
+	}
+	for _, r := range sa.PostalCode {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != ' ' {
+			v.Add("postalCode", "may only contain letters, digits, spaces and hyphens")
+			break
+		}
+	}
+	if len(sa.Country) > 100 {
+		v.Add("country", "must have at most 100 characters")
+	}
+	if sa.Phone != "" {
+		// E.164: up to 15 digits with the country code
+		if digits := OnlyDigits(sa.Phone); len(digits) < 7 || len(digits) > 15 {
+			v.Add("phone", "must be a valid international number")
+		}
+	}
+}
+
+// collapseSpaces trims s and collapses inner whitespace runs into single spaces
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,111 @@
+package domain
+
+import "strings"
+
+// Order statuses
+const (
+	OrderPending   = "pending"
+	OrderConfirmed = "confirmed"
+	OrderShipped   = "shipped"
+	OrderDelivered = "delivered"
+	OrderCancelled = "cancelled"
+)
+
+// IsValidOrderStatus reports whether status is one of the Order* constants
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case OrderPending, OrderConfirmed, OrderShipped, OrderDelivered, OrderCancelled:
+		return true
+	}
+	return false
+}
+
+// UserFilter narrows the admin user list; empty fields match everything
+type UserFilter struct {
+	Query  string // Substring of the name or email
+	Role   string
+	Status string // "active" or "suspended"
+}
+
+// ProductFilter narrows the admin product list; empty fields match everything
+type ProductFilter struct {
+	Query    string // Substring of the name, slug or SKU
+	SellerID string
+	Active   *bool
+}
+
+// OrderFilter narrows the admin order list; empty fields match everything
+type OrderFilter struct {
+	Query  string // Order number prefix
+	Status string
+	UserID string
+}
+
+// SearchPattern turns a free-text query into a case-insensitive LIKE pattern
+func SearchPattern(query string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(strings.TrimSpace(query)))
+	return "%" + escaped + "%"
+}
+
+// Audited admin actions on accounts, recorded with the admin as actor
+const (
+	AuditUserSuspended   = "user.suspended"
+	AuditUserReactivated = "user.reactivated"
+	AuditRoleChanged     = "role.changed"
+)
+
+// SuspendUserRequest is the request body for suspending an account
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// ChangeRoleRequest is the request body for changing a user's role
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// ModerateProductRequest is the request body for moderating a product; omitted fields are kept
+type ModerateProductRequest struct {
+	IsActive   *bool `json:"is_active"`
+	IsFeatured *bool `json:"is_featured"`
+}
+
+// AdminUpdateOrderRequest is the request body for acting on an order; omitted fields are kept
+type AdminUpdateOrderRequest struct {
+	Status         *string `json:"status"`
+	TrackingNumber *string `json:"tracking_number" binding:"omitempty,max=100"`
+	Notes          *string `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// PlatformMetrics are the platform-wide numbers shown on the admin dashboard
+type PlatformMetrics struct {
+	Users                     UserMetrics       `json:"users"`
+	Products                  ProductMetrics    `json:"products"`
+	Orders                    OrderMetrics      `json:"orders"`
+	Revenue                   []CurrencyRevenue `json:"revenue"` // Cancelled orders excluded
+	Stores                    int64             `json:"stores"`
+	PendingSellerApplications int64             `json:"pending_seller_applications"`
+}
+
+// UserMetrics counts accounts by role and state
+type UserMetrics struct {
+	Total      int64            `json:"total"`
+	ByRole     map[string]int64 `json:"by_role"`
+	Suspended  int64            `json:"suspended"`
+	NewLast30d int64            `json:"new_last_30d"`
+	Unverified int64            `json:"unverified"`
+}
+
+// ProductMetrics counts catalog entries
+type ProductMetrics struct {
+	Total      int64 `json:"total"`
+	Active     int64 `json:"active"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+// OrderMetrics counts orders by status
+type OrderMetrics struct {
+	Total    int64            `json:"total"`
+	ByStatus map[string]int64 `json:"by_status"`
+	Last30d  int64            `json:"last_30d"`
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxAPIKeysPerUser caps the active (not revoked, not expired) keys of a seller
+const MaxAPIKeysPerUser = 10
+
+// APIKeyPrefix starts every key, so leaked keys are easy to recognise in logs and scanners
+const APIKeyPrefix = "ek_"
+
+// Audited API key changes
+const (
+	AuditAPIKeyCreated = "apikey.created"
+	AuditAPIKeyRevoked = "apikey.revoked"
+)
+
+// APIKeyScopes are the permissions a key may carry. Managing keys is not among them:
+// a key cannot create or revoke keys.
+var APIKeyScopes = []Permission{PermProductWrite, PermOrderFulfil, PermStoreManage, PermStoreAnalytics}
+
+// IsAPIKeyScope reports whether perm may be granted to an API key
+func IsAPIKeyScope(perm Permission) bool {
+	for _, scope := range APIKeyScopes {
+		if scope == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey lets a seller's integration (e.g. an ERP syncing inventory) call the seller API without
+// an interactive login. The key is "ek_<prefix>_<secret>": the prefix identifies it and is shown
+// in listings, and only the SHA-256 of the whole key is stored, so it is shown once at creation.
+// A request made with a key needs both the user's role and the key to grant the permission.
+type APIKey struct {
+	ID         string       `gorm:"type:text;primaryKey" json:"id"`
+	UserID     string       `gorm:"type:text;index" json:"userId"` // camelCase
+	Name       string       `gorm:"size:100" json:"name"`
+	Prefix     string       `gorm:"size:32;uniqueIndex" json:"prefix"`
+	KeyHash    string       `gorm:"size:64" json:"-"`
+	Scopes     []Permission `gorm:"type:json;serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time   `json:"expiresAt"`                 // camelCase, nil never expires
+	LastUsedAt *time.Time   `json:"lastUsedAt"`                // camelCase, updated at most once a minute
+	LastUsedIP string       `gorm:"size:45" json:"lastUsedIp"` // camelCase
+	RevokedAt  *time.Time   `json:"revokedAt"`                 // camelCase
+	CreatedAt  time.Time    `json:"createdAt"`                 // camelCase
+}
+
+// TableName sets the table name for APIKey
+func (k *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsActive reports whether the key can still be used
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope reports whether the key was granted perm. Analytics used to be part of
+// store:manage, so keys with that scope keep reading them.
+func (k *APIKey) HasScope(perm Permission) bool {
+	for _, scope := range k.Scopes {
+		if scope == perm || (scope == PermStoreManage && perm == PermStoreAnalytics) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest is the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string       `json:"name" binding:"required,max=100"`
+	Scopes    []Permission `json:"scopes" binding:"required,min=1"` // e.g. ["product:write"]
+	ExpiresAt *time.Time   `json:"expires_at"`                      // Optional, RFC 3339; omit for a key that never expires
+}
+
+// CreatedAPIKey is returned once, when a key is created; Key cannot be retrieved again
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
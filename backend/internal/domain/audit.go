@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audited account changes
+const (
+	AuditProfileUpdated       = "profile.updated"
+	AuditPasswordChanged      = "password.changed"
+	AuditEmailChangeRequested = "email.change_requested" // A link was sent to the new address
+	AuditEmailChanged         = "email.changed"
+	AuditLoginFailed          = "login.failed" // Wrong password or two-factor code, with the IP it came from
+	AuditLoginLocked          = "login.locked" // Too many failures, logins are refused for a while
+)
+
+// AuditLog records a change made to an account, by its owner or by an admin
+type AuditLog struct {
+	ID        string            `gorm:"type:text;primaryKey" json:"id"`
+	UserID    string            `gorm:"type:text;index" json:"userId"` // camelCase, the account that changed
+	ActorID   string            `gorm:"type:text" json:"actorId"`      // camelCase, who made the change
+	Action    string            `gorm:"size:50;index" json:"action"`
+	Details   map[string]string `gorm:"type:json;serializer:json" json:"details,omitempty"`
+	IPAddress string            `gorm:"size:45" json:"ipAddress"`  // camelCase
+	UserAgent string            `gorm:"size:255" json:"userAgent"` // camelCase
+	CreatedAt time.Time         `json:"createdAt"`                 // camelCase
+}
+
+// TableName sets the table name for AuditLog
+func (al *AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (al *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if al.ID == "" {
+		al.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// UpdateProfileRequest is the request body for editing one's profile; omitted fields are kept.
+// An empty avatar_url removes the avatar.
+type UpdateProfileRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=2,max=255"`
+	AvatarURL *string `json:"avatar_url" binding:"omitempty,max=255"`
+}
+
+// ChangePasswordRequest is the request body for changing one's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangeEmailRequest is the request body for changing one's email address
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
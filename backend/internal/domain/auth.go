@@ -1,9 +1,16 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
 // LoginRequest is the request body for login
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6"`
+	DeviceName string `json:"device_name" binding:"max=100"` // Optional, shown in the session list
 }
 
 // RegisterRequest is the request body for registration
@@ -13,17 +20,55 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
-// LoginResponse is the response after successful login
+// LoginResponse is the response after successful login or token refresh
 type LoginResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token            string        `json:"token"`            // Short-lived access token
+	ExpiresAt        time.Time     `json:"expiresAt"`        // Access token expiry
+	RefreshToken     string        `json:"refreshToken"`     // Single use, exchange at /api/auth/refresh
+	RefreshExpiresAt time.Time     `json:"refreshExpiresAt"` // camelCase
+	User             *UserResponse `json:"user"`
+}
+
+// AccessClaims are the claims of an access token: sub is the user ID and jti the Session ID.
+// Email and role let other services act on a token without calling the API; the API itself
+// reloads the user on every request, so they are informative only and may be stale.
+// Act is set on impersonation tokens only.
+type AccessClaims struct {
+	Email string      `json:"email"`
+	Role  string      `json:"role"`
+	Act   *ActorClaim `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IsImpersonation reports whether an admin is acting as the subject
+func (c *AccessClaims) IsImpersonation() bool {
+	return c.Act != nil
+}
+
+// LoginChallenge is returned by login instead of a session when the account has two-factor
+// authentication; the session is issued by /api/auth/login/2fa
+type LoginChallenge struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"` // Always true, lets clients tell it from a LoginResponse
+	ChallengeToken    string    `json:"challengeToken"`    // Single use, sent back with the code
+	ExpiresAt         time.Time `json:"expiresAt"`
+}
+
+// TwoFactorLoginRequest is the request body for completing a login with a TOTP or recovery code
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+	DeviceName     string `json:"device_name" binding:"max=100"` // Optional, shown in the session list
 }
 
 // CreateOrderRequest is the request body for creating an order
 type CreateOrderRequest struct {
-	Items           []OrderItemInput `json:"items" binding:"required,min=1"`
-	ShippingAddress ShippingAddress  `json:"shipping_address" binding:"required"`
-	PaymentMethod   string           `json:"payment_method" binding:"required"`
+	Items            []OrderItemInput `json:"items" binding:"required,min=1"`
+	AddressID        string           `json:"address_id"`       // Saved address to ship to, or
+	ShippingAddress  *ShippingAddress `json:"shipping_address"` // an address typed at checkout
+	PaymentMethod    string           `json:"payment_method" binding:"required"`
+	CustomerDocument string           `json:"customer_document"` // Optional CPF/CNPJ printed on the NF-e
+	ShippingMethod   string           `json:"shipping_method"`   // Optional, defaults to the cheapest option
+	Currency         string           `json:"currency"`          // Optional, defaults to the storefront currency
 }
 
 // OrderItemInput represents a cart item when creating an order
@@ -42,10 +87,9 @@ type AnalyticsRequest struct {
 
 // AnalyticsResponse for seller analytics
 type AnalyticsResponse struct {
-	TotalOrders       int64   `json:"total_orders"`
-	TotalRevenue      float64 `json:"total_revenue"`
-	AverageOrderValue float64 `json:"average_order_value"`
-	TotalProducts     int64   `json:"total_products"`
-	TotalViews        int64   `json:"total_views"`
-	ConversionRate    float64 `json:"conversion_rate"`
+	TotalOrders    int64             `json:"total_orders"`
+	Revenue        []CurrencyRevenue `json:"revenue"` // One entry per order currency, amounts are never summed across currencies
+	TotalProducts  int64             `json:"total_products"`
+	TotalViews     int64             `json:"total_views"`
+	ConversionRate float64           `json:"conversion_rate"`
 }
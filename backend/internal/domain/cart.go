@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cart item availability flags returned on cart reads
+const (
+	CartIssueUnavailable       = "unavailable"          // Product deleted or deactivated
+	CartIssueOutOfStock        = "out_of_stock"         // No units left
+	CartIssueInsufficientStock = "insufficient_stock"   // Fewer units left than requested
+	CartIssueNotSoldInCurrency = "not_sold_in_currency" // No list price in the storefront currency
+)
+
+// Cart is a shopping cart owned either by a user or by a guest token
+type Cart struct {
+	ID        string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID    *string    `gorm:"type:text;uniqueIndex" json:"userId"` // Set for logged-in customers
+	Token     *string    `gorm:"size:64;uniqueIndex" json:"-"`        // Set for guest carts, sent back as X-Cart-Token
+	ExpiresAt *time.Time `json:"expiresAt"`                           // Guest carts expire, user carts do not
+	Items     []CartItem `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt time.Time  `json:"createdAt"` // camelCase
+	UpdatedAt time.Time  `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for Cart
+func (c *Cart) TableName() string {
+	return "carts"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (c *Cart) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// CartItem is one line of a cart
+type CartItem struct {
+	ID         string    `gorm:"type:text;primaryKey" json:"id"`
+	CartID     string    `gorm:"type:text;index" json:"cartId"` // camelCase
+	ProductID  string    `gorm:"type:text" json:"productId"`    // camelCase
+	Quantity   int       `json:"quantity"`
+	Color      *string   `gorm:"size:100" json:"color,omitempty"`
+	Size       *string   `gorm:"size:50" json:"size,omitempty"`
+	PriceAtAdd Money     `json:"priceAtAdd"` // Product price (in the product's currency) when the item was added
+	Product    *Product  `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"product,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"` // camelCase
+	UpdatedAt  time.Time `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for CartItem
+func (ci *CartItem) TableName() string {
+	return "cart_items"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (ci *CartItem) BeforeCreate(tx *gorm.DB) error {
+	if ci.ID == "" {
+		ci.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// SameVariant reports whether two lines are the same product in the same color and size
+func (ci *CartItem) SameVariant(productID string, color, size *string) bool {
+	return ci.ProductID == productID && equalOptional(ci.Color, color) && equalOptional(ci.Size, size)
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// AddCartItemRequest is the request body for adding a product to the cart
+type AddCartItemRequest struct {
+	ProductID string  `json:"product_id" binding:"required,uuid"`
+	Quantity  int     `json:"quantity" binding:"required,min=1"`
+	Color     *string `json:"color"`
+	Size      *string `json:"size"`
+}
+
+// UpdateCartItemRequest is the request body for changing a line's quantity
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// CartResponse is the DTO returned to frontend, priced against current product data
+type CartResponse struct {
+	ID        string             `json:"id,omitempty"`
+	Token     string             `json:"token,omitempty"` // Guest cart token, empty for user carts
+	Currency  string             `json:"currency"`        // Storefront currency the cart is priced in
+	Items     []CartItemResponse `json:"items"`
+	ItemCount int                `json:"itemCount"` // Units that can be checked out
+	Subtotal  Money              `json:"subtotal"`  // Current price of the items that can be checked out
+	HasIssues bool               `json:"hasIssues"` // True when any line is flagged
+	UpdatedAt *time.Time         `json:"updatedAt,omitempty"`
+}
+
+// CartItemResponse is the DTO for cart lines
+type CartItemResponse struct {
+	ID                string  `json:"id"`
+	ProductID         string  `json:"productId"`
+	ProductName       string  `json:"productName,omitempty"`
+	Quantity          int     `json:"quantity"`
+	Color             *string `json:"color,omitempty"`
+	Size              *string `json:"size,omitempty"`
+	UnitPrice         Money   `json:"unitPrice"`         // Current list price in the cart currency
+	PriceChanged      bool    `json:"priceChanged"`      // The product price changed since the item was added
+	LineTotal         Money   `json:"lineTotal"`         // UnitPrice * Quantity
+	Available         bool    `json:"available"`         // Can be checked out as is
+	AvailableQuantity int     `json:"availableQuantity"` // Units in stock
+	Issue             string  `json:"issue,omitempty"`   // CartIssue* when not available
+}
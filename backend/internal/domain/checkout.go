@@ -0,0 +1,37 @@
+package domain
+
+// ShippingOption is one delivery method offered for an order
+type ShippingOption struct {
+	Method        string `json:"method"` // e.g. "standard", "express"
+	Name          string `json:"name"`
+	Price         Money  `json:"price"`
+	EstimatedDays int    `json:"estimatedDays"` // Business days after dispatch
+}
+
+// CheckoutQuote is the priced preview of a CreateOrderRequest.
+// It is produced by the same pipeline as CreateOrder, so a valid quote matches the order that would be placed.
+type CheckoutQuote struct {
+	Currency        string            `json:"currency"` // Every amount of the quote is in this currency
+	Lines           []QuoteLine       `json:"lines"`
+	Subtotal        Money             `json:"subtotal"`
+	DiscountAmount  Money             `json:"discountAmount"`
+	ShippingOptions []ShippingOption  `json:"shippingOptions"`
+	ShippingMethod  string            `json:"shippingMethod,omitempty"` // Selected (or cheapest) option
+	ShippingFee     Money             `json:"shippingFee"`
+	TaxAmount       Money             `json:"taxAmount"` // Contained in the prices, not added to the total
+	Total           Money             `json:"total"`
+	Valid           bool              `json:"valid"`              // False when the order would be rejected
+	Problems        map[string]string `json:"problems,omitempty"` // Same keys as the order's validation error
+}
+
+// QuoteLine is one priced line of a quote
+type QuoteLine struct {
+	ProductID   string        `json:"productId"`
+	ProductName string        `json:"productName,omitempty"`
+	Quantity    int           `json:"quantity"`
+	UnitPrice   Money         `json:"unitPrice"`
+	LineTotal   Money         `json:"lineTotal"`
+	Color       *string       `json:"color,omitempty"`
+	Size        *string       `json:"size,omitempty"`
+	Tax         *TaxBreakdown `json:"tax,omitempty"`
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// supportedCurrencies are the ISO 4217 codes the storefront sells in
+var supportedCurrencies = []string{"BRL", "EUR"}
+
+// SupportedCurrencies lists the currencies the storefront sells in, DefaultCurrency first
+func SupportedCurrencies() []string {
+	return append([]string(nil), supportedCurrencies...)
+}
+
+// IsSupportedCurrency reports whether orders can be placed in the currency
+func IsSupportedCurrency(code string) bool {
+	for _, c := range supportedCurrencies {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCurrency uppercases and trims an ISO 4217 code ("eur " -> "EUR")
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ProductPrice is a product's list price in a currency other than its own.
+// Products without an entry for a currency cannot be ordered in it.
+type ProductPrice struct {
+	ID             string    `gorm:"type:text;primaryKey" json:"id"`
+	ProductID      string    `gorm:"type:text;uniqueIndex:idx_product_prices_currency" json:"productId"` // camelCase
+	Currency       string    `gorm:"size:3;uniqueIndex:idx_product_prices_currency" json:"currency"`
+	Price          Money     `json:"price"`
+	CompareAtPrice *Money    `json:"compareAtPrice"` // camelCase
+	CreatedAt      time.Time `json:"createdAt"`      // camelCase
+	UpdatedAt      time.Time `json:"updatedAt"`      // camelCase
+}
+
+// TableName sets the table name for ProductPrice
+func (pp *ProductPrice) TableName() string {
+	return "product_prices"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (pp *ProductPrice) BeforeCreate(tx *gorm.DB) error {
+	if pp.ID == "" {
+		pp.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// AfterFind tags the loaded amounts with the row's currency
+func (pp *ProductPrice) AfterFind(tx *gorm.DB) error {
+	pp.Price.Currency = pp.Currency
+	if pp.CompareAtPrice != nil {
+		pp.CompareAtPrice.Currency = pp.Currency
+	}
+	return nil
+}
+
+// ExchangeRate converts amounts from Base to Quote: 1 Base = Rate Quote.
+// Rates are only used to display prices; orders are priced from list prices.
+type ExchangeRate struct {
+	ID        string    `gorm:"type:text;primaryKey" json:"id"`
+	Base      string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair" json:"base"`
+	Quote     string    `gorm:"size:3;uniqueIndex:idx_exchange_rates_pair" json:"quote"`
+	Rate      float64   `json:"rate"`
+	CreatedAt time.Time `json:"createdAt"` // camelCase
+	UpdatedAt time.Time `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for ExchangeRate
+func (er *ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (er *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	if er.ID == "" {
+		er.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// ProductPriceInput is one entry of a seller's price list
+type ProductPriceInput struct {
+	Currency       string `json:"currency" binding:"required,len=3"`
+	Price          Money  `json:"price"`
+	CompareAtPrice *Money `json:"compare_at_price"`
+}
+
+// SetProductPricesRequest replaces a product's price list.
+// Currencies missing from the request are no longer sold.
+type SetProductPricesRequest struct {
+	Prices []ProductPriceInput `json:"prices" binding:"dive"`
+}
+
+// CurrencyRevenue is a seller's revenue in one currency
+type CurrencyRevenue struct {
+	Currency          string `json:"currency"`
+	TotalOrders       int64  `json:"total_orders"`
+	TotalRevenue      Money  `json:"total_revenue"`
+	AverageOrderValue Money  `json:"average_order_value"`
+}
+
+// CurrenciesResponse lists the storefront currencies and the display rates from DefaultCurrency
+type CurrenciesResponse struct {
+	Default   string             `json:"default"`
+	Supported []string           `json:"supported"`
+	Rates     map[string]float64 `json:"rates"` // 1 DefaultCurrency in each currency with a known rate
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "strings"
+
+// IsValidCPF checks an 11-digit CPF (punctuation allowed) including both check digits
+func IsValidCPF(cpf string) bool {
+	digits := OnlyDigits(cpf)
+	if len(digits) != 11 || allSameDigit(digits) {
+		return false
+	}
+	return checkDigit(digits[:9], 10) == digits[9] && checkDigit(digits[:10], 11) == digits[10]
+}
+
+// IsValidCNPJ checks a 14-digit CNPJ (punctuation allowed) including both check digits
+func IsValidCNPJ(cnpj string) bool {
+	digits := OnlyDigits(cnpj)
+	if len(digits) != 14 || allSameDigit(digits) {
+		return false
+	}
+	return cnpjCheckDigit(digits[:12]) == digits[12] && cnpjCheckDigit(digits[:13]) == digits[13]
+}
+
+// NormalizeTaxDocument returns the digits of a valid CPF or CNPJ, or "" when invalid
+func NormalizeTaxDocument(document string) string {
+	digits := OnlyDigits(document)
+	if IsValidCPF(digits) || IsValidCNPJ(digits) {
+		return digits
+	}
+	return ""
+}
+
+// checkDigit computes a CPF check digit with weights counting down from firstWeight
+func checkDigit(digits string, firstWeight int) byte {
+	sum := 0
+	for i, d := range digits {
+		sum += int(d-'0') * (firstWeight - i)
+	}
+	return mod11Digit(sum)
+}
+
+// cnpjCheckDigit computes a CNPJ check digit (weights 2..9 cycling from the right)
+func cnpjCheckDigit(digits string) byte {
+	sum, weight := 0, 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	return mod11Digit(sum)
+}
+
+// mod11Digit turns a weighted sum into a modulo-11 check digit character
+func mod11Digit(sum int) byte {
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - rest)
+}
+
+func allSameDigit(digits string) bool {
+	return strings.Count(digits, digits[:1]) == len(digits)
+}
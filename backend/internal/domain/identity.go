@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audited social login events
+const (
+	AuditIdentityLinked = "identity.linked"
+)
+
+// UserIdentity links an account at an external OIDC provider to a User.
+// Provider and Subject (the provider's stable account ID) identify it; the email is
+// only what the provider reported when the identity was linked.
+type UserIdentity struct {
+	ID          string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID      string     `gorm:"type:text;index" json:"userId"` // camelCase
+	Provider    string     `gorm:"size:50;uniqueIndex:idx_user_identities_provider_subject" json:"provider"`
+	Subject     string     `gorm:"size:255;uniqueIndex:idx_user_identities_provider_subject" json:"-"`
+	Email       string     `gorm:"size:255" json:"email"`
+	LastLoginAt *time.Time `json:"lastLoginAt"` // camelCase
+	CreatedAt   time.Time  `json:"createdAt"`   // camelCase
+}
+
+// TableName sets the table name for UserIdentity
+func (ui *UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (ui *UserIdentity) BeforeCreate(tx *gorm.DB) error {
+	if ui.ID == "" {
+		ui.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// OIDCLoginState is a pending authorization request, created when the browser is sent to the
+// provider and consumed by the callback. Only the SHA-256 of the state is stored; the nonce and
+// PKCE verifier never leave the server.
+type OIDCLoginState struct {
+	ID           string    `gorm:"type:text;primaryKey"`
+	Provider     string    `gorm:"size:50"`
+	StateHash    string    `gorm:"size:64;uniqueIndex"`
+	Nonce        string    `gorm:"size:64"`
+	CodeVerifier string    `gorm:"size:64"`
+	ExpiresAt    time.Time `gorm:"index"`
+	CreatedAt    time.Time
+}
+
+// TableName sets the table name for OIDCLoginState
+func (s *OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (s *OIDCLoginState) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// OIDCAuthorization is returned when a social login starts: the client sends the browser to
+// AuthorizationURL, and the provider redirects back to the frontend with code and state
+type OIDCAuthorization struct {
+	Provider         string    `json:"provider"`
+	AuthorizationURL string    `json:"authorizationUrl"` // camelCase
+	ExpiresAt        time.Time `json:"expiresAt"`        // camelCase, the callback must arrive before this
+}
+
+// OIDCCallbackRequest is the request body carrying the provider's redirect parameters
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
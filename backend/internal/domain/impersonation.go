@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Audited impersonation events, recorded on the impersonated account with the admin as actor
+const (
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonatedRequest  = "impersonation.request" // One per request made with an impersonation token
+)
+
+// ActorClaim is the "act" claim (RFC 8693) of an impersonation token: the admin acting as the subject
+type ActorClaim struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+}
+
+// ImpersonateRequest is the request body for impersonating a user
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"` // Recorded in the user's audit trail
+}
+
+// ImpersonationResponse is the short-lived token letting an admin act as a user.
+// There is no refresh token: once it expires the admin starts again.
+type ImpersonationResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt time.Time     `json:"expiresAt"` // camelCase
+	User      *UserResponse `json:"user"`
+}
+
+// ImpersonationState tells the frontend to show the impersonation banner
+type ImpersonationState struct {
+	ActorID    string    `json:"actorId"`    // camelCase
+	ActorEmail string    `json:"actorEmail"` // camelCase
+	ExpiresAt  time.Time `json:"expiresAt"`  // camelCase
+}
+
+// MeResponse is the current user, with the impersonation state when an admin is acting as them
+type MeResponse struct {
+	*UserResponse
+	Impersonation *ImpersonationState `json:"impersonation"` // null unless impersonating
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tax regimes (CRT - código de regime tributário)
+const (
+	TaxRegimeSimplesNacional = 1
+	TaxRegimeSimplesExcess   = 2 // Simples Nacional, excesso de sublimite de receita bruta
+	TaxRegimeNormal          = 3
+)
+
+// NF-e environments (tpAmb)
+const (
+	NFeEnvironmentProduction   = 1
+	NFeEnvironmentHomologation = 2
+)
+
+// Invoice statuses
+const (
+	InvoiceStatusPending    = "pending" // Number reserved, waiting for SEFAZ
+	InvoiceStatusAuthorized = "authorized"
+	InvoiceStatusRejected   = "rejected"
+)
+
+// FiscalProfile holds the seller data printed as the NF-e issuer (emitente)
+type FiscalProfile struct {
+	ID                string    `gorm:"type:text;primaryKey" json:"id"`
+	SellerID          string    `gorm:"type:text;uniqueIndex" json:"sellerId"`
+	CNPJ              string    `gorm:"size:14" json:"cnpj"`
+	StateRegistration string    `gorm:"size:14" json:"stateRegistration"` // Inscrição estadual
+	LegalName         string    `gorm:"size:60" json:"legalName"`         // Razão social
+	TradeName         string    `gorm:"size:60" json:"tradeName"`         // Nome fantasia
+	TaxRegime         int       `gorm:"default:1" json:"taxRegime"`       // TaxRegime* (CRT)
+	Street            string    `gorm:"size:60" json:"street"`
+	Number            string    `gorm:"size:60" json:"number"`
+	Complement        string    `gorm:"size:60" json:"complement"`
+	Neighborhood      string    `gorm:"size:60" json:"neighborhood"`
+	City              string    `gorm:"size:60" json:"city"`
+	CityCode          string    `gorm:"size:7" json:"cityCode"` // IBGE municipality code
+	State             string    `gorm:"size:2" json:"state"`
+	PostalCode        string    `gorm:"size:8" json:"postalCode"`
+	Phone             string    `gorm:"size:14" json:"phone"`
+	Series            int       `gorm:"default:1" json:"series"`
+	NextNumber        int       `gorm:"default:1" json:"nextNumber"`
+	Environment       int       `gorm:"default:2" json:"environment"` // NFeEnvironment*
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// TableName sets the table name for FiscalProfile
+func (f *FiscalProfile) TableName() string {
+	return "fiscal_profiles"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (f *FiscalProfile) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// FiscalProfileRequest is the request body for saving a seller's fiscal data
+type FiscalProfileRequest struct {
+	CNPJ              string `json:"cnpj" binding:"required"`
+	StateRegistration string `json:"state_registration" binding:"required"`
+	LegalName         string `json:"legal_name" binding:"required,max=60"`
+	TradeName         string `json:"trade_name" binding:"max=60"`
+	TaxRegime         int    `json:"tax_regime" binding:"required,oneof=1 2 3"`
+	Street            string `json:"street" binding:"required"`
+	Number            string `json:"number" binding:"required"`
+	Complement        string `json:"complement"`
+	Neighborhood      string `json:"neighborhood" binding:"required"`
+	City              string `json:"city" binding:"required"`
+	CityCode          string `json:"city_code" binding:"required,len=7,numeric"`
+	State             string `json:"state" binding:"required,len=2"`
+	PostalCode        string `json:"postal_code" binding:"required"`
+	Phone             string `json:"phone"`
+	Series            int    `json:"series" binding:"min=0,max=889"`
+	Environment       int    `json:"environment" binding:"omitempty,oneof=1 2"`
+}
+
+// Invoice is an NF-e issued by a seller for its items of an order.
+// A seller has at most one pending or authorized invoice per order; rejected ones are kept as history.
+type Invoice struct {
+	ID            string     `gorm:"type:text;primaryKey" json:"id"`
+	OrderID       string     `gorm:"type:text;index:idx_invoice_order_seller;uniqueIndex:idx_invoices_open,where:status <> 'rejected'" json:"orderId"`
+	SellerID      string     `gorm:"type:text;index:idx_invoice_order_seller;uniqueIndex:idx_invoices_open,where:status <> 'rejected'" json:"sellerId"`
+	Number        int        `json:"number"`
+	Series        int        `json:"series"`
+	AccessKey     string     `gorm:"size:44;uniqueIndex:idx_invoices_access_key,where:access_key <> ''" json:"accessKey"` // Chave de acesso, empty until generated
+	Environment   int        `json:"environment"`
+	Status        string     `gorm:"size:20" json:"status"` // InvoiceStatus*
+	Signed        bool       `json:"signed"`
+	Protocol      *string    `gorm:"size:20" json:"protocol"`
+	StatusCode    int        `json:"statusCode"` // cStat returned by SEFAZ
+	StatusMessage string     `gorm:"size:255" json:"statusMessage"`
+	TotalAmount   Money      `json:"total"`
+	XML           string     `json:"-"` // Signed NFe (wrapped in nfeProc once authorized)
+	IssuedAt      time.Time  `json:"issuedAt"`
+	AuthorizedAt  *time.Time `json:"authorizedAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName sets the table name for Invoice
+func (i *Invoice) TableName() string {
+	return "invoices"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.NewString()
+	}
+	return nil
+}
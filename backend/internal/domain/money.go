@@ -0,0 +1,345 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the ISO 4217 code of amounts that do not state one
+const DefaultCurrency = "BRL"
+
+var (
+	// ErrCurrencyMismatch is returned when combining amounts in different currencies
+	ErrCurrencyMismatch = errors.New("money: cannot combine amounts in different currencies")
+	// ErrZeroDenominator is returned when dividing an amount by zero
+	ErrZeroDenominator = errors.New("money: division by zero")
+	// ErrNegativeWeight is returned when allocating an amount with a negative weight
+	ErrNegativeWeight = errors.New("money: allocation weights cannot be negative")
+)
+
+// Money is an amount in integer minor units (centavos) of a currency.
+//
+// Rounding rules: arithmetic on Money is exact; only MulRatio, Ratio, Percent and
+// Allocate produce fractions of a cent. MulRatio and Percent round half away
+// from zero, Allocate hands out the remainder cent by cent so the parts always
+// add up to the original amount.
+//
+// In JSON, Money is a decimal number in major units (1234.5 is R$ 1.234,50) and
+// parsing rejects sub-cent precision instead of rounding it away. In SQL it is
+// stored as a BIGINT of minor units; the currency lives in its own column.
+//
+// Add, Sub, Cmp and MulRatio panic on mixed currencies and zero denominators, which
+// are programming errors. Amounts and ratios that come from data go through TryAdd,
+// Ratio or SameCurrency first.
+type Money struct {
+	Amount   int64  // Minor units
+	Currency string // ISO 4217 code, empty is treated as compatible with any currency
+}
+
+// NewMoney creates an amount of minor units in the given currency
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Cents creates an amount of centavos in DefaultCurrency
+func Cents(amount int64) Money {
+	return Money{Amount: amount, Currency: DefaultCurrency}
+}
+
+// ParseMoney parses a decimal string in major units ("1234.5", "-0.99").
+// More than two decimal places is an error, never a rounding.
+func ParseMoney(s string, currency string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	if whole == "" || (hasFraction && fraction == "") {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+	if len(fraction) > 2 {
+		return Money{}, fmt.Errorf("money amount %q has more than two decimal places", s)
+	}
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid money amount %q", s)
+		}
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid money amount %q: %w", s, err)
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.mustMatch(other)}
+}
+
+// TryAdd returns m + other, or ErrCurrencyMismatch
+func (m Money) TryAdd(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return m.Add(other), nil
+}
+
+// SameCurrency reports whether m and other can be combined (an empty currency matches any)
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == "" || other.Currency == "" || m.Currency == other.Currency
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.mustMatch(other)}
+}
+
+// Mul returns m multiplied by a whole quantity
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// MulRatio returns m * numerator / denominator, rounded half away from zero.
+// The denominator must not be zero; use Ratio when it comes from data.
+func (m Money) MulRatio(numerator, denominator int64) Money {
+	if denominator == 0 {
+		panic(ErrZeroDenominator)
+	}
+	return Money{Amount: divRound(m.Amount*numerator, denominator), Currency: m.Currency}
+}
+
+// Ratio returns m * numerator / denominator like MulRatio, or ErrZeroDenominator
+func (m Money) Ratio(numerator, denominator int64) (Money, error) {
+	if denominator == 0 {
+		return Money{}, ErrZeroDenominator
+	}
+	return m.MulRatio(numerator, denominator), nil
+}
+
+// Percent returns rate percent of m (Percent(18) is 18%), rounded half away from zero.
+// Rates are taken with two decimal places (12.5%), which is how tax tables state them.
+func (m Money) Percent(rate float64) Money {
+	return m.MulRatio(int64(math.Round(rate*100)), 10000)
+}
+
+// Allocate splits m proportionally to weights. The parts add up to m exactly:
+// each part is truncated toward zero and the leftover cents go one by one to the
+// first parts with a weight, so a negative m gives negative parts. When all weights
+// are zero every part is zero; a negative weight is ErrNegativeWeight.
+func (m Money) Allocate(weights []int64) ([]Money, error) {
+	parts := make([]Money, len(weights))
+	var sum int64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, ErrNegativeWeight
+		}
+		sum += w
+	}
+	for i := range parts {
+		parts[i].Currency = m.Currency
+	}
+	if sum == 0 || m.Amount == 0 {
+		return parts, nil
+	}
+
+	sign, amount := int64(1), m.Amount
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+	remainder := amount
+	for i, w := range weights {
+		parts[i].Amount = amount * w / sum
+		remainder -= parts[i].Amount
+	}
+	for i := 0; remainder > 0; i = (i + 1) % len(parts) {
+		if weights[i] > 0 {
+			parts[i].Amount++
+			remainder--
+		}
+	}
+	for i := range parts {
+		parts[i].Amount *= sign
+	}
+	return parts, nil
+}
+
+// Convert returns m in another currency at rate units of to per unit of m,
+// rounded half away from zero. Rates are taken with six decimal places.
+func (m Money) Convert(to string, rate float64) Money {
+	converted := m.MulRatio(int64(math.Round(rate*1e6)), 1e6)
+	converted.Currency = to
+	return converted
+}
+
+// In returns m tagged with a currency, for amounts loaded without one
+func (m Money) In(currency string) Money {
+	return Money{Amount: m.Amount, Currency: currency}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1
+func (m Money) Cmp(other Money) int {
+	m.mustMatch(other)
+	switch {
+	case m.Amount < other.Amount:
+		return -1
+	case m.Amount > other.Amount:
+		return 1
+	}
+	return 0
+}
+
+// Equal reports whether both amount and currency match (an empty currency matches any)
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && (m.Currency == other.Currency || m.Currency == "" || other.Currency == "")
+}
+
+// Decimal formats the amount in major units with two decimals ("1234.50")
+func (m Money) Decimal() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// String formats the amount with its currency ("BRL 1234.50")
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Currency + " " + m.Decimal()
+}
+
+// MarshalJSON writes the amount as a JSON number in major units
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON reads a JSON number (or numeric string) in major units.
+// The currency is kept when already set, DefaultCurrency otherwise.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("invalid money amount %s", data)
+	}
+
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(number.String(), currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner, reading minor units. The currency defaults to
+// DefaultCurrency; entities with a currency column overwrite it after loading.
+func (m *Money) Scan(value interface{}) error {
+	m.Currency = DefaultCurrency
+	switch v := value.(type) {
+	case nil:
+		m.Amount = 0
+	case int64:
+		m.Amount = v
+	case float64:
+		if v != math.Trunc(v) {
+			return fmt.Errorf("money column holds a fractional amount of minor units: %v", v)
+		}
+		m.Amount = int64(v)
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", value)
+	}
+	return nil
+}
+
+func (m *Money) scanString(s string) error {
+	amount, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid money column value %q: %w", s, err)
+	}
+	m.Amount = amount
+	return nil
+}
+
+// Value implements driver.Valuer, writing minor units
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount, nil
+}
+
+// GormDataType stores Money as an integer column
+func (Money) GormDataType() string {
+	return "bigint"
+}
+
+// mustMatch returns the shared currency; mixing currencies is a programming error
+func (m Money) mustMatch(other Money) string {
+	switch {
+	case m.Currency == "":
+		return other.Currency
+	case other.Currency == "" || other.Currency == m.Currency:
+		return m.Currency
+	}
+	panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency))
+}
+
+// divRound divides rounding half away from zero
+func divRound(numerator, denominator int64) int64 {
+	quotient, remainder := numerator/denominator, numerator%denominator
+	if remainder == 0 {
+		return quotient
+	}
+	if abs(remainder)*2 >= abs(denominator) {
+		if (numerator < 0) != (denominator < 0) {
+			return quotient - 1
+		}
+		return quotient + 1
+	}
+	return quotient
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
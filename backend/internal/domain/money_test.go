@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"0", 0, false},
+		{"12", 1200, false},
+		{"12.5", 1250, false},
+		{"12.05", 1205, false},
+		{" 1234.50 ", 123450, false},
+		{"-3.10", -310, false},
+		{"0.01", 1, false},
+		{"12.345", 0, true},
+		{"12.", 0, true},
+		{".50", 0, true},
+		{"", 0, true},
+		{"1e3", 0, true},
+		{"1,50", 0, true},
+		{"+1.00", 0, true},
+		{"99999999999999999999", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMoney(tt.in, "BRL")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseMoney(%q) = %v, want an error", tt.in, got)
+				}
+				return
+			}
+			if err != nil || got.Amount != tt.want || got.Currency != "BRL" {
+				t.Errorf("ParseMoney(%q) = %v, %v; want BRL %d minor units", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivRoundHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		numerator, denominator, want int64
+	}{
+		{10, 2, 5},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{5, -2, -3},
+		{-5, -2, 3},
+		{4, 3, 1},
+		{5, 3, 2},
+		{-4, 3, -1},
+		{-5, 3, -2},
+		{1, 1000, 0},
+		{500, 1000, 1},
+		{499, 1000, 0},
+		{-500, 1000, -1},
+	}
+	for _, tt := range tests {
+		if got := divRound(tt.numerator, tt.denominator); got != tt.want {
+			t.Errorf("divRound(%d, %d) = %d, want %d", tt.numerator, tt.denominator, got, tt.want)
+		}
+	}
+}
+
+func TestRatio(t *testing.T) {
+	got, err := Cents(1000).Ratio(1, 3)
+	if err != nil || got.Amount != 333 {
+		t.Errorf("Ratio(1, 3) = %v, %v; want 3.33", got, err)
+	}
+	if _, err := Cents(1000).Ratio(1, 0); !errors.Is(err, ErrZeroDenominator) {
+		t.Errorf("Ratio(1, 0) error = %v, want ErrZeroDenominator", err)
+	}
+}
+
+func TestTryAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"same currency", NewMoney(100, "BRL"), NewMoney(50, "BRL"), NewMoney(150, "BRL"), nil},
+		{"zero value takes the other currency", Money{}, NewMoney(50, "EUR"), NewMoney(50, "EUR"), nil},
+		{"mixed currencies", NewMoney(100, "BRL"), NewMoney(50, "EUR"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.TryAdd(tt.b)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("TryAdd = %v, %v; want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"even split", 900, []int64{1, 1, 1}, []int64{300, 300, 300}},
+		{"remainder goes to the first parts", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"proportional", 1000, []int64{3000, 1000}, []int64{750, 250}},
+		{"remainder skips zero weights", 101, []int64{0, 1, 1}, []int64{0, 51, 50}},
+		{"negative amount", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"all weights zero", 100, []int64{0, 0}, []int64{0, 0}},
+		{"zero amount", 0, []int64{2, 1}, []int64{0, 0}},
+		{"no parts", 100, nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := NewMoney(tt.amount, "BRL").Allocate(tt.weights)
+			if err != nil {
+				t.Fatalf("Allocate: %v", err)
+			}
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Allocate returned %d parts, want %d", len(parts), len(tt.want))
+			}
+			for i, part := range parts {
+				if part.Amount != tt.want[i] || part.Currency != "BRL" {
+					t.Errorf("part %d = %v, want BRL %d minor units", i, part, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := Cents(100).Allocate([]int64{1, -1}); !errors.Is(err, ErrNegativeWeight) {
+		t.Errorf("Allocate with a negative weight: error = %v, want ErrNegativeWeight", err)
+	}
+}
+
+func TestScanAndValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    int64
+		wantErr bool
+	}{
+		{"nil", nil, 0, false},
+		{"int64", int64(1250), 1250, false},
+		{"whole float64", float64(1250), 1250, false},
+		{"fractional float64", 12.5, 0, true},
+		{"bytes", []byte("-310"), -310, false},
+		{"string", " 42 ", 42, false},
+		{"decimal string", "12.50", 0, true},
+		{"unsupported type", true, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			err := m.Scan(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Scan(%v) = %v, want an error", tt.value, m)
+				}
+				return
+			}
+			if err != nil || m.Amount != tt.want || m.Currency != DefaultCurrency {
+				t.Errorf("Scan(%v) = %v, %v; want %s %d minor units", tt.value, m, err, DefaultCurrency, tt.want)
+			}
+
+			value, err := m.Value()
+			if err != nil || value != tt.want {
+				t.Errorf("Value() = %v, %v; want %d", value, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Money
+		json string
+	}{
+		{"whole", NewMoney(1200, "BRL"), "12.00"},
+		{"cents", NewMoney(1205, "BRL"), "12.05"},
+		{"negative", NewMoney(-5, "BRL"), "-0.05"},
+		{"zero", NewMoney(0, "BRL"), "0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil || string(data) != tt.json {
+				t.Fatalf("Marshal = %s, %v; want %s", data, err, tt.json)
+			}
+			var out Money
+			if err := json.Unmarshal(data, &out); err != nil || out != tt.in {
+				t.Errorf("Unmarshal(%s) = %v, %v; want %v", data, out, err, tt.in)
+			}
+		})
+	}
+
+	// A decoded amount keeps a currency set beforehand and rejects sub-cent precision
+	euros := Money{Currency: "EUR"}
+	if err := json.Unmarshal([]byte("9.9"), &euros); err != nil || euros != NewMoney(990, "EUR") {
+		t.Errorf("Unmarshal into EUR = %v, %v; want EUR 9.90", euros, err)
+	}
+	for _, input := range []string{"1.005", `"abc"`, "true"} {
+		var m Money
+		if err := json.Unmarshal([]byte(input), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", input, m)
+		}
+	}
+	untouched := NewMoney(100, "BRL")
+	if err := json.Unmarshal([]byte("null"), &untouched); err != nil || untouched.Amount != 100 {
+		t.Errorf("Unmarshal(null) = %v, %v; want the value left as it was", untouched, err)
+	}
+}
@@ -11,20 +11,25 @@ import (
 
 // Order represents an order entity
 type Order struct {
-	ID              string           `gorm:"type:text;primaryKey" json:"id"`
-	UserID          string           `gorm:"type:text" json:"userId"`                          // camelCase
-	OrderNumber     string           `gorm:"size:50;uniqueIndex" json:"orderNumber"`           // camelCase + orderNumber for TS
-	Status          string           `gorm:"size:50;default:'pending'" json:"status"`          // 'pending', 'confirmed', 'shipped', 'delivered', 'cancelled'
-	TotalAmount     float64          `gorm:"type:real" json:"total"`                           // 'total' per TS
-	ShippingFee     float64          `gorm:"type:real;default:0" json:"shippingFee"`           // camelCase
-	DiscountAmount  float64          `gorm:"type:real;default:0" json:"discountAmount"`        // camelCase
-	PaymentMethod   *string          `gorm:"size:100" json:"paymentMethod"`                    // camelCase
-	ShippingAddress *ShippingAddress `gorm:"type:json;serializer:json" json:"shippingAddress"` // camelCase + json (SQLite)
-	TrackingNumber  *string          `gorm:"size:100" json:"trackingNumber"`                   // camelCase
-	Notes           *string          `json:"notes"`
-	Items           []OrderItem      `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"items"`
-	CreatedAt       time.Time        `json:"createdAt"` // camelCase
-	UpdatedAt       time.Time        `json:"updatedAt"` // camelCase
+	ID               string           `gorm:"type:text;primaryKey" json:"id"`
+	UserID           string           `gorm:"type:text" json:"userId"`                          // camelCase
+	OrderNumber      string           `gorm:"size:50;uniqueIndex" json:"orderNumber"`           // camelCase + orderNumber for TS
+	Status           string           `gorm:"size:50;default:'pending'" json:"status"`          // 'pending', 'confirmed', 'shipped', 'delivered', 'cancelled'
+	Currency         string           `gorm:"size:3;default:'BRL'" json:"currency"`             // Every amount of the order is in this currency
+	TotalAmount      Money            `json:"total"`                                            // 'total' per TS
+	ShippingFee      Money            `gorm:"default:0" json:"shippingFee"`                     // camelCase
+	ShippingMethod   *string          `gorm:"size:50" json:"shippingMethod"`                    // ShippingOption.Method chosen at checkout
+	DiscountAmount   Money            `gorm:"default:0" json:"discountAmount"`                  // camelCase
+	TaxAmount        Money            `gorm:"default:0" json:"taxAmount"`                       // ICMS/DIFAL/FCP contained in the total
+	PaymentMethod    *string          `gorm:"size:100" json:"paymentMethod"`                    // camelCase
+	CustomerDocument *string          `gorm:"size:14" json:"customerDocument"`                  // CPF or CNPJ, digits only (required for NF-e)
+	ShippingAddress  *ShippingAddress `gorm:"type:json;serializer:json" json:"shippingAddress"` // camelCase + json (SQLite)
+	AddressID        *string          `gorm:"type:text" json:"addressId"`                       // Address book entry ShippingAddress was copied from
+	TrackingNumber   *string          `gorm:"size:100" json:"trackingNumber"`                   // camelCase
+	Notes            *string          `json:"notes"`
+	Items            []OrderItem      `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt        time.Time        `json:"createdAt"` // camelCase
+	UpdatedAt        time.Time        `json:"updatedAt"` // camelCase
 }
 
 // TableName sets the table name for Order
@@ -41,6 +46,24 @@ func (o *Order) BeforeCreate(tx *gorm.DB) error {
 		// Generate order number: ORD-RANDOMID
 		o.OrderNumber = "ORD-" + uuid.NewString()[:8]
 	}
+	if o.Currency == "" {
+		o.Currency = DefaultCurrency
+	}
+	return nil
+}
+
+// AfterFind tags the order's amounts, and those of preloaded items, with the order currency
+func (o *Order) AfterFind(tx *gorm.DB) error {
+	if o.Currency == "" {
+		o.Currency = DefaultCurrency
+	}
+	o.TotalAmount.Currency = o.Currency
+	o.ShippingFee.Currency = o.Currency
+	o.DiscountAmount.Currency = o.Currency
+	o.TaxAmount.Currency = o.Currency
+	for i := range o.Items {
+		o.Items[i].inCurrency(o.Currency)
+	}
 	return nil
 }
 
@@ -51,6 +74,7 @@ type ShippingAddress struct {
 	Complement   string `json:"complement,omitempty"`
 	Neighborhood string `json:"neighborhood"`
 	City         string `json:"city"`
+	CityCode     string `json:"cityCode,omitempty"` // IBGE municipality code, resolved from the CEP
 	State        string `json:"state"`
 	PostalCode   string `json:"postalCode"` // camelCase
 	Country      string `json:"country,omitempty"`
@@ -71,15 +95,16 @@ func (sa ShippingAddress) Value() (driver.Value, error) {
 
 // OrderItem represents items in an order
 type OrderItem struct {
-	ID          string    `gorm:"type:text;primaryKey" json:"id"`
-	OrderID     string    `gorm:"type:text" json:"orderId"`   // camelCase
-	ProductID   string    `gorm:"type:text" json:"productId"` // camelCase
-	Quantity    int       `json:"quantity"`
-	PriceAtTime float64   `gorm:"type:real" json:"priceAtTime"` // camelCase
-	Color       *string   `gorm:"size:100" json:"color,omitempty"`
-	Size        *string   `gorm:"size:50" json:"size,omitempty"`
-	Product     *Product  `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT" json:"product,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"` // camelCase
+	ID          string       `gorm:"type:text;primaryKey" json:"id"`
+	OrderID     string       `gorm:"type:text" json:"orderId"`   // camelCase
+	ProductID   string       `gorm:"type:text" json:"productId"` // camelCase
+	Quantity    int          `json:"quantity"`
+	PriceAtTime Money        `json:"priceAtTime"` // camelCase
+	Color       *string      `gorm:"size:100" json:"color,omitempty"`
+	Size        *string      `gorm:"size:50" json:"size,omitempty"`
+	Tax         TaxBreakdown `gorm:"embedded;embeddedPrefix:tax_" json:"tax"`
+	Product     *Product     `gorm:"foreignKey:ProductID;constraint:OnDelete:RESTRICT" json:"product,omitempty"`
+	CreatedAt   time.Time    `json:"createdAt"` // camelCase
 }
 
 // TableName sets the table name for OrderItem
@@ -95,37 +120,57 @@ func (oi *OrderItem) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// inCurrency tags the item's amounts with its order's currency
+func (oi *OrderItem) inCurrency(currency string) {
+	oi.PriceAtTime.Currency = currency
+	oi.Tax.Base.Currency = currency
+	oi.Tax.ICMSAmount.Currency = currency
+	oi.Tax.DIFALAmount.Currency = currency
+	oi.Tax.FCPAmount.Currency = currency
+	oi.Tax.TotalTax.Currency = currency
+}
+
 // OrderResponse is the DTO returned to frontend
 type OrderResponse struct {
-	ID              string              `json:"id"`
-	UserID          string              `json:"userId"`      // camelCase
-	OrderNumber     string              `json:"orderNumber"` // camelCase
-	Status          string              `json:"status"`
-	Total           float64             `json:"total"`           // Match TS 'total' field
-	ShippingFee     float64             `json:"shippingFee"`     // camelCase
-	DiscountAmount  float64             `json:"discountAmount"`  // camelCase
-	PaymentMethod   *string             `json:"paymentMethod"`   // camelCase
-	ShippingAddress *ShippingAddress    `json:"shippingAddress"` // camelCase
-	TrackingNumber  *string             `json:"trackingNumber"`  // camelCase
-	Items           []OrderItemResponse `json:"items"`
-	CreatedAt       time.Time           `json:"createdAt"` // camelCase
+	ID               string              `json:"id"`
+	UserID           string              `json:"userId"`      // camelCase
+	OrderNumber      string              `json:"orderNumber"` // camelCase
+	Status           string              `json:"status"`
+	Currency         string              `json:"currency"`
+	Total            Money               `json:"total"`            // Match TS 'total' field
+	ShippingFee      Money               `json:"shippingFee"`      // camelCase
+	ShippingMethod   *string             `json:"shippingMethod"`   // camelCase
+	DiscountAmount   Money               `json:"discountAmount"`   // camelCase
+	TaxAmount        Money               `json:"taxAmount"`        // camelCase
+	PaymentMethod    *string             `json:"paymentMethod"`    // camelCase
+	CustomerDocument *string             `json:"customerDocument"` // camelCase
+	ShippingAddress  *ShippingAddress    `json:"shippingAddress"`  // camelCase
+	TrackingNumber   *string             `json:"trackingNumber"`   // camelCase
+	Items            []OrderItemResponse `json:"items"`
+	CreatedAt        time.Time           `json:"createdAt"` // camelCase
 }
 
 // OrderItemResponse is the DTO for order items
 type OrderItemResponse struct {
-	ID          string  `json:"id"`
-	ProductID   string  `json:"productId"`             // camelCase
-	ProductName string  `json:"productName,omitempty"` // camelCase
-	Quantity    int     `json:"quantity"`
-	PriceAtTime float64 `json:"priceAtTime"` // camelCase
-	Color       *string `json:"color,omitempty"`
-	Size        *string `json:"size,omitempty"`
+	ID          string        `json:"id"`
+	ProductID   string        `json:"productId"`             // camelCase
+	ProductName string        `json:"productName,omitempty"` // camelCase
+	Quantity    int           `json:"quantity"`
+	PriceAtTime Money         `json:"priceAtTime"` // camelCase
+	Color       *string       `json:"color,omitempty"`
+	Size        *string       `json:"size,omitempty"`
+	Tax         *TaxBreakdown `json:"tax,omitempty"` // nil for orders placed before tax tracking
 }
 
 // ToResponse converts Order to OrderResponse
 func (o *Order) ToResponse() *OrderResponse {
 	items := make([]OrderItemResponse, len(o.Items))
 	for i, item := range o.Items {
+		var tax *TaxBreakdown
+		if item.Tax.DestinationState != "" {
+			itemTax := item.Tax
+			tax = &itemTax
+		}
 		items[i] = OrderItemResponse{
 			ID:          item.ID,
 			ProductID:   item.ProductID,
@@ -134,21 +179,26 @@ func (o *Order) ToResponse() *OrderResponse {
 			PriceAtTime: item.PriceAtTime,
 			Color:       item.Color,
 			Size:        item.Size,
+			Tax:         tax,
 		}
 	}
 
 	return &OrderResponse{
-		ID:              o.ID,
-		UserID:          o.UserID,
-		OrderNumber:     o.OrderNumber,
-		Status:          o.Status,
-		Total:           o.TotalAmount, // Map to 'total' per TS
-		ShippingFee:     o.ShippingFee,
-		DiscountAmount:  o.DiscountAmount,
-		PaymentMethod:   o.PaymentMethod,
-		ShippingAddress: o.ShippingAddress,
-		TrackingNumber:  o.TrackingNumber,
-		Items:           items,
-		CreatedAt:       o.CreatedAt,
+		ID:               o.ID,
+		UserID:           o.UserID,
+		OrderNumber:      o.OrderNumber,
+		Status:           o.Status,
+		Currency:         o.Currency,
+		Total:            o.TotalAmount, // Map to 'total' per TS
+		ShippingFee:      o.ShippingFee,
+		ShippingMethod:   o.ShippingMethod,
+		DiscountAmount:   o.DiscountAmount,
+		TaxAmount:        o.TaxAmount,
+		PaymentMethod:    o.PaymentMethod,
+		CustomerDocument: o.CustomerDocument,
+		ShippingAddress:  o.ShippingAddress,
+		TrackingNumber:   o.TrackingNumber,
+		Items:            items,
+		CreatedAt:        o.CreatedAt,
 	}
 }
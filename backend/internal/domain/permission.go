@@ -0,0 +1,49 @@
+package domain
+
+// Permission names an action a role may perform, as "resource:action"
+type Permission string
+
+// Permissions checked by middleware.Require
+const (
+	PermSellerApply      Permission = "seller:apply"      // Apply to sell on the platform
+	PermProductWrite     Permission = "product:write"     // Manage one's own products and their prices
+	PermOrderFulfil      Permission = "order:fulfil"      // See and invoice orders for one's own products
+	PermStoreManage      Permission = "store:manage"      // Edit one's store and fiscal profile
+	PermStoreAnalytics   Permission = "store:analytics"   // See one's store analytics
+	PermStoreTeam        Permission = "store:team"        // Invite and manage the store's team members
+	PermAPIKeyManage     Permission = "apikey:manage"     // Create and revoke one's API keys
+	PermAdminUsers       Permission = "admin:users"       // List, suspend and change the role of any user
+	PermAdminSellers     Permission = "admin:sellers"     // Review seller applications
+	PermAdminCatalog     Permission = "admin:catalog"     // Moderate any product
+	PermAdminOrders      Permission = "admin:orders"      // See and act on any order
+	PermAdminMetrics     Permission = "admin:metrics"     // See platform-wide metrics
+	PermAdminImpersonate Permission = "admin:impersonate" // Act as a customer or seller, for support
+)
+
+// rolePermissions grants permissions to each role. Admins manage the platform,
+// they do not sell: admin permissions do not include the seller ones.
+var rolePermissions = map[string][]Permission{
+	RoleCustomer: {PermSellerApply},
+	RoleSeller:   {PermProductWrite, PermOrderFulfil, PermStoreManage, PermStoreAnalytics, PermStoreTeam, PermAPIKeyManage},
+	RoleAdmin:    {PermAdminUsers, PermAdminSellers, PermAdminCatalog, PermAdminOrders, PermAdminMetrics, PermAdminImpersonate},
+}
+
+// PermissionsFor lists the permissions granted to a role, nil for unknown roles
+func PermissionsFor(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+// RoleHasPermission reports whether role grants perm
+func RoleHasPermission(role string, perm Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the user's role grants perm
+func (u *User) Can(perm Permission) bool {
+	return RoleHasPermission(u.Role, perm)
+}
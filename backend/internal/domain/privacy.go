@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of data-subject requests (LGPD art. 18)
+const (
+	PrivacyExport  = "export"  // Copy of the user's data
+	PrivacyErasure = "erasure" // Anonymization of the account
+)
+
+// Statuses of a PrivacyRequest, in order
+const (
+	PrivacyPending   = "pending"
+	PrivacyRunning   = "running"
+	PrivacyCompleted = "completed"
+	PrivacyFailed    = "failed"
+)
+
+// AuditDataExportRequested is audited when a user asks for a copy of their data
+const AuditDataExportRequested = "privacy.export_requested"
+
+// ErasedUserName replaces the name of an erased account
+const ErasedUserName = "Usuário removido"
+
+// PrivacyRequest is a data-subject request, processed as a background job
+type PrivacyRequest struct {
+	ID          string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID      string     `gorm:"type:text;index" json:"-"`
+	Kind        string     `gorm:"size:20" json:"kind"`         // PrivacyExport or PrivacyErasure
+	Status      string     `gorm:"size:20;index" json:"status"` // Privacy* statuses
+	Error       *string    `json:"error,omitempty"`             // Why a failed request failed
+	Archive     []byte     `json:"-"`                           // ZIP produced by an export
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`         // camelCase, the archive can be downloaded until then
+	StartedAt   *time.Time `json:"startedAt,omitempty"`         // camelCase
+	CompletedAt *time.Time `json:"completedAt,omitempty"`       // camelCase
+	CreatedAt   time.Time  `json:"createdAt"`                   // camelCase
+}
+
+// TableName sets the table name for PrivacyRequest
+func (pr *PrivacyRequest) TableName() string {
+	return "privacy_requests"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (pr *PrivacyRequest) BeforeCreate(tx *gorm.DB) error {
+	if pr.ID == "" {
+		pr.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsFinished reports whether the job is no longer queued or running
+func (pr *PrivacyRequest) IsFinished() bool {
+	return pr.Status == PrivacyCompleted || pr.Status == PrivacyFailed
+}
+
+// DataExport is the content of data.json in an export archive.
+// The platform stores no product reviews or ratings, so there are none to export.
+type DataExport struct {
+	ExportedAt        time.Time          `json:"exportedAt"`
+	Profile           *UserResponse      `json:"profile"`
+	Addresses         []UserAddress      `json:"addresses"`
+	Orders            []OrderResponse    `json:"orders"`
+	AuditLog          []AuditLog         `json:"auditLog"`
+	SellerApplication *SellerApplication `json:"sellerApplication,omitempty"`
+	Store             *Store             `json:"store,omitempty"`
+}
+
+// EraseAccountRequest is the request body for erasing the current account.
+// Accounts without a password (social login only) send none and must have signed in recently instead.
+type EraseAccountRequest struct {
+	Password string `json:"password"`
+}
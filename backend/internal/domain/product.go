@@ -15,13 +15,16 @@ type Product struct {
 	Name              string    `gorm:"size:255" json:"name"`
 	Slug              string    `gorm:"size:255;uniqueIndex" json:"slug"`
 	Description       string    `json:"description"`
-	Price             float64   `gorm:"type:real" json:"price"`
-	CompareAtPrice    *float64  `gorm:"type:real" json:"compareAtPrice"` // camelCase
-	CostPrice         *float64  `gorm:"type:real" json:"costPrice"`      // camelCase
+	Currency          string    `gorm:"size:3;default:'BRL'" json:"currency"` // Currency of Price, CompareAtPrice and CostPrice
+	Price             Money     `json:"price"`
+	CompareAtPrice    *Money    `json:"compareAtPrice"` // camelCase
+	CostPrice         *Money    `json:"costPrice"`      // camelCase
 	SKU               string    `gorm:"size:100" json:"sku"`
 	Barcode           string    `gorm:"size:100" json:"barcode"`
-	StockQuantity     int       `json:"stockQuantity"`     // camelCase
-	LowStockThreshold int       `json:"lowStockThreshold"` // camelCase
+	NCM               string    `gorm:"size:8" json:"ncm"`          // Nomenclatura Comum do Mercosul, 8 digits
+	TaxOrigin         int       `gorm:"default:0" json:"taxOrigin"` // TaxOrigin* code (0 = nacional)
+	StockQuantity     int       `json:"stockQuantity"`              // camelCase
+	LowStockThreshold int       `json:"lowStockThreshold"`          // camelCase
 	Weight            *float64  `json:"weight"`
 	IsActive          bool      `json:"isActive"`                  // camelCase
 	IsFeatured        bool      `json:"isFeatured"`                // camelCase
@@ -29,6 +32,15 @@ type Product struct {
 	MetaDescription   *string   `json:"metaDescription"`           // camelCase
 	CreatedAt         time.Time `json:"createdAt"`                 // camelCase
 	UpdatedAt         time.Time `json:"updatedAt"`                 // camelCase
+
+	Prices []ProductPrice `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"prices,omitempty"` // Price lists in other currencies
+
+	// Set while an admin suspends the seller: the product is hidden from the storefront and cannot be ordered
+	SellerSuspended bool `gorm:"default:false;index" json:"sellerSuspended,omitempty"`
+
+	// Storefront price, filled when listing the catalog in a currency (not persisted)
+	DisplayPrice   *Money `gorm:"-" json:"displayPrice,omitempty"`   // camelCase
+	PriceConverted bool   `gorm:"-" json:"priceConverted,omitempty"` // DisplayPrice comes from an exchange rate, not a price list
 }
 
 // TableName sets the table name for Product
@@ -41,5 +53,42 @@ func (p *Product) BeforeCreate(tx *gorm.DB) error {
 	if p.ID == "" {
 		p.ID = uuid.NewString()
 	}
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	return nil
+}
+
+// AfterFind tags the loaded amounts with the product's currency
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	p.Price.Currency = p.Currency
+	if p.CompareAtPrice != nil {
+		p.CompareAtPrice.Currency = p.Currency
+	}
+	if p.CostPrice != nil {
+		p.CostPrice.Currency = p.Currency
+	}
 	return nil
 }
+
+// IsAvailable reports whether the product can be added to carts and ordered
+func (p *Product) IsAvailable() bool {
+	return p.IsActive && !p.SellerSuspended
+}
+
+// PriceIn returns the list price in a currency: the product's own price or its price list entry.
+// Prices must be loaded; ok is false when the product is not sold in the currency.
+func (p *Product) PriceIn(currency string) (price Money, ok bool) {
+	if currency == p.Currency {
+		return p.Price, true
+	}
+	for _, entry := range p.Prices {
+		if entry.Currency == currency {
+			return entry.Price, true
+		}
+	}
+	return Money{}, false
+}
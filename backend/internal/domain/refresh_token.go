@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Refresh token and session revocation reasons
+const (
+	RevokedRotated        = "rotated"          // Exchanged for a new token of the same family
+	RevokedLogout         = "logout"           // The session was logged out
+	RevokedLogoutAll      = "logout_all"       // Every session of the user was logged out
+	RevokedReuse          = "reuse_detected"   // A rotated token was presented again, the family is compromised
+	RevokedPasswordReset  = "password_reset"   // The password was reset, every session is logged out
+	RevokedSuspended      = "suspended"        // An admin suspended the account
+	RevokedPasswordChange = "password_changed" // The password was changed, other sessions are logged out
+	RevokedErased         = "erased"           // The account was anonymized at its owner's request
+	RevokedByUser         = "revoked_by_user"  // Signed out from the session list
+	RevokedIdentityLinked = "identity_linked"  // The unverified account was claimed by the mailbox owner through social login
+)
+
+// RefreshToken is one rotating refresh token. Tokens issued from the same login share a
+// FamilyID, the ID of their Session, which access tokens carry as their jti so revoking
+// the family revokes them too.
+// Only the SHA-256 of the token is stored.
+type RefreshToken struct {
+	ID            string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID        string     `gorm:"type:text;index" json:"userId"`   // camelCase
+	FamilyID      string     `gorm:"type:text;index" json:"familyId"` // camelCase
+	TokenHash     string     `gorm:"size:64;uniqueIndex" json:"-"`
+	UserAgent     string     `gorm:"size:255" json:"userAgent"` // camelCase
+	IPAddress     string     `gorm:"size:45" json:"ipAddress"`  // camelCase
+	ExpiresAt     time.Time  `json:"expiresAt"`                 // camelCase
+	RevokedAt     *time.Time `json:"revokedAt"`                 // camelCase
+	RevokedReason string     `gorm:"size:50" json:"revokedReason,omitempty"`
+	ReplacedByID  *string    `gorm:"type:text" json:"replacedById,omitempty"` // Token issued when this one was rotated
+	CreatedAt     time.Time  `json:"createdAt"`                               // camelCase
+}
+
+// TableName sets the table name for RefreshToken
+func (rt *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (rt *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if rt.ID == "" {
+		rt.ID = uuid.NewString()
+	}
+	if rt.FamilyID == "" {
+		rt.FamilyID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsActive reports whether the token can still be exchanged
+func (rt *RefreshToken) IsActive(now time.Time) bool {
+	return rt.RevokedAt == nil && now.Before(rt.ExpiresAt)
+}
+
+// ClientInfo describes the device a session was started from
+type ClientInfo struct {
+	UserAgent  string
+	IPAddress  string
+	DeviceName string // Optional name given at login, stored on the session
+}
+
+// RefreshRequest is the request body for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
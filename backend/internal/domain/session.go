@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditSessionRevoked is audited when a user signs a session out from the session list
+const AuditSessionRevoked = "session.revoked"
+
+// Session is one login on one device. Its ID is the refresh token FamilyID and the jti of
+// every access token issued for it, so revoking the session revokes all of them.
+type Session struct {
+	ID            string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID        string     `gorm:"type:text;index" json:"userId"` // camelCase
+	DeviceName    *string    `gorm:"size:100" json:"deviceName"`    // camelCase, optional, chosen by the user
+	UserAgent     string     `gorm:"size:255" json:"userAgent"`     // camelCase
+	IPAddress     string     `gorm:"size:45" json:"ipAddress"`      // camelCase, as of the last refresh
+	CreatedAt     time.Time  `json:"createdAt"`                     // camelCase
+	LastSeenAt    time.Time  `json:"lastSeenAt"`                    // camelCase, updated at most once a minute
+	ExpiresAt     time.Time  `json:"expiresAt"`                     // camelCase, moves forward with every refresh
+	RevokedAt     *time.Time `json:"-"`
+	RevokedReason string     `gorm:"size:50" json:"-"`
+	ActorID       *string    `gorm:"type:text" json:"-"` // The admin impersonating the user; such sessions are not listed
+	Current       bool       `gorm:"-" json:"current"`   // The session of the request listing it
+}
+
+// TableName sets the table name for Session
+func (s *Session) TableName() string {
+	return "sessions"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be used
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RenameSessionRequest is the request body for naming a session's device
+type RenameSessionRequest struct {
+	DeviceName string `json:"device_name" binding:"max=100"` // Empty clears the name
+}
@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// User roles
+const (
+	RoleCustomer = "customer"
+	RoleSeller   = "seller"
+	RoleAdmin    = "admin"
+)
+
+// Seller application statuses
+const (
+	ApplicationPending  = "pending"
+	ApplicationApproved = "approved"
+	ApplicationRejected = "rejected"
+)
+
+// BankAccount is where a seller receives payouts
+type BankAccount struct {
+	BankCode       string `gorm:"size:3" json:"bankCode"`        // COMPE code, e.g. 001, 341
+	Branch         string `gorm:"size:10" json:"branch"`         // Agência
+	Number         string `gorm:"size:20" json:"number"`         // Conta com dígito
+	HolderName     string `gorm:"size:100" json:"holderName"`    // camelCase
+	HolderDocument string `gorm:"size:14" json:"holderDocument"` // CPF or CNPJ, digits only
+}
+
+// SellerApplication is a customer's request to sell on the platform, reviewed by an admin
+type SellerApplication struct {
+	ID              string           `gorm:"type:text;primaryKey" json:"id"`
+	UserID          string           `gorm:"type:text;index" json:"userId"` // camelCase
+	Status          string           `gorm:"size:20;index;default:'pending'" json:"status"`
+	StoreName       string           `gorm:"size:100" json:"storeName"` // camelCase
+	LegalName       string           `gorm:"size:100" json:"legalName"` // Razão social
+	CNPJ            string           `gorm:"size:14" json:"cnpj"`
+	Description     string           `json:"description"`
+	BankAccount     BankAccount      `gorm:"embedded;embeddedPrefix:bank_" json:"bankAccount"`
+	Address         *ShippingAddress `gorm:"type:json;serializer:json" json:"address"`
+	ReviewedBy      *string          `gorm:"type:text" json:"reviewedBy"`        // Admin user ID
+	ReviewedAt      *time.Time       `json:"reviewedAt"`                         // camelCase
+	RejectionReason *string          `json:"rejectionReason"`                    // camelCase
+	StoreID         *string          `gorm:"type:text" json:"storeId,omitempty"` // Store created on approval
+	User            *User            `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CreatedAt       time.Time        `json:"createdAt"` // camelCase
+	UpdatedAt       time.Time        `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for SellerApplication
+func (sa *SellerApplication) TableName() string {
+	return "seller_applications"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (sa *SellerApplication) BeforeCreate(tx *gorm.DB) error {
+	if sa.ID == "" {
+		sa.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// Store is a seller's public storefront
+type Store struct {
+	ID             string           `gorm:"type:text;primaryKey" json:"id"`
+	SellerID       string           `gorm:"type:text;uniqueIndex" json:"sellerId"` // camelCase
+	Name           string           `gorm:"size:100" json:"name"`
+	Slug           string           `gorm:"size:120;uniqueIndex" json:"slug"`
+	LogoURL        *string          `gorm:"size:255" json:"logoUrl"` // camelCase
+	Description    string           `json:"description"`
+	ShippingPolicy string           `json:"shippingPolicy"` // camelCase
+	ReturnPolicy   string           `json:"returnPolicy"`   // camelCase
+	LegalName      string           `gorm:"size:100" json:"legalName"`
+	CNPJ           string           `gorm:"size:14" json:"cnpj"`
+	BankAccount    BankAccount      `gorm:"embedded;embeddedPrefix:bank_" json:"bankAccount"`
+	Address        *ShippingAddress `gorm:"type:json;serializer:json" json:"address"`
+	Seller         *User            `gorm:"foreignKey:SellerID" json:"-"`
+	CreatedAt      time.Time        `json:"createdAt"` // camelCase
+	UpdatedAt      time.Time        `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for Store
+func (s *Store) TableName() string {
+	return "stores"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (s *Store) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// StorePublicResponse is the public store page: no bank details, CNPJ or street address
+type StorePublicResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Slug           string    `json:"slug"`
+	LogoURL        *string   `json:"logoUrl"`
+	Description    string    `json:"description"`
+	ShippingPolicy string    `json:"shippingPolicy"`
+	ReturnPolicy   string    `json:"returnPolicy"`
+	City           string    `json:"city,omitempty"`
+	State          string    `json:"state,omitempty"`
+	Products       []Product `json:"products"`
+	TotalProducts  int64     `json:"totalProducts"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// ToPublicResponse converts Store to its public page
+func (s *Store) ToPublicResponse(products []Product, total int64) *StorePublicResponse {
+	resp := &StorePublicResponse{
+		ID:             s.ID,
+		Name:           s.Name,
+		Slug:           s.Slug,
+		LogoURL:        s.LogoURL,
+		Description:    s.Description,
+		ShippingPolicy: s.ShippingPolicy,
+		ReturnPolicy:   s.ReturnPolicy,
+		Products:       products,
+		TotalProducts:  total,
+		CreatedAt:      s.CreatedAt,
+	}
+	if s.Address != nil {
+		resp.City = s.Address.City
+		resp.State = s.Address.State
+	}
+	return resp
+}
+
+// SellerApplicationRequest is the request body for applying to sell
+type SellerApplicationRequest struct {
+	StoreName   string          `json:"store_name" binding:"required,min=2,max=100"`
+	LegalName   string          `json:"legal_name" binding:"required,max=100"`
+	CNPJ        string          `json:"cnpj" binding:"required"`
+	Description string          `json:"description" binding:"max=2000"`
+	BankAccount BankAccount     `json:"bank_account" binding:"required"`
+	Address     ShippingAddress `json:"address" binding:"required"`
+}
+
+// RejectApplicationRequest is the request body for rejecting a seller application
+type RejectApplicationRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// UpdateStoreRequest is the request body for editing a store profile; omitted fields are kept
+type UpdateStoreRequest struct {
+	Name           *string `json:"name" binding:"omitempty,min=2,max=100"`
+	LogoURL        *string `json:"logo_url" binding:"omitempty,url,max=255"`
+	Description    *string `json:"description" binding:"omitempty,max=2000"`
+	ShippingPolicy *string `json:"shipping_policy" binding:"omitempty,max=5000"`
+	ReturnPolicy   *string `json:"return_policy" binding:"omitempty,max=5000"`
+}
+
+// Validate checks the bank account fields. Field keys use the JSON names of BankAccount.
+func (b *BankAccount) Validate() *ValidationError {
+	v := NewValidationError()
+	b.BankCode = OnlyDigits(b.BankCode)
+	b.Branch = strings.TrimSpace(b.Branch)
+	b.Number = strings.TrimSpace(b.Number)
+	b.HolderName = collapseSpaces(b.HolderName)
+
+	if len(b.BankCode) != 3 {
+		v.Add("bankCode", "must be the 3-digit bank code")
+	}
+	if b.Branch == "" || len(b.Branch) > 10 {
+		v.Add("branch", "is required")
+	}
+	if b.Number == "" || len(b.Number) > 20 {
+		v.Add("number", "is required")
+	}
+	if b.HolderName == "" {
+		v.Add("holderName", "is required")
+	}
+	if document := NormalizeTaxDocument(b.HolderDocument); document != "" {
+		b.HolderDocument = document
+	} else {
+		v.Add("holderDocument", "must be a valid CPF or CNPJ")
+	}
+	return v
+}
+
+// Slugify turns a name into a URL slug ("Ateliê São Paulo" -> "atelie-sao-paulo")
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Drop accents left over by the decomposition
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+			dash = false
+		case !dash && b.Len() > 0:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxStoreMembers caps the members and pending invitations of one store
+const MaxStoreMembers = 50
+
+// Store roles. The owner is the seller the store belongs to and has no StoreMember row;
+// the other roles are given to invited team members.
+const (
+	StoreRoleOwner         = "owner"
+	StoreRoleManager       = "manager"
+	StoreRoleCatalogEditor = "catalog_editor"
+	StoreRoleFulfilment    = "fulfilment"
+	StoreRoleAnalyst       = "analyst"
+)
+
+// StorePermissions are granted per store, by the store role, rather than by the user's role
+var StorePermissions = []Permission{PermProductWrite, PermOrderFulfil, PermStoreManage, PermStoreAnalytics, PermStoreTeam}
+
+// storeRolePermissions grants store permissions to each store role
+var storeRolePermissions = map[string][]Permission{
+	StoreRoleOwner:         StorePermissions,
+	StoreRoleManager:       StorePermissions,
+	StoreRoleCatalogEditor: {PermProductWrite},
+	StoreRoleFulfilment:    {PermOrderFulfil},
+	StoreRoleAnalyst:       {PermStoreAnalytics},
+}
+
+// IsStorePermission reports whether perm is granted per store
+func IsStorePermission(perm Permission) bool {
+	for _, p := range StorePermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidMemberRole reports whether role can be given to a team member (any store role but owner)
+func IsValidMemberRole(role string) bool {
+	_, ok := storeRolePermissions[role]
+	return ok && role != StoreRoleOwner
+}
+
+// StoreMember is a user working for a store, or an invitation to. Invitations are
+// addressed to an email and accepted by the account with that (verified) email.
+type StoreMember struct {
+	ID          string     `gorm:"type:text;primaryKey" json:"id"`
+	StoreID     string     `gorm:"type:text;uniqueIndex:idx_store_members_store_email" json:"storeId"` // camelCase
+	Email       string     `gorm:"size:255;uniqueIndex:idx_store_members_store_email;index" json:"email"`
+	UserID      *string    `gorm:"type:text;index" json:"userId"` // camelCase, set on acceptance
+	Role        string     `gorm:"size:20" json:"role"`
+	InvitedByID string     `gorm:"type:text" json:"invitedById"` // camelCase
+	AcceptedAt  *time.Time `json:"acceptedAt"`                   // camelCase, nil while the invitation is pending
+	Store       *Store     `gorm:"foreignKey:StoreID" json:"-"`
+	CreatedAt   time.Time  `json:"createdAt"` // camelCase
+	UpdatedAt   time.Time  `json:"updatedAt"` // camelCase
+}
+
+// TableName sets the table name for StoreMember
+func (m *StoreMember) TableName() string {
+	return "store_members"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (m *StoreMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// IsPending reports whether the invitation was not accepted yet
+func (m *StoreMember) IsPending() bool {
+	return m.AcceptedAt == nil
+}
+
+// StoreAccess is the store a request acts for (see X-Store-ID) and what the user may do in it
+type StoreAccess struct {
+	StoreID     string       `json:"storeId"`  // camelCase
+	SellerID    string       `json:"sellerId"` // camelCase, the owner: products, orders and invoices are keyed by it
+	Name        string       `json:"name"`
+	Slug        string       `json:"slug"`
+	Role        string       `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// NewStoreAccess describes acting for store with a store role
+func NewStoreAccess(store *Store, role string) *StoreAccess {
+	return &StoreAccess{
+		StoreID:     store.ID,
+		SellerID:    store.SellerID,
+		Name:        store.Name,
+		Slug:        store.Slug,
+		Role:        role,
+		Permissions: append([]Permission(nil), storeRolePermissions[role]...),
+	}
+}
+
+// Can reports whether the store role grants perm
+func (a *StoreAccess) Can(perm Permission) bool {
+	for _, granted := range a.Permissions {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreInvitation is a pending invitation, as shown to the invited user
+type StoreInvitation struct {
+	ID        string    `json:"id"`
+	StoreID   string    `json:"storeId"`   // camelCase
+	StoreName string    `json:"storeName"` // camelCase
+	StoreSlug string    `json:"storeSlug"` // camelCase
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"` // camelCase
+}
+
+// InviteStoreMemberRequest is the request body for inviting a team member
+type InviteStoreMemberRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// UpdateStoreMemberRequest is the request body for changing a team member's role
+type UpdateStoreMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+// Product tax origin codes (tabela "origem da mercadoria" used by ICMS/NF-e)
+const (
+	TaxOriginNational           = 0 // Nacional
+	TaxOriginForeignDirect      = 1 // Estrangeira - importação direta
+	TaxOriginForeignInternal    = 2 // Estrangeira - adquirida no mercado interno
+	TaxOriginNationalOver40     = 3 // Nacional com conteúdo de importação > 40%
+	TaxOriginNationalBasicProc  = 4 // Nacional - processos produtivos básicos
+	TaxOriginNationalUnder40    = 5 // Nacional com conteúdo de importação <= 40%
+	TaxOriginForeignDirectCamex = 6 // Estrangeira - importação direta, sem similar (CAMEX)
+	TaxOriginForeignInternCamex = 7 // Estrangeira - mercado interno, sem similar (CAMEX)
+	TaxOriginNationalOver70     = 8 // Nacional com conteúdo de importação > 70%
+)
+
+// TaxBreakdown is the per-line tax computation stored on each OrderItem.
+// ICMS is charged "por dentro" in Brazil: the amounts are already contained in the
+// line price and are reported for fiscal purposes, not added on top of the total.
+type TaxBreakdown struct {
+	NCM              string  `gorm:"size:8" json:"ncm,omitempty"`
+	OriginState      string  `gorm:"size:2" json:"originState"`
+	DestinationState string  `gorm:"size:2" json:"destinationState"`
+	Base             Money   `gorm:"default:0" json:"base"`                // Calculation base (line total)
+	ICMSRate         float64 `gorm:"type:real;default:0" json:"icmsRate"`  // Percent, e.g. 12 for 12%
+	ICMSAmount       Money   `gorm:"default:0" json:"icmsAmount"`          // Due to the origin state
+	DIFALRate        float64 `gorm:"type:real;default:0" json:"difalRate"` // Destination internal rate minus interstate rate
+	DIFALAmount      Money   `gorm:"default:0" json:"difalAmount"`         // Due to the destination state
+	FCPRate          float64 `gorm:"type:real;default:0" json:"fcpRate"`   // Fundo de Combate à Pobreza
+	FCPAmount        Money   `gorm:"default:0" json:"fcpAmount"`
+	TotalTax         Money   `gorm:"default:0" json:"totalTax"`
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeCount is how many recovery codes are issued at once
+const RecoveryCodeCount = 10
+
+// Audited two-factor changes
+const (
+	AuditTwoFactorEnabled     = "2fa.enabled"
+	AuditTwoFactorDisabled    = "2fa.disabled"
+	AuditRecoveryCodesRenewed = "2fa.recovery_codes_renewed"
+	AuditRecoveryCodeUsed     = "2fa.recovery_code_used"
+	AuditRolePolicyUpdated    = "security.role_policy_updated"
+)
+
+// RecoveryCode is a single-use code that replaces the TOTP code when the authenticator is lost.
+// Only the SHA-256 of the code is stored.
+type RecoveryCode struct {
+	ID        string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID    string     `gorm:"type:text;index" json:"userId"` // camelCase
+	CodeHash  string     `gorm:"size:64;uniqueIndex" json:"-"`
+	UsedAt    *time.Time `json:"usedAt"`    // camelCase
+	CreatedAt time.Time  `json:"createdAt"` // camelCase
+}
+
+// TableName sets the table name for RecoveryCode
+func (rc *RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (rc *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	if rc.ID == "" {
+		rc.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// RolePolicy holds the security requirements admins set for a role
+type RolePolicy struct {
+	Role             string    `gorm:"size:50;primaryKey" json:"role"`
+	RequireTwoFactor bool      `gorm:"default:false" json:"requireTwoFactor"` // camelCase
+	UpdatedBy        *string   `gorm:"type:text" json:"updatedBy"`            // camelCase, admin who last changed it
+	UpdatedAt        time.Time `json:"updatedAt"`                             // camelCase
+}
+
+// TableName sets the table name for RolePolicy
+func (rp *RolePolicy) TableName() string {
+	return "role_policies"
+}
+
+// TwoFactorStatus describes the current user's two-factor setup
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabledAt"`
+	Required               bool       `json:"required"`               // The user's role must use two-factor
+	RecoveryCodesRemaining int64      `json:"recoveryCodesRemaining"` // camelCase
+}
+
+// TwoFactorEnrollment is returned when enrolment starts; the secret is shown once
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`     // Base32, for manual entry
+	OTPAuthURI string `json:"otpauthUri"` // otpauth://totp/... for authenticator apps
+	QRCode     string `json:"qrCode"`     // PNG of OTPAuthURI as a data: URL
+}
+
+// TwoFactorPasswordRequest is the request body for starting enrolment
+type TwoFactorPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// TwoFactorCodeRequest is the request body for confirming enrolment or renewing recovery codes
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest is the request body for turning two-factor off
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+// UpdateRolePolicyRequest is the request body for changing a role's security requirements
+type UpdateRolePolicyRequest struct {
+	RequireTwoFactor *bool `json:"require_two_factor" binding:"required"`
+}
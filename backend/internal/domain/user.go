@@ -9,15 +9,23 @@ import (
 
 // User represents the user entity
 type User struct {
-	ID           string    `gorm:"type:text;primaryKey" json:"id"`
-	Name         string    `gorm:"size:255" json:"name"`
-	Email        string    `gorm:"size:255;uniqueIndex" json:"email"`
-	PasswordHash string    `gorm:"size:255" json:"-"`                      // Never expose password hash
-	Role         string    `gorm:"size:50;default:'customer'" json:"role"` // 'customer' or 'seller'
-	AvatarURL    *string   `gorm:"size:255" json:"avatarUrl"`              // camelCase for TS alignment
-	IsActive     bool      `gorm:"default:true" json:"isActive"`           // camelCase
-	CreatedAt    time.Time `json:"createdAt"`                              // camelCase
-	UpdatedAt    time.Time `json:"updatedAt"`                              // camelCase
+	ID                 string     `gorm:"type:text;primaryKey" json:"id"`
+	Name               string     `gorm:"size:255" json:"name"`
+	Email              string     `gorm:"size:255;uniqueIndex" json:"email"`
+	PasswordHash       string     `gorm:"size:255" json:"-"`                      // Never expose password hash
+	Role               string     `gorm:"size:50;default:'customer'" json:"role"` // 'customer', 'seller' or 'admin'
+	AvatarURL          *string    `gorm:"size:255" json:"avatarUrl"`              // camelCase for TS alignment
+	IsActive           bool       `gorm:"default:true" json:"isActive"`           // camelCase, false while suspended
+	EmailVerifiedAt    *time.Time `json:"emailVerifiedAt"`                        // camelCase, nil until the emailed link is opened
+	PendingEmail       *string    `gorm:"size:255" json:"pendingEmail"`           // camelCase, requested address waiting for its link to be opened
+	SuspendedAt        *time.Time `json:"suspendedAt"`                            // camelCase
+	SuspensionReason   *string    `json:"suspensionReason"`                       // camelCase, shown to admins only
+	ErasedAt           *time.Time `json:"erasedAt,omitempty"`                     // camelCase, set when the account was anonymized on request
+	TOTPSecret         *string    `gorm:"column:totp_secret;size:64" json:"-"`    // Base32, set at enrolment and kept while 2FA is enabled
+	TOTPLastStep       int64      `gorm:"column:totp_last_step" json:"-"`         // Last accepted time step, so a code cannot be replayed
+	TwoFactorEnabledAt *time.Time `json:"twoFactorEnabledAt"`                     // camelCase, nil until enrolment is confirmed
+	CreatedAt          time.Time  `json:"createdAt"`                              // camelCase
+	UpdatedAt          time.Time  `json:"updatedAt"`                              // camelCase
 }
 
 // TableName sets the table name for User
@@ -33,24 +41,49 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsValidRole reports whether role is one of the Role* constants
+func IsValidRole(role string) bool {
+	return role == RoleCustomer || role == RoleSeller || role == RoleAdmin
+}
+
+// HasTwoFactor reports whether the user confirmed a TOTP enrolment
+func (u *User) HasTwoFactor() bool {
+	return u.TwoFactorEnabledAt != nil && u.TOTPSecret != nil
+}
+
+// IsEmailVerified reports whether the user confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 // UserResponse is the DTO returned to frontend (no sensitive data)
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	AvatarURL *string   `json:"avatarUrl"` // camelCase
-	CreatedAt time.Time `json:"createdAt"` // camelCase
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	Email            string       `json:"email"`
+	Role             string       `json:"role"`
+	Permissions      []Permission `json:"permissions"`
+	AvatarURL        *string      `json:"avatarUrl"`        // camelCase
+	EmailVerified    bool         `json:"emailVerified"`    // camelCase
+	EmailVerifiedAt  *time.Time   `json:"emailVerifiedAt"`  // camelCase
+	PendingEmail     *string      `json:"pendingEmail"`     // camelCase
+	TwoFactorEnabled bool         `json:"twoFactorEnabled"` // camelCase
+	CreatedAt        time.Time    `json:"createdAt"`        // camelCase
 }
 
 // ToResponse converts User to UserResponse
 func (u *User) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Role:      u.Role,
-		AvatarURL: u.AvatarURL,
-		CreatedAt: u.CreatedAt,
+		ID:               u.ID,
+		Name:             u.Name,
+		Email:            u.Email,
+		Role:             u.Role,
+		Permissions:      PermissionsFor(u.Role),
+		AvatarURL:        u.AvatarURL,
+		EmailVerified:    u.IsEmailVerified(),
+		EmailVerifiedAt:  u.EmailVerifiedAt,
+		PendingEmail:     u.PendingEmail,
+		TwoFactorEnabled: u.HasTwoFactor(),
+		CreatedAt:        u.CreatedAt,
 	}
 }
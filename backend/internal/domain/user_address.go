@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxUserAddresses caps the address book of a single user
+const MaxUserAddresses = 20
+
+// UserAddress is an entry of a customer's address book
+type UserAddress struct {
+	ID                string          `gorm:"type:text;primaryKey" json:"id"`
+	UserID            string          `gorm:"type:text;index" json:"userId"` // camelCase
+	Label             string          `gorm:"size:50" json:"label"`          // e.g. "Casa", "Trabalho"
+	Address           ShippingAddress `gorm:"type:json;serializer:json" json:"address"`
+	IsDefaultShipping bool            `gorm:"default:false" json:"isDefaultShipping"` // camelCase
+	IsDefaultBilling  bool            `gorm:"default:false" json:"isDefaultBilling"`  // camelCase
+	CreatedAt         time.Time       `json:"createdAt"`                              // camelCase
+	UpdatedAt         time.Time       `json:"updatedAt"`                              // camelCase
+}
+
+// TableName sets the table name for UserAddress
+func (ua *UserAddress) TableName() string {
+	return "user_addresses"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (ua *UserAddress) BeforeCreate(tx *gorm.DB) error {
+	if ua.ID == "" {
+		ua.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// UserAddressRequest is the request body for creating or replacing an address book entry
+type UserAddressRequest struct {
+	Label             string          `json:"label" binding:"max=50"`
+	Address           ShippingAddress `json:"address" binding:"required"`
+	IsDefaultShipping bool            `json:"is_default_shipping"` // true makes it the default; false keeps the current flag
+	IsDefaultBilling  bool            `json:"is_default_billing"`  // same as IsDefaultShipping
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// User token purposes
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeEmailChange       = "email_change"    // Sent to the new address, switches the account to it
+	TokenPurposeLoginChallenge    = "login_challenge" // Password checked, waiting for the second factor
+)
+
+// UserToken is a single-use, expiring token handed to a user (password reset, email verification and
+// email change links, two-factor login challenges).
+// Only the SHA-256 of the token is stored.
+type UserToken struct {
+	ID        string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID    string     `gorm:"type:text;index" json:"userId"` // camelCase
+	Purpose   string     `gorm:"size:30;index" json:"purpose"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `json:"expiresAt"` // camelCase
+	UsedAt    *time.Time `json:"usedAt"`    // camelCase, set once consumed or superseded
+	CreatedAt time.Time  `json:"createdAt"` // camelCase
+}
+
+// TableName sets the table name for UserToken
+func (ut *UserToken) TableName() string {
+	return "user_tokens"
+}
+
+// BeforeCreate hook to generate UUID before saving
+func (ut *UserToken) BeforeCreate(tx *gorm.DB) error {
+	if ut.ID == "" {
+		ut.ID = uuid.NewString()
+	}
+	return nil
+}
+
+// ForgotPasswordRequest is the request body for requesting a password reset email
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest is the request body for choosing a new password with a reset token
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationError collects field-level problems found while validating a request.
+// Keys are the JSON paths of the offending fields (e.g. "shipping_address.postalCode").
+type ValidationError struct {
+	Fields map[string]string `json:"fields"`
+}
+
+// NewValidationError creates an empty validation error
+func NewValidationError() *ValidationError {
+	return &ValidationError{Fields: make(map[string]string)}
+}
+
+// Add records a problem for a field. The first message for a field wins.
+func (e *ValidationError) Add(field, message string) {
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// Merge copies the problems of another validation error under the given prefix
+func (e *ValidationError) Merge(prefix string, other *ValidationError) {
+	if other == nil {
+		return
+	}
+	for field, message := range other.Fields {
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+		e.Add(field, message)
+	}
+}
+
+// HasErrors reports whether any field problem was recorded
+func (e *ValidationError) HasErrors() bool {
+	return len(e.Fields) > 0
+}
+
+// OrNil returns the error only when it holds problems, so callers can `return v.OrNil()`
+func (e *ValidationError) OrNil() error {
+	if e == nil || !e.HasErrors() {
+		return nil
+	}
+	return e
+}
+
+// Error implements the error interface with a stable, sorted summary
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field + ": " + e.Fields[field]
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
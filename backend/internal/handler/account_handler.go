@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler handles profile, account recovery and email verification endpoints
+type AccountHandler struct {
+	accountService service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService service.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+// ForgotPassword emails a password reset link
+// POST /api/auth/forgot-password
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to request password reset", err.Error()))
+		return
+	}
+
+	// Same answer whether or not the account exists
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "If an account exists for this email, a reset link has been sent"))
+}
+
+// ResetPassword sets a new password using the emailed token
+// POST /api/auth/reset-password
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	err := h.accountService.ResetPassword(req.Token, req.Password)
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Password reset failed", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Password reset failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Password updated, please log in again"))
+}
+
+// VerifyEmail confirms the user's email with the emailed token
+// GET /api/auth/verify-email?token=
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", "token is required"))
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(token)
+	if errors.Is(err, service.ErrInvalidVerificationToken) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Email verification failed", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Email verification failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user.ToResponse(), "Email verified"))
+}
+
+// ResendVerification emails a new verification link to the current user
+// POST /api/auth/resend-verification (Protected)
+func (h *AccountHandler) ResendVerification(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+		return
+	}
+
+	userData, ok := user.(*domain.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+		return
+	}
+
+	err := h.accountService.SendVerification(userData.ID)
+	var throttled *service.ThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+		c.JSON(http.StatusTooManyRequests, utils.ErrorResponse("Too many requests", err.Error()))
+		return
+	}
+	if errors.Is(err, service.ErrEmailAlreadyVerified) {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Email already verified", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to send verification email", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Verification email sent"))
+}
+
+// UpdateProfile changes the current user's name or avatar
+// PATCH /api/auth/me (Protected)
+func (h *AccountHandler) UpdateProfile(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	user, err := h.accountService.UpdateProfile(userData.ID, &req, clientInfo(c))
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid profile", validationErr.Fields))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update profile", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user.ToResponse(), "Profile updated"))
+}
+
+// ChangePassword sets a new password and logs out every other session
+// POST /api/auth/change-password (Protected)
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+	claims, ok := c.Get("claims")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No claims in context"))
+		return
+	}
+
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	err := h.accountService.ChangePassword(userData.ID, claims.(*domain.AccessClaims).ID, &req, clientInfo(c))
+	if errors.Is(err, service.ErrWrongPassword) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Password change failed", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Password change failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Password changed, other sessions were logged out"))
+}
+
+// ChangeEmail asks to move the account to a new email address, which must confirm it
+// POST /api/auth/change-email (Protected)
+func (h *AccountHandler) ChangeEmail(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	user, err := h.accountService.ChangeEmail(userData.ID, &req, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrWrongPassword):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	case errors.Is(err, service.ErrEmailInUse):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user.ToResponse(), "Check the new address to confirm the change"))
+}
+
+// ConfirmEmailChange switches the account to its pending email with the token sent to that address
+// GET /api/auth/confirm-email-change?token=
+func (h *AccountHandler) ConfirmEmailChange(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", "token is required"))
+		return
+	}
+
+	user, err := h.accountService.ConfirmEmailChange(token, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrInvalidEmailChangeToken):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	case errors.Is(err, service.ErrEmailInUse):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Email change failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user.ToResponse(), "Email changed"))
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddressHandler handles the customer address book endpoints
+type AddressHandler struct {
+	addressBookService service.AddressBookService
+}
+
+// NewAddressHandler creates a new address book handler
+func NewAddressHandler(addressBookService service.AddressBookService) *AddressHandler {
+	return &AddressHandler{addressBookService: addressBookService}
+}
+
+// ListAddresses lists the current user's saved addresses
+// GET /api/me/addresses (Protected)
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	addresses, err := h.addressBookService.List(userData.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve addresses", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(addresses, "Addresses retrieved"))
+}
+
+// GetAddress retrieves one saved address
+// GET /api/me/addresses/:id (Protected)
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.addressBookService.Get(userData.ID, c.Param("id"))
+	if h.addressFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(address, "Address retrieved"))
+}
+
+// CreateAddress saves a new address
+// POST /api/me/addresses (Protected)
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UserAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	address, err := h.addressBookService.Create(userData.ID, &req)
+	if h.addressFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(address, "Address saved"))
+}
+
+// UpdateAddress replaces a saved address
+// PUT /api/me/addresses/:id (Protected)
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UserAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	address, err := h.addressBookService.Update(userData.ID, c.Param("id"), &req)
+	if h.addressFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(address, "Address updated"))
+}
+
+// DeleteAddress removes a saved address
+// DELETE /api/me/addresses/:id (Protected)
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.addressFailed(c, h.addressBookService.Delete(userData.ID, c.Param("id"))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Address deleted"))
+}
+
+// addressFailed writes the error response for a failed address book operation
+func (h *AddressHandler) addressFailed(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid address", validationErr.Fields))
+	case errors.Is(err, service.ErrAddressNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Address not found", err.Error()))
+	case errors.Is(err, service.ErrAddressBookFull):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Address book is full", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Address book operation failed", err.Error()))
+	}
+	return true
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles platform administration endpoints.
+// Routes are mounted behind middleware.Require with the admin:* permissions.
+type AdminHandler struct {
+	adminService service.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// GetMetrics retrieves platform-wide metrics
+// GET /api/admin/metrics (Protected - Admin only)
+func (h *AdminHandler) GetMetrics(c *gin.Context) {
+	metrics, err := h.adminService.Metrics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve metrics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(metrics, "Metrics retrieved"))
+}
+
+// ListUsers lists and searches users by ?q= (name or email), ?role= and ?status=active|suspended
+// GET /api/admin/users (Protected - Admin only)
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, perPage := pageParams(c)
+	filter := domain.UserFilter{
+		Query:  c.Query("q"),
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+	}
+
+	data, err := h.adminService.ListUsers(filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to list users", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(data, "Users retrieved"))
+}
+
+// GetUser retrieves any user
+// GET /api/admin/users/:id (Protected - Admin only)
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.adminService.GetUser(c.Param("id"))
+	if h.userActionFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user, "User retrieved"))
+}
+
+// SuspendUser blocks an account and signs it out everywhere
+// POST /api/admin/users/:id/suspend (Protected - Admin only)
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	user, err := h.adminService.SuspendUser(admin.ID, c.Param("id"), req.Reason, clientInfo(c))
+	if h.userActionFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user, "User suspended"))
+}
+
+// ReactivateUser lifts a suspension
+// POST /api/admin/users/:id/reactivate (Protected - Admin only)
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminService.ReactivateUser(admin.ID, c.Param("id"), clientInfo(c))
+	if h.userActionFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user, "User reactivated"))
+}
+
+// ChangeRole sets a user's role
+// PUT /api/admin/users/:id/role (Protected - Admin only)
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	user, err := h.adminService.ChangeRole(admin.ID, c.Param("id"), req.Role, clientInfo(c))
+	if h.userActionFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(user, "Role updated"))
+}
+
+// ListAuditLog lists the changes made to an account
+// GET /api/admin/users/:id/audit-log (Protected - Admin only)
+func (h *AdminHandler) ListAuditLog(c *gin.Context) {
+	page, perPage := pageParams(c)
+	data, err := h.adminService.ListAuditLog(c.Param("id"), page, perPage)
+	if h.userActionFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(data, "Audit log retrieved"))
+}
+
+// ListProducts lists every product, including inactive ones, by ?q=, ?seller_id= and ?active=
+// GET /api/admin/products (Protected - Admin only)
+func (h *AdminHandler) ListProducts(c *gin.Context) {
+	page, perPage := pageParams(c)
+	filter := domain.ProductFilter{
+		Query:    c.Query("q"),
+		SellerID: c.Query("seller_id"),
+	}
+	if value := c.Query("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", "active must be true or false"))
+			return
+		}
+		filter.Active = &active
+	}
+
+	data, err := h.adminService.ListProducts(filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to list products", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(data, "Products retrieved"))
+}
+
+// ModerateProduct takes any product off sale (or back on) or changes whether it is featured
+// PATCH /api/admin/products/:id (Protected - Admin only)
+func (h *AdminHandler) ModerateProduct(c *gin.Context) {
+	var req domain.ModerateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	product, err := h.adminService.ModerateProduct(c.Param("id"), &req)
+	if errors.Is(err, service.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to moderate product", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(product, "Product updated"))
+}
+
+// DeleteProduct removes any product
+// DELETE /api/admin/products/:id (Protected - Admin only)
+func (h *AdminHandler) DeleteProduct(c *gin.Context) {
+	err := h.adminService.DeleteProduct(c.Param("id"))
+	if errors.Is(err, service.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to delete product", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Product deleted"))
+}
+
+// ListOrders lists every order by ?q= (order number), ?status= and ?user_id=
+// GET /api/admin/orders (Protected - Admin only)
+func (h *AdminHandler) ListOrders(c *gin.Context) {
+	page, perPage := pageParams(c)
+	filter := domain.OrderFilter{
+		Query:  c.Query("q"),
+		Status: c.Query("status"),
+		UserID: c.Query("user_id"),
+	}
+
+	data, err := h.adminService.ListOrders(filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to list orders", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(data, "Orders retrieved"))
+}
+
+// GetOrder retrieves any order
+// GET /api/admin/orders/:id (Protected - Admin only)
+func (h *AdminHandler) GetOrder(c *gin.Context) {
+	order, err := h.adminService.GetOrder(c.Param("id"))
+	if errors.Is(err, service.ErrOrderNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Order not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve order", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(order, "Order retrieved"))
+}
+
+// UpdateOrder changes the status, tracking number or notes of any order
+// PATCH /api/admin/orders/:id (Protected - Admin only)
+func (h *AdminHandler) UpdateOrder(c *gin.Context) {
+	var req domain.AdminUpdateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	order, err := h.adminService.UpdateOrder(c.Param("id"), &req)
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Order not found", err.Error()))
+		return
+	case errors.Is(err, service.ErrInvalidOrderStatus):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update order", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(order, "Order updated"))
+}
+
+// userActionFailed writes the error response of a user management call, reporting whether it did
+func (h *AdminHandler) userActionFailed(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found", err.Error()))
+	case errors.Is(err, service.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+	case errors.Is(err, service.ErrCannotModifySelf):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot modify own account", err.Error()))
+	case errors.Is(err, service.ErrAccountErased):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Account was erased", err.Error()))
+	case errors.Is(err, service.ErrNoStore):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("User has no store", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update user", err.Error()))
+	}
+	return true
+}
+
+// pageParams reads ?page= and ?per_page=; the service applies defaults and limits
+func pageParams(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	return page, perPage
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles the seller API key endpoints
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// ListAPIKeys lists the current seller's API keys
+// GET /api/seller/api-keys (Protected)
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyService.List(userData.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve API keys", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(keys, "API keys retrieved"))
+}
+
+// CreateAPIKey issues a new API key; the key itself is only returned here
+// POST /api/seller/api-keys (Protected)
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	key, err := h.apiKeyService.Create(userData, &req, clientInfo(c))
+	if h.apiKeyFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(key, "API key created, store it now: it will not be shown again"))
+}
+
+// RevokeAPIKey disables an API key
+// DELETE /api/seller/api-keys/:id (Protected)
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.apiKeyFailed(c, h.apiKeyService.Revoke(userData.ID, c.Param("id"), clientInfo(c))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "API key revoked"))
+}
+
+// apiKeyFailed writes the error response for a failed API key operation
+func (h *APIKeyHandler) apiKeyFailed(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid API key", validationErr.Fields))
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("API key not found", err.Error()))
+	case errors.Is(err, service.ErrAPIKeyLimitReached):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Too many API keys", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("API key operation failed", err.Error()))
+	}
+	return true
+}
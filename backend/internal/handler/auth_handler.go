@@ -4,19 +4,28 @@ import (
 	"ecommerce/internal/domain"
 	"ecommerce/internal/service"
 	"ecommerce/internal/utils"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	authService service.AuthService
+	authService    service.AuthService
+	cartService    service.CartService
+	accountService service.AccountService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService service.AuthService, cartService service.CartService, accountService service.AccountService) *AuthHandler {
+	return &AuthHandler{
+		authService:    authService,
+		cartService:    cartService,
+		accountService: accountService,
+	}
 }
 
 // Login handles user login
@@ -29,12 +38,54 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Login(req.Email, req.Password)
+	client := clientInfo(c)
+	client.DeviceName = req.DeviceName
+	resp, challenge, err := h.authService.Login(req.Email, req.Password, client)
+	if tooManyAttempts(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrAccountSuspended) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Authentication failed", err.Error()))
 		return
 	}
+	if challenge != nil {
+		c.JSON(http.StatusOK, utils.SuccessResponse(challenge, "Two-factor code required"))
+		return
+	}
+
+	h.mergeGuestCart(c, resp.User.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(resp, "Login successful"))
+}
+
+// CompleteLogin finishes a two-factor login with a TOTP or recovery code
+// POST /api/auth/login/2fa
+func (h *AuthHandler) CompleteLogin(c *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	client := clientInfo(c)
+	client.DeviceName = req.DeviceName
+	resp, err := h.authService.CompleteLogin(req.ChallengeToken, req.Code, client)
+	if tooManyAttempts(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrInvalidLoginChallenge) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	}
 
+	h.mergeGuestCart(c, resp.User.ID)
 	c.JSON(http.StatusOK, utils.SuccessResponse(resp, "Login successful"))
 }
 
@@ -48,16 +99,84 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.Register(&req)
+	resp, err := h.authService.Register(&req, clientInfo(c))
+	if tooManyAttempts(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Registration failed", err.Error()))
 		return
 	}
 
+	h.mergeGuestCart(c, resp.User.ID)
+	h.sendVerification(resp.User.ID)
 	c.JSON(http.StatusCreated, utils.SuccessResponse(resp, "Registration successful"))
 }
 
-// GetMe retrieves the current authenticated user
+// Refresh exchanges a refresh token for a new access token and refresh token
+// POST /api/auth/refresh
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req domain.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	resp, err := h.authService.Refresh(req.RefreshToken, clientInfo(c))
+	if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Refresh failed", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Refresh failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(resp, "Token refreshed"))
+}
+
+// Logout revokes the current session's access and refresh tokens
+// POST /api/auth/logout (Protected)
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := c.Get("claims")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No claims in context"))
+		return
+	}
+
+	if err := h.authService.Logout(claims.(*domain.AccessClaims)); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Logout failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Logged out"))
+}
+
+// LogoutAll revokes every session of the current user, on every device
+// POST /api/auth/logout-all (Protected)
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+		return
+	}
+
+	userData, ok := user.(*domain.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+		return
+	}
+
+	if err := h.authService.LogoutAll(userData.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Logout failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Logged out of all sessions"))
+}
+
+// GetMe retrieves the current authenticated user, with the impersonation state when an
+// admin is acting as them (the frontend shows a banner)
 // GET /api/auth/me (Protected)
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	// User is extracted from context by middleware
@@ -73,5 +192,60 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse(userData.ToResponse(), "User retrieved"))
+	resp := &domain.MeResponse{UserResponse: userData.ToResponse()}
+	if claims, ok := c.Get("claims"); ok {
+		if access, ok := claims.(*domain.AccessClaims); ok && access.IsImpersonation() {
+			resp.Impersonation = &domain.ImpersonationState{
+				ActorID:    access.Act.Subject,
+				ActorEmail: access.Act.Email,
+				ExpiresAt:  access.ExpiresAt.Time,
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(resp, "User retrieved"))
+}
+
+// sendVerification emails the verification link to a new account.
+// Failures are logged only: the user can ask for the link again.
+func (h *AuthHandler) sendVerification(userID string) {
+	if err := h.accountService.SendVerification(userID); err != nil {
+		log.Printf("failed to send verification email to user %s: %v", userID, err)
+	}
+}
+
+// mergeGuestCart moves the guest cart sent in X-Cart-Token into the user's cart
+func (h *AuthHandler) mergeGuestCart(c *gin.Context, userID string) {
+	mergeGuestCart(c, h.cartService, userID)
+}
+
+// mergeGuestCart moves the guest cart sent in X-Cart-Token into the user's cart.
+// Failures are logged only: a lost guest cart must not block logging in.
+func mergeGuestCart(c *gin.Context, cartService service.CartService, userID string) {
+	token := c.GetHeader(CartTokenHeader)
+	if token == "" {
+		return
+	}
+	if err := cartService.MergeGuestCart(token, userID); err != nil {
+		log.Printf("failed to merge guest cart into user %s: %v", userID, err)
+	}
+}
+
+// tooManyAttempts writes the 429 response, with Retry-After, when err is a *service.ThrottledError
+func tooManyAttempts(c *gin.Context, err error) bool {
+	var throttled *service.ThrottledError
+	if !errors.As(err, &throttled) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+	c.JSON(http.StatusTooManyRequests, utils.ErrorResponse("Too many attempts", err.Error()))
+	return true
+}
+
+// clientInfo describes the device making the request, recorded with new sessions
+func clientInfo(c *gin.Context) domain.ClientInfo {
+	return domain.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
 }
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartTokenHeader carries the guest cart token in requests and responses
+const CartTokenHeader = "X-Cart-Token"
+
+// CartHandler handles shopping cart endpoints
+type CartHandler struct {
+	cartService service.CartService
+}
+
+// NewCartHandler creates a new cart handler
+func NewCartHandler(cartService service.CartService) *CartHandler {
+	return &CartHandler{cartService: cartService}
+}
+
+// GetCart retrieves the current cart with up-to-date prices and availability
+// GET /api/cart (Optional auth, guests send X-Cart-Token)
+func (h *CartHandler) GetCart(c *gin.Context) {
+	userID, token := cartOwner(c)
+
+	cart, err := h.cartService.GetCart(userID, token, storefrontCurrency(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve cart", err.Error()))
+		return
+	}
+
+	respondWithCart(c, http.StatusOK, cart, "Cart retrieved")
+}
+
+// AddItem adds a product to the cart
+// POST /api/cart/items (Optional auth, guests send X-Cart-Token)
+func (h *CartHandler) AddItem(c *gin.Context) {
+	var req domain.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	userID, token := cartOwner(c)
+	cart, err := h.cartService.AddItem(userID, token, storefrontCurrency(c), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to add item", err.Error()))
+		return
+	}
+
+	respondWithCart(c, http.StatusOK, cart, "Item added to cart")
+}
+
+// UpdateItem changes the quantity of a cart line
+// PATCH /api/cart/items/:id (Optional auth, guests send X-Cart-Token)
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	var req domain.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	userID, token := cartOwner(c)
+	cart, err := h.cartService.UpdateItem(userID, token, storefrontCurrency(c), c.Param("id"), req.Quantity)
+	if errors.Is(err, service.ErrCartItemNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Cart item not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to update item", err.Error()))
+		return
+	}
+
+	respondWithCart(c, http.StatusOK, cart, "Cart item updated")
+}
+
+// RemoveItem deletes a cart line
+// DELETE /api/cart/items/:id (Optional auth, guests send X-Cart-Token)
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	userID, token := cartOwner(c)
+	cart, err := h.cartService.RemoveItem(userID, token, storefrontCurrency(c), c.Param("id"))
+	if errors.Is(err, service.ErrCartItemNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Cart item not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to remove item", err.Error()))
+		return
+	}
+
+	respondWithCart(c, http.StatusOK, cart, "Cart item removed")
+}
+
+// ClearCart removes every item from the cart
+// DELETE /api/cart (Optional auth, guests send X-Cart-Token)
+func (h *CartHandler) ClearCart(c *gin.Context) {
+	userID, token := cartOwner(c)
+	cart, err := h.cartService.Clear(userID, token, storefrontCurrency(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to clear cart", err.Error()))
+		return
+	}
+
+	respondWithCart(c, http.StatusOK, cart, "Cart cleared")
+}
+
+// cartOwner returns the authenticated user's ID, or the guest cart token when logged out
+func cartOwner(c *gin.Context) (string, string) {
+	if user, exists := c.Get("user"); exists {
+		if userData, ok := user.(*domain.User); ok {
+			return userData.ID, ""
+		}
+	}
+	return "", c.GetHeader(CartTokenHeader)
+}
+
+// respondWithCart writes the cart, echoing the guest token so new guests can store it
+func respondWithCart(c *gin.Context, status int, cart *domain.CartResponse, message string) {
+	if cart.Token != "" {
+		c.Header(CartTokenHeader, cart.Token)
+	}
+	c.JSON(status, utils.SuccessResponse(cart, message))
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userFromContext returns the authenticated user, writing the error response when missing
+func userFromContext(c *gin.Context) (*domain.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+		return nil, false
+	}
+
+	userData, ok := user.(*domain.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+		return nil, false
+	}
+
+	return userData, true
+}
+
+// storeFromContext returns the store the request acts for (see middleware.ResolveStore),
+// writing the error response when there is none
+func storeFromContext(c *gin.Context) (*domain.StoreAccess, bool) {
+	store, exists := c.Get("store")
+	if !exists {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("No store to act for", "Choose one of your stores with the X-Store-ID header"))
+		return nil, false
+	}
+
+	access, ok := store.(*domain.StoreAccess)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid store type"))
+		return nil, false
+	}
+
+	return access, true
+}
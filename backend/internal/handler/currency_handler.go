@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurrencyHandler handles storefront currency endpoints
+type CurrencyHandler struct {
+	converter service.CurrencyConverter
+}
+
+// NewCurrencyHandler creates a new currency handler
+func NewCurrencyHandler(converter service.CurrencyConverter) *CurrencyHandler {
+	return &CurrencyHandler{converter: converter}
+}
+
+// ListCurrencies retrieves the supported currencies and their display exchange rates
+// GET /api/currencies
+func (h *CurrencyHandler) ListCurrencies(c *gin.Context) {
+	currencies, err := h.converter.Currencies()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve currencies", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(currencies, "Currencies retrieved"))
+}
+
+// storefrontCurrency returns the currency chosen by CurrencyMiddleware
+func storefrontCurrency(c *gin.Context) string {
+	if currency := c.GetString("currency"); currency != "" {
+		return currency
+	}
+	return domain.DefaultCurrency
+}
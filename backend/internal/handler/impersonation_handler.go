@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles the support endpoint letting admins act as a user
+type ImpersonationHandler struct {
+	authService service.AuthService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(authService service.AuthService) *ImpersonationHandler {
+	return &ImpersonationHandler{authService: authService}
+}
+
+// Impersonate issues a short-lived token acting as the user, with the admin in its act claim
+// POST /api/admin/users/:id/impersonate (Protected - Admin only)
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	resp, err := h.authService.Impersonate(admin, c.Param("id"), req.Reason, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found", err.Error()))
+		return
+	case errors.Is(err, service.ErrCannotImpersonate):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot impersonate this user", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Impersonation failed", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(resp, "Impersonation started, every request is audited"))
+}
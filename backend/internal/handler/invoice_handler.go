@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"bytes"
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler handles NF-e endpoints for sellers
+type InvoiceHandler struct {
+	invoiceService service.InvoiceService
+}
+
+// NewInvoiceHandler creates a new invoice handler
+func NewInvoiceHandler(invoiceService service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{invoiceService: invoiceService}
+}
+
+// GetFiscalProfile retrieves the seller's fiscal data
+// GET /api/seller/fiscal-profile (Protected - Seller only)
+func (h *InvoiceHandler) GetFiscalProfile(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.invoiceService.GetFiscalProfile(access.SellerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Fiscal profile not found", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(profile, "Fiscal profile retrieved"))
+}
+
+// SaveFiscalProfile creates or updates the seller's fiscal data
+// PUT /api/seller/fiscal-profile (Protected - Seller only)
+func (h *InvoiceHandler) SaveFiscalProfile(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.FiscalProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	profile, err := h.invoiceService.SaveFiscalProfile(access.SellerID, &req)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid fiscal profile", validationErr.Fields))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save fiscal profile", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(profile, "Fiscal profile saved"))
+}
+
+// IssueInvoice generates and transmits the NF-e for the seller's items of an order
+// POST /api/seller/orders/:id/invoice (Protected - Seller only)
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.IssueInvoice(access.SellerID, c.Param("id"))
+	if errors.Is(err, service.ErrInvoiceExists) {
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Failed to issue invoice", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to issue invoice", err.Error()))
+		return
+	}
+
+	if invoice.Status != domain.InvoiceStatusAuthorized {
+		c.JSON(http.StatusUnprocessableEntity, utils.ErrorResponse("Invoice rejected by SEFAZ", invoice.StatusMessage))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(invoice, "Invoice issued successfully"))
+}
+
+// GetInvoice retrieves the invoice metadata of an order
+// GET /api/seller/orders/:id/invoice (Protected - Seller only)
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(access.SellerID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Invoice not found", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(invoice, "Invoice retrieved"))
+}
+
+// DownloadXML returns the invoice XML (nfeProc once authorized)
+// GET /api/seller/orders/:id/invoice/xml (Protected - Seller only)
+func (h *InvoiceHandler) DownloadXML(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(access.SellerID, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Invoice not found", err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+invoice.AccessKey+`-nfe.xml"`)
+	c.Data(http.StatusOK, "application/xml", []byte(invoice.XML))
+}
+
+// DownloadDANFE returns the DANFE PDF of the invoice
+// GET /api/seller/orders/:id/invoice/danfe (Protected - Seller only)
+func (h *InvoiceHandler) DownloadDANFE(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	var pdf bytes.Buffer
+	if err := h.invoiceService.RenderDANFE(access.SellerID, c.Param("id"), &pdf); err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Failed to render DANFE", err.Error()))
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="danfe.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"ecommerce/internal/jwtkeys"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the public keys access tokens are signed with
+type JWKSHandler struct {
+	keys *jwtkeys.Ring
+}
+
+// NewJWKSHandler creates a new JWKS handler
+func NewJWKSHandler(keys *jwtkeys.Ring) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS returns the key set as a bare JSON Web Key Set (RFC 7517), not wrapped in the
+// usual response envelope, so standard JWT libraries can consume it
+// GET /.well-known/jwks.json
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Short enough that verifiers notice scheduled keys well before they sign
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
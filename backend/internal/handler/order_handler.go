@@ -4,6 +4,7 @@ import (
 	"ecommerce/internal/domain"
 	"ecommerce/internal/service"
 	"ecommerce/internal/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -41,8 +42,16 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
 		return
 	}
+	if req.Currency == "" {
+		req.Currency = storefrontCurrency(c)
+	}
 
 	order, err := h.orderService.CreateOrder(userData.ID, &req)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid order data", validationErr.Fields))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to create order", err.Error()))
 		return
@@ -51,6 +60,35 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	c.JSON(http.StatusCreated, utils.SuccessResponse(order, "Order created successfully"))
 }
 
+// QuoteOrder prices a CreateOrderRequest without placing the order
+// POST /api/checkout/quote (Optional auth)
+func (h *OrderHandler) QuoteOrder(c *gin.Context) {
+	var req domain.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = storefrontCurrency(c)
+	}
+
+	// Logged-in customers may quote to one of their saved addresses
+	userID := ""
+	if user, exists := c.Get("user"); exists {
+		if userData, ok := user.(*domain.User); ok {
+			userID = userData.ID
+		}
+	}
+
+	quote, err := h.orderService.QuoteOrder(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to quote order", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(quote, "Quote calculated"))
+}
+
 // GetOrder retrieves a specific order
 // GET /api/orders/:id (Protected)
 func (h *OrderHandler) GetOrder(c *gin.Context) {
@@ -118,22 +156,8 @@ func (h *OrderHandler) GetMyOrders(c *gin.Context) {
 // GetSellerOrders retrieves orders for seller products
 // GET /api/seller/orders (Protected - Seller only)
 func (h *OrderHandler) GetSellerOrders(c *gin.Context) {
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-		return
-	}
-
-	userData, ok := user.(*domain.User)
+	access, ok := storeFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-		return
-	}
-
-	// Verify seller role
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can access this endpoint"))
 		return
 	}
 
@@ -153,7 +177,7 @@ func (h *OrderHandler) GetSellerOrders(c *gin.Context) {
 		}
 	}
 
-	orders, total, err := h.orderService.GetSellerOrders(userData.ID, limit, offset)
+	orders, total, err := h.orderService.GetSellerOrders(access.SellerID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch orders", err.Error()))
 		return
@@ -174,26 +198,12 @@ func (h *OrderHandler) GetSellerOrders(c *gin.Context) {
 // GetSellerAnalytics retrieves analytics for seller
 // GET /api/seller/analytics (Protected - Seller only)
 func (h *OrderHandler) GetSellerAnalytics(c *gin.Context) {
-	// Get user from context
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-		return
-	}
-
-	userData, ok := user.(*domain.User)
+	access, ok := storeFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-		return
-	}
-
-	// Verify seller role
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can access this endpoint"))
 		return
 	}
 
-	analytics, err := h.orderService.GetSellerAnalytics(userData.ID)
+	analytics, err := h.orderService.GetSellerAnalytics(access.SellerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch analytics", err.Error()))
 		return
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyHandler handles LGPD data export and account erasure endpoints
+type PrivacyHandler struct {
+	privacyService service.PrivacyService
+}
+
+// NewPrivacyHandler creates a new privacy handler
+func NewPrivacyHandler(privacyService service.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{privacyService: privacyService}
+}
+
+// RequestExport starts producing a copy of the current user's data
+// POST /api/me/data-export (Protected)
+func (h *PrivacyHandler) RequestExport(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.privacyService.RequestExport(userData.ID, clientInfo(c))
+	if h.privacyFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.SuccessResponse(request, "Data export requested"))
+}
+
+// GetExport returns the status of a data export
+// GET /api/me/data-export/:id (Protected)
+func (h *PrivacyHandler) GetExport(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.privacyService.GetExport(userData.ID, c.Param("id"))
+	if h.privacyFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(request, "Data export retrieved"))
+}
+
+// DownloadExport returns the ZIP archive of a completed data export
+// GET /api/me/data-export/:id/download (Protected)
+func (h *PrivacyHandler) DownloadExport(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.privacyService.DownloadExport(userData.ID, c.Param("id"))
+	if h.privacyFailed(c, err) {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="meus-dados-`+request.CreatedAt.Format("2006-01-02")+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", request.Archive)
+}
+
+// RequestErasure starts anonymizing the current user's account
+// DELETE /api/me (Protected)
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.EraseAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	// API keys carry no session, so they cannot stand in for a recent sign-in
+	sessionID := ""
+	if claims, ok := c.Get("claims"); ok {
+		sessionID = claims.(*domain.AccessClaims).ID
+	}
+
+	request, err := h.privacyService.RequestErasure(userData.ID, sessionID, &req)
+	if h.privacyFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.SuccessResponse(request, "Account erasure requested"))
+}
+
+// GetErasure returns the status of an account erasure
+// GET /api/privacy/erasure/:id
+func (h *PrivacyHandler) GetErasure(c *gin.Context) {
+	request, err := h.privacyService.GetErasure(c.Param("id"))
+	if h.privacyFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(request, "Account erasure retrieved"))
+}
+
+// privacyFailed writes the error response for a failed privacy operation
+func (h *PrivacyHandler) privacyFailed(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrPrivacyRequestNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Request not found", err.Error()))
+	case errors.Is(err, service.ErrExportNotReady):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Export not ready", err.Error()))
+	case errors.Is(err, service.ErrExportExpired):
+		c.JSON(http.StatusGone, utils.ErrorResponse("Export expired", err.Error()))
+	case errors.Is(err, service.ErrWrongPassword), errors.Is(err, service.ErrReauthRequired):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Account erasure failed", err.Error()))
+	case errors.Is(err, service.ErrErasureNotAllowed), errors.Is(err, service.ErrOpenOrders):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Account erasure failed", err.Error()))
+	case errors.Is(err, service.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Account suspended", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Privacy request failed", err.Error()))
+	}
+	return true
+}
@@ -4,6 +4,7 @@ import (
 	"ecommerce/internal/domain"
 	"ecommerce/internal/service"
 	"ecommerce/internal/utils"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -24,7 +25,7 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
 
-	data, err := h.service.List(page, perPage)
+	data, err := h.service.List(page, perPage, storefrontCurrency(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -44,40 +45,31 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	productID := c.Param("id")
 
-	product, err := h.service.GetByID(productID)
-	if err != nil {
+	product, err := h.service.GetByID(productID, storefrontCurrency(c))
+	if errors.Is(err, service.ErrProductNotFound) {
 		c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", err.Error()))
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve product", err.Error()))
+		return
+	}
 
 	c.JSON(http.StatusOK, utils.SuccessResponse(product, "Product retrieved"))
 }
 
-// GetSellerProducts retrieves all products for a seller
+// GetSellerProducts retrieves the products of the store the request acts for
 // GET /api/seller/products (Protected - Seller only)
 func (h *ProductHandler) GetSellerProducts(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-		return
-	}
-
-	userData, ok := user.(*domain.User)
+	access, ok := storeFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-		return
-	}
-
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can access this endpoint"))
 		return
 	}
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
 
-	// You would implement GetSellerProducts in the service layer
-	data, err := h.service.List(page, perPage)
+	data, err := h.service.ListBySeller(access.SellerID, page, perPage, storefrontCurrency(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to fetch products", err.Error()))
 		return
@@ -89,20 +81,8 @@ func (h *ProductHandler) GetSellerProducts(c *gin.Context) {
 // CreateProduct creates a new product (Seller only)
 // POST /api/seller/products (Protected - Seller only)
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-		return
-	}
-
-	userData, ok := user.(*domain.User)
+	access, ok := storeFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-		return
-	}
-
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can create products"))
 		return
 	}
 
@@ -112,7 +92,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	product.SellerID = userData.ID
+	product.SellerID = access.SellerID
 	product.IsActive = true
 
 	// You would implement Create in the service layer
@@ -122,20 +102,8 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // UpdateProduct updates an existing product (Seller only)
 // PUT /api/seller/products/:id (Protected - Seller only)
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-		return
-	}
-
-	userData, ok := user.(*domain.User)
+	access, ok := storeFromContext(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-		return
-	}
-
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can update products"))
 		return
 	}
 
@@ -148,29 +116,48 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	}
 
 	product.ID = productID
-	product.SellerID = userData.ID
+	product.SellerID = access.SellerID
 
 	// You would implement Update in the service layer
 	c.JSON(http.StatusOK, utils.SuccessResponse(product, "Product updated successfully"))
 }
 
-// DeleteProduct deletes a product (Seller only)
-// DELETE /api/seller/products/:id (Protected - Seller only)
-func (h *ProductHandler) DeleteProduct(c *gin.Context) {
-	user, exists := c.Get("user")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+// SetProductPrices replaces the product's price lists in other currencies
+// PUT /api/seller/products/:id/prices (Protected - Seller only)
+func (h *ProductHandler) SetProductPrices(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
 		return
 	}
 
-	userData, ok := user.(*domain.User)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+	var req domain.SetProductPricesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
 		return
 	}
 
-	if userData.Role != "seller" {
-		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Only sellers can delete products"))
+	product, err := h.service.SetPrices(access.SellerID, c.Param("id"), &req)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid price list", validationErr.Fields))
+		return
+	}
+	if errors.Is(err, service.ErrProductNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Product not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to save prices", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(product, "Product prices updated"))
+}
+
+// DeleteProduct deletes a product (Seller only)
+// DELETE /api/seller/products/:id (Protected - Seller only)
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	if _, ok := storeFromContext(c); !ok {
 		return
 	}
 
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SellerHandler handles seller onboarding, store profiles and public store pages
+type SellerHandler struct {
+	sellerService service.SellerService
+}
+
+// NewSellerHandler creates a new seller handler
+func NewSellerHandler(sellerService service.SellerService) *SellerHandler {
+	return &SellerHandler{sellerService: sellerService}
+}
+
+// Apply submits a request to sell on the platform
+// POST /api/seller/apply (Protected - Customer only)
+func (h *SellerHandler) Apply(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.SellerApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	application, err := h.sellerService.Apply(userData, &req)
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid seller application", validationErr.Fields))
+		return
+	case errors.Is(err, service.ErrAlreadySeller), errors.Is(err, service.ErrApplicationPending):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot apply to sell", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to submit application", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(application, "Application submitted for review"))
+}
+
+// GetMyApplication retrieves the status of the current user's latest application
+// GET /api/seller/application (Protected)
+func (h *SellerHandler) GetMyApplication(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.sellerService.GetMyApplication(userData.ID)
+	if errors.Is(err, service.ErrApplicationNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Application not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve application", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(application, "Application retrieved"))
+}
+
+// GetMyStore retrieves the seller's store profile
+// GET /api/seller/store (Protected - Seller only)
+func (h *SellerHandler) GetMyStore(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	store, err := h.sellerService.GetMyStore(access.SellerID)
+	if errors.Is(err, service.ErrStoreNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Store not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve store", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(store, "Store retrieved"))
+}
+
+// UpdateMyStore edits the seller's store profile
+// PUT /api/seller/store (Protected - Seller only)
+func (h *SellerHandler) UpdateMyStore(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	store, err := h.sellerService.UpdateMyStore(access.SellerID, &req)
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid store profile", validationErr.Fields))
+		return
+	case errors.Is(err, service.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Store not found", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to update store", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(store, "Store updated"))
+}
+
+// GetStore retrieves a store's public page with its products
+// GET /api/stores/:slug
+func (h *SellerHandler) GetStore(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	store, err := h.sellerService.GetStorePage(c.Param("slug"), page, perPage, storefrontCurrency(c))
+	if errors.Is(err, service.ErrStoreNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Store not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve store", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(store, "Store retrieved"))
+}
+
+// ListApplications lists seller applications for review, optionally by ?status=
+// GET /api/admin/seller-applications (Protected - Admin only)
+func (h *SellerHandler) ListApplications(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	data, err := h.sellerService.ListApplications(c.Query("status"), page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to list applications", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(data, "Applications retrieved"))
+}
+
+// ApproveApplication creates the applicant's store and makes them a seller
+// POST /api/admin/seller-applications/:id/approve (Protected - Admin only)
+func (h *SellerHandler) ApproveApplication(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	application, err := h.sellerService.Approve(admin.ID, c.Param("id"))
+	if !h.reviewFailed(c, err) {
+		c.JSON(http.StatusOK, utils.SuccessResponse(application, "Application approved"))
+	}
+}
+
+// RejectApplication closes an application with a reason
+// POST /api/admin/seller-applications/:id/reject (Protected - Admin only)
+func (h *SellerHandler) RejectApplication(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.RejectApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	application, err := h.sellerService.Reject(admin.ID, c.Param("id"), req.Reason)
+	if !h.reviewFailed(c, err) {
+		c.JSON(http.StatusOK, utils.SuccessResponse(application, "Application rejected"))
+	}
+}
+
+// reviewFailed writes the error response of an approve/reject call, reporting whether it did
+func (h *SellerHandler) reviewFailed(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrApplicationNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Application not found", err.Error()))
+	case errors.Is(err, service.ErrApplicationReviewed):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Application already reviewed", err.Error()))
+	case errors.Is(err, service.ErrApplicantIneligible):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Applicant cannot become a seller", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to review application", err.Error()))
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler handles the endpoints listing and signing out the user's login sessions
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// ListSessions lists the current user's active sessions
+// GET /api/me/sessions (Protected)
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.List(userData.ID, currentSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve sessions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(sessions, "Sessions retrieved"))
+}
+
+// RenameSession names the device of a session
+// PATCH /api/me/sessions/:id (Protected)
+func (h *SessionHandler) RenameSession(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.RenameSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	session, err := h.sessionService.Rename(userData.ID, c.Param("id"), req.DeviceName)
+	if h.sessionFailed(c, err) {
+		return
+	}
+	session.Current = session.ID == currentSessionID(c)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(session, "Session renamed"))
+}
+
+// RevokeSession signs a session out; revoking the current one works like logging out
+// DELETE /api/me/sessions/:id (Protected)
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.sessionFailed(c, h.sessionService.Revoke(userData.ID, c.Param("id"), clientInfo(c))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Session revoked"))
+}
+
+// RevokeOtherSessions signs out every session but the one making the request
+// DELETE /api/me/sessions (Protected)
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.sessionFailed(c, h.sessionService.RevokeOthers(userData.ID, currentSessionID(c), clientInfo(c))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Other sessions revoked"))
+}
+
+// sessionFailed writes the error response for a failed session operation
+func (h *SessionHandler) sessionFailed(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Session not found", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Session operation failed", err.Error()))
+	}
+	return true
+}
+
+// currentSessionID returns the session (jti) of the request's access token
+func currentSessionID(c *gin.Context) string {
+	if claims, ok := c.Get("claims"); ok {
+		if access, ok := claims.(*domain.AccessClaims); ok {
+			return access.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SocialLoginHandler handles the OIDC social login endpoints.
+// The frontend starts the flow, sends the browser to the provider and posts the code and
+// state the provider redirected back with; the answer is the same as /api/auth/login.
+type SocialLoginHandler struct {
+	socialLoginService service.SocialLoginService
+	cartService        service.CartService
+}
+
+// NewSocialLoginHandler creates a new social login handler
+func NewSocialLoginHandler(socialLoginService service.SocialLoginService, cartService service.CartService) *SocialLoginHandler {
+	return &SocialLoginHandler{
+		socialLoginService: socialLoginService,
+		cartService:        cartService,
+	}
+}
+
+// ListProviders lists the configured providers
+// GET /api/auth/oidc
+func (h *SocialLoginHandler) ListProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse(h.socialLoginService.Providers(), "Login providers retrieved"))
+}
+
+// Start returns the provider URL to send the browser to
+// POST /api/auth/oidc/:provider/start
+func (h *SocialLoginHandler) Start(c *gin.Context) {
+	authorization, err := h.socialLoginService.Start(c.Param("provider"))
+	if errors.Is(err, service.ErrUnknownProvider) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Provider not found", err.Error()))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, utils.ErrorResponse("Failed to start login", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(authorization, "Redirect to the provider"))
+}
+
+// Callback finishes the login with the provider's code and state
+// POST /api/auth/oidc/:provider/callback
+func (h *SocialLoginHandler) Callback(c *gin.Context) {
+	var req domain.OIDCCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	resp, challenge, err := h.socialLoginService.Callback(c.Param("provider"), &req, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Provider not found", err.Error()))
+		return
+	case errors.Is(err, service.ErrInvalidOIDCState), errors.Is(err, service.ErrSocialLoginFailed):
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	case errors.Is(err, service.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	case errors.Is(err, service.ErrIdentityEmailMissing), errors.Is(err, service.ErrIdentityEmailUnverified):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Authentication failed", err.Error()))
+		return
+	}
+	if challenge != nil {
+		c.JSON(http.StatusOK, utils.SuccessResponse(challenge, "Two-factor code required"))
+		return
+	}
+
+	mergeGuestCart(c, h.cartService, resp.User.ID)
+	c.JSON(http.StatusOK, utils.SuccessResponse(resp, "Login successful"))
+}
+
+// ListIdentities lists the provider accounts linked to the current user
+// GET /api/auth/identities (Protected)
+func (h *SocialLoginHandler) ListIdentities(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	identities, err := h.socialLoginService.ListIdentities(userData.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve identities", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(identities, "Identities retrieved"))
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreTeamHandler handles store team management and the invited users' side of it
+type StoreTeamHandler struct {
+	storeTeamService service.StoreTeamService
+}
+
+// NewStoreTeamHandler creates a new store team handler
+func NewStoreTeamHandler(storeTeamService service.StoreTeamService) *StoreTeamHandler {
+	return &StoreTeamHandler{storeTeamService: storeTeamService}
+}
+
+// ListMembers lists the members and pending invitations of the store
+// GET /api/seller/team (Protected - store owner and managers)
+func (h *StoreTeamHandler) ListMembers(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.storeTeamService.ListMembers(access.StoreID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve team", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(members, "Team retrieved"))
+}
+
+// InviteMember invites an email to the store team with a role
+// POST /api/seller/team (Protected - store owner and managers)
+func (h *StoreTeamHandler) InviteMember(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.InviteStoreMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	member, err := h.storeTeamService.Invite(access, userData, &req)
+	if h.teamFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse(member, "Invitation sent"))
+}
+
+// UpdateMember changes the role of a member or invitation
+// PUT /api/seller/team/:id (Protected - store owner and managers)
+func (h *StoreTeamHandler) UpdateMember(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateStoreMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	member, err := h.storeTeamService.ChangeRole(access.StoreID, c.Param("id"), req.Role)
+	if h.teamFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(member, "Member updated"))
+}
+
+// RemoveMember removes a member from the store, or cancels an invitation
+// DELETE /api/seller/team/:id (Protected - store owner and managers)
+func (h *StoreTeamHandler) RemoveMember(c *gin.Context) {
+	access, ok := storeFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.teamFailed(c, h.storeTeamService.RemoveMember(access.StoreID, c.Param("id"))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Member removed"))
+}
+
+// ListMyStores lists the stores the current user can act for, with their role in each
+// GET /api/me/stores (Protected)
+func (h *StoreTeamHandler) ListMyStores(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	stores, err := h.storeTeamService.ListMyStores(userData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve stores", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(stores, "Stores retrieved"))
+}
+
+// LeaveStore ends the current user's membership of a store
+// DELETE /api/me/stores/:id (Protected)
+func (h *StoreTeamHandler) LeaveStore(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.teamFailed(c, h.storeTeamService.LeaveStore(userData, c.Param("id"))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "You left the store"))
+}
+
+// ListInvitations lists the store invitations addressed to the current user
+// GET /api/me/store-invitations (Protected)
+func (h *StoreTeamHandler) ListInvitations(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.storeTeamService.ListInvitations(userData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve invitations", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(invitations, "Invitations retrieved"))
+}
+
+// AcceptInvitation joins the inviting store's team
+// POST /api/me/store-invitations/:id/accept (Protected)
+func (h *StoreTeamHandler) AcceptInvitation(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	store, err := h.storeTeamService.AcceptInvitation(userData, c.Param("id"))
+	if h.teamFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(store, "Invitation accepted"))
+}
+
+// DeclineInvitation deletes an invitation addressed to the current user
+// DELETE /api/me/store-invitations/:id (Protected)
+func (h *StoreTeamHandler) DeclineInvitation(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	if h.teamFailed(c, h.storeTeamService.DeclineInvitation(userData, c.Param("id"))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Invitation declined"))
+}
+
+// teamFailed writes the error response for a failed store team operation
+func (h *StoreTeamHandler) teamFailed(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusUnprocessableEntity, utils.ValidationErrorResponse("Invalid team member", validationErr.Fields))
+	case errors.Is(err, service.ErrStoreMemberNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Member not found", err.Error()))
+	case errors.Is(err, service.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Invitation not found", err.Error()))
+	case errors.Is(err, service.ErrStoreMemberExists), errors.Is(err, service.ErrStoreTeamFull):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Cannot invite member", err.Error()))
+	case errors.Is(err, service.ErrInvitationEmailNotVerified), errors.Is(err, service.ErrStoreAccessDenied):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Team operation failed", err.Error()))
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler handles TOTP enrolment and the admin two-factor policy endpoints
+type TwoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// GetStatus describes the current user's two-factor setup
+// GET /api/auth/2fa (Protected)
+func (h *TwoFactorHandler) GetStatus(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactorService.Status(userData.ID)
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(status, "Two-factor status retrieved"))
+}
+
+// Enroll starts enrolment and returns the secret as an otpauth URI and QR code
+// POST /api/auth/2fa/enroll (Protected)
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TwoFactorPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(userData.ID, req.Password)
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(enrollment, "Scan the QR code and confirm with a code"))
+}
+
+// Confirm turns two-factor on and returns the recovery codes
+// POST /api/auth/2fa/confirm (Protected)
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(userData.ID, req.Code, clientInfo(c))
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"recoveryCodes": codes}, "Two-factor authentication enabled, store the recovery codes safely"))
+}
+
+// Disable turns two-factor off
+// POST /api/auth/2fa/disable (Protected)
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	if h.twoFactorFailed(c, h.twoFactorService.Disable(userData.ID, &req, clientInfo(c))) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(nil, "Two-factor authentication disabled"))
+}
+
+// RenewRecoveryCodes replaces the recovery codes
+// POST /api/auth/2fa/recovery-codes (Protected)
+func (h *TwoFactorHandler) RenewRecoveryCodes(c *gin.Context) {
+	userData, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	codes, err := h.twoFactorService.RenewRecoveryCodes(userData.ID, req.Code, clientInfo(c))
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(gin.H{"recoveryCodes": codes}, "Recovery codes renewed"))
+}
+
+// ListRolePolicies lists the security requirements of every role
+// GET /api/admin/security/roles (Protected - Admin only)
+func (h *TwoFactorHandler) ListRolePolicies(c *gin.Context) {
+	policies, err := h.twoFactorService.ListRolePolicies()
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(policies, "Role policies retrieved"))
+}
+
+// UpdateRolePolicy requires or stops requiring two-factor for a role
+// PUT /api/admin/security/roles/:role (Protected - Admin only)
+func (h *TwoFactorHandler) UpdateRolePolicy(c *gin.Context) {
+	admin, ok := userFromContext(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateRolePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+		return
+	}
+
+	policy, err := h.twoFactorService.SetRolePolicy(admin.ID, c.Param("role"), *req.RequireTwoFactor, clientInfo(c))
+	if h.twoFactorFailed(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(policy, "Role policy updated"))
+}
+
+// twoFactorFailed writes the error response for a failed two-factor operation
+func (h *TwoFactorHandler) twoFactorFailed(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrWrongPassword), errors.Is(err, service.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Verification failed", err.Error()))
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled), errors.Is(err, service.ErrTwoFactorNotEnrolled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled), errors.Is(err, service.ErrTwoFactorRequired):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Two-factor operation failed", err.Error()))
+	case errors.Is(err, service.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request", err.Error()))
+	case errors.Is(err, service.ErrAccountSuspended):
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Account suspended", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Two-factor operation failed", err.Error()))
+	}
+	return true
+}
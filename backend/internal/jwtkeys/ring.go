@@ -0,0 +1,322 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for signing
+const minRSABits = 2048
+
+// activationLayout is the optional date prefix of a key file name: "2026-11-01_b.pem"
+// starts signing on November 1st (UTC). Files without it are active right away.
+const activationLayout = "2006-01-02"
+
+// Key is one signing key of a Ring
+type Key struct {
+	ID         string    // The JWT "kid" header, and the file name without .pem
+	ActiveFrom time.Time // When it starts signing; zero means always
+	method     jwt.SigningMethod
+	private    crypto.Signer
+}
+
+// NewKey wraps an RSA (RS256, at least 2048 bits) or Ed25519 (EdDSA) private key
+func NewKey(id string, private crypto.Signer, activeFrom time.Time) (*Key, error) {
+	key := &Key{ID: id, ActiveFrom: activeFrom, private: private}
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("jwtkeys: RSA key %q has %d bits, at least %d are required", id, k.N.BitLen(), minRSABits)
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("jwtkeys: key %q is neither RSA nor Ed25519", id)
+	}
+	return key, nil
+}
+
+// Algorithm returns the JWS algorithm the key signs with
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// Ring holds the signing keys of access tokens. The newest key whose ActiveFrom has passed
+// signs; the key it replaced keeps verifying for the grace period (the access token
+// lifetime), so tokens signed just before a rotation stay valid until they expire. Keys
+// scheduled for later are published in the JWKS ahead of time, so verifiers have them
+// cached by the time they sign anything.
+type Ring struct {
+	dir   string
+	grace time.Duration
+
+	mu   sync.RWMutex
+	keys []*Key // By ActiveFrom, then ID
+}
+
+// NewRing creates a ring of fixed keys
+func NewRing(keys []*Key, grace time.Duration) (*Ring, error) {
+	r := &Ring{grace: grace}
+	if err := r.set(keys); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LoadDir creates a ring from the PEM private keys (PKCS#8, or PKCS#1 for RSA) in dir.
+// Each *.pem file is one key named after the file; see activationLayout for scheduling.
+// Reload picks up keys added or removed later.
+func LoadDir(dir string, grace time.Duration) (*Ring, error) {
+	r := &Ring{dir: dir, grace: grace}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Generate creates a ring with one Ed25519 key that only lives in memory (development):
+// tokens stop verifying when the process restarts and differ between instances
+func Generate(grace time.Duration) (*Ring, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	key, err := NewKey("dev-"+time.Now().UTC().Format("20060102150405"), private, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return NewRing([]*Key{key}, grace)
+}
+
+// Reload reads the key directory again. On error the ring keeps its current keys.
+func (r *Ring) Reload() error {
+	if r.dir == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	return r.set(keys)
+}
+
+// Sign signs claims with the current signing key, naming it in the "kid" header
+func (r *Ring) Sign(claims jwt.Claims) (string, error) {
+	key := r.signingKey(time.Now())
+	if key == nil {
+		return "", errors.New("jwtkeys: no active signing key")
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.private)
+}
+
+// Keyfunc returns the public key a token names in its "kid" header, as long as that key
+// may verify tokens now and matches the token's algorithm. It is meant for jwt.Parse.
+func (r *Ring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	now := time.Now()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i, key := range r.keys {
+		if key.ID != kid {
+			continue
+		}
+		if !r.verifies(i, now) {
+			return nil, fmt.Errorf("jwtkeys: key %q is not in use", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("jwtkeys: key %q does not sign %s", kid, token.Method.Alg())
+		}
+		return key.private.Public(), nil
+	}
+	return nil, fmt.Errorf("jwtkeys: unknown key %q", kid)
+}
+
+// Algorithms lists the algorithms of the ring's keys, for jwt.WithValidMethods
+func (r *Ring) Algorithms() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var algs []string
+	for _, key := range r.keys {
+		if !containsString(algs, key.method.Alg()) {
+			algs = append(algs, key.method.Alg())
+		}
+	}
+	return algs
+}
+
+// JWK is the public half of a key, as published in a JSON Web Key Set
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // Ed25519
+	X   string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+// JWKSet is a JSON Web Key Set
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the keys verifiers should accept: those that verify now and those
+// scheduled to sign later
+func (r *Ring) JWKS() JWKSet {
+	now := time.Now()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for i, key := range r.keys {
+		if !r.verifies(i, now) && !key.ActiveFrom.After(now) {
+			continue
+		}
+		jwk := JWK{Use: "sig", Alg: key.method.Alg(), Kid: key.ID}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// Current returns the key signing now, nil when none is active yet
+func (r *Ring) Current() *Key {
+	return r.signingKey(time.Now())
+}
+
+func (r *Ring) set(keys []*Key) error {
+	if len(keys) == 0 {
+		return errors.New("jwtkeys: no signing keys")
+	}
+	sorted := append([]*Key(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].ActiveFrom.Equal(sorted[j].ActiveFrom) {
+			return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].ID == sorted[i-1].ID {
+			return fmt.Errorf("jwtkeys: duplicate key ID %q", sorted[i].ID)
+		}
+	}
+	if sorted[0].ActiveFrom.After(time.Now()) {
+		return errors.New("jwtkeys: no key is active yet")
+	}
+
+	r.mu.Lock()
+	r.keys = sorted
+	r.mu.Unlock()
+	return nil
+}
+
+// signingKey returns the newest key active at now
+func (r *Ring) signingKey(now time.Time) *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var current *Key
+	for _, key := range r.keys {
+		if key.ActiveFrom.After(now) {
+			break
+		}
+		current = key
+	}
+	return current
+}
+
+// verifies reports whether keys[i] is active and was not replaced more than the grace
+// period ago. Callers hold mu.
+func (r *Ring) verifies(i int, now time.Time) bool {
+	if r.keys[i].ActiveFrom.After(now) {
+		return false
+	}
+	if i+1 == len(r.keys) || r.keys[i+1].ActiveFrom.After(now) {
+		return true
+	}
+	return now.Before(r.keys[i+1].ActiveFrom.Add(r.grace))
+}
+
+// loadKeyFile parses one PEM private key file
+func loadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: %s is not PEM", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwtkeys: %s holds a %q, not a private key", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: %s: %w", path, err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: %s: unsupported key type", path)
+	}
+
+	id := strings.TrimSuffix(filepath.Base(path), ".pem")
+	var activeFrom time.Time
+	if len(id) > len(activationLayout) && id[len(activationLayout)] == '_' {
+		if activeFrom, err = time.Parse(activationLayout, id[:len(activationLayout)]); err != nil {
+			return nil, fmt.Errorf("jwtkeys: %s: invalid activation date: %w", path, err)
+		}
+	}
+	return NewKey(id, signer, activeFrom)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
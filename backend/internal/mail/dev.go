@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogMailer writes messages to the application log instead of sending them (development)
+type LogMailer struct{}
+
+// NewLogMailer creates a mailer that logs every message
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+// Send logs the message
+func (LogMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	log.Printf("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileMailer writes each message to its own file in a directory (development)
+type FileMailer struct {
+	dir string
+}
+
+// NewFileMailer creates a mailer writing into dir, creating it when missing
+func NewFileMailer(dir string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mail: %w", err)
+	}
+	return &FileMailer{dir: dir}, nil
+}
+
+// Send writes the message to <dir>/<timestamp>-<recipient>.eml
+func (m *FileMailer) Send(msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	recipient := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, msg.To)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), recipient)
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o644)
+}
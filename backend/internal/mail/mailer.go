@@ -0,0 +1,30 @@
+// Package mail sends transactional email through a pluggable Mailer.
+package mail
+
+import (
+	"errors"
+	"strings"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// validate rejects messages that cannot be delivered, and header injection through To or Subject
+func (m Message) validate() error {
+	if m.To == "" {
+		return errors.New("mail: message has no recipient")
+	}
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return errors.New("mail: line breaks are not allowed in headers")
+	}
+	return nil
+}
@@ -1,15 +1,29 @@
 package middleware
 
 import (
+	"ecommerce/internal/domain"
 	"ecommerce/internal/service"
 	"ecommerce/internal/utils"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT tokens
+// allowAPIKeysKey marks, in the request context, routes where API keys are accepted
+const allowAPIKeysKey = "allowApiKeys"
+
+// AllowAPIKeys lets the AuthMiddleware that follows it accept "Authorization: ApiKey <key>".
+// Key requests set "apiKey" in the context and are limited to the key's scopes by Require.
+func AllowAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(allowAPIKeysKey, true)
+		c.Next()
+	}
+}
+
+// AuthMiddleware validates JWT tokens, and API keys on routes behind AllowAPIKeys
 func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
@@ -20,8 +34,12 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 			return
 		}
 
-		// Extract token (format: "Bearer <token>")
+		// Extract token (format: "Bearer <token>" or "ApiKey <key>")
 		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "ApiKey" {
+			authenticateAPIKey(c, authService, parts[1])
+			return
+		}
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "Invalid authorization header format"))
 			c.Abort()
@@ -30,18 +48,15 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// Validate token
-		claims, err := authService.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "Invalid or expired token"))
+		// Validate token (signature, expiry and session revocation) and load its user
+		user, claims, err := authService.Authenticate(tokenString)
+		if errors.Is(err, service.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Account suspended"))
 			c.Abort()
 			return
 		}
-
-		// Get user from token
-		user, err := authService.GetUserFromToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "User not found"))
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "Invalid, expired or revoked token"))
 			c.Abort()
 			return
 		}
@@ -50,34 +65,34 @@ func AuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		c.Set("user", user)
 		c.Set("claims", claims)
 
-		c.Next()
+		nextAudited(c, authService, claims)
 	}
 }
 
-// RoleMiddleware checks if user has specific role
-func RoleMiddleware(requiredRole string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		user, exists := c.Get("user")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
-			c.Abort()
-			return
-		}
-
-		// Type assertion - this should work after AuthMiddleware sets it
-		userInterface, ok := user.(interface{})
-		if !ok {
-			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
-			c.Abort()
-			return
-		}
-
-		// You would need to check the role here
-		// For now, we'll skip this check as it's already done in handlers
-		_ = userInterface
+// authenticateAPIKey handles an ApiKey Authorization header for AuthMiddleware
+func authenticateAPIKey(c *gin.Context, authService service.AuthService, key string) {
+	if !c.GetBool(allowAPIKeysKey) {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "API keys are not accepted on this route"))
+		c.Abort()
+		return
+	}
 
-		c.Next()
+	client := domain.ClientInfo{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+	user, apiKey, err := authService.AuthenticateAPIKey(key, client)
+	if errors.Is(err, service.ErrAccountSuspended) {
+		c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Account suspended"))
+		c.Abort()
+		return
 	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "Invalid, expired or revoked API key"))
+		c.Abort()
+		return
+	}
+
+	c.Set("user", user)
+	c.Set("apiKey", apiKey)
+	c.Next()
 }
 
 // OptionalAuthMiddleware allows both authenticated and unauthenticated requests
@@ -96,13 +111,69 @@ func OptionalAuthMiddleware(authService service.AuthService) gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
-		user, err := authService.GetUserFromToken(tokenString)
+		user, claims, err := authService.Authenticate(tokenString)
 		if err != nil {
 			c.Next()
 			return
 		}
 
 		c.Set("user", user)
+		c.Set("claims", claims)
+		nextAudited(c, authService, claims)
+	}
+}
+
+// nextAudited runs the rest of the chain, auditing the request when an admin is
+// impersonating the user
+func nextAudited(c *gin.Context, authService service.AuthService, claims *domain.AccessClaims) {
+	c.Next()
+	if claims.IsImpersonation() {
+		client := domain.ClientInfo{UserAgent: c.Request.UserAgent(), IPAddress: c.ClientIP()}
+		authService.RecordImpersonatedRequest(claims, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), client)
+	}
+}
+
+// BlockImpersonation refuses the route to impersonation tokens: support staff see what the
+// user sees but cannot pay, change credentials or profile data, apply to sell, edit the store,
+// issue fiscal documents, reprice, download data exports or delete data on their behalf.
+// It must run after AuthMiddleware.
+func BlockImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := c.Get("claims"); ok {
+			if access, ok := claims.(*domain.AccessClaims); ok && access.IsImpersonation() {
+				c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Not allowed while impersonating a user"))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail rejects users who have not confirmed their email address.
+// It must run after AuthMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+			c.Abort()
+			return
+		}
+
+		userData, ok := user.(*domain.User)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+			c.Abort()
+			return
+		}
+
+		if !userData.IsEmailVerified() {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Email not verified", "Confirm your email address before continuing"))
+			c.Abort()
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CurrencyHeader selects the storefront currency when the currency query param is absent
+const CurrencyHeader = "X-Currency"
+
+// CurrencyMiddleware resolves the storefront currency from ?currency= or the X-Currency header,
+// defaulting to domain.DefaultCurrency, and stores it in the context as "currency"
+func CurrencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := c.Query("currency")
+		if requested == "" {
+			requested = c.GetHeader(CurrencyHeader)
+		}
+
+		currency := domain.NormalizeCurrency(requested)
+		if currency == "" {
+			currency = domain.DefaultCurrency
+		}
+		if !domain.IsSupportedCurrency(currency) {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Unsupported currency", requested))
+			c.Abort()
+			return
+		}
+
+		c.Set("currency", currency)
+		c.Header(CurrencyHeader, currency)
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Require rejects users whose role lacks any of perms, and API key requests whose key
+// lacks any of them. On routes behind ResolveStore, store permissions come from the user's
+// role in the acting store rather than their account role. It must run after AuthMiddleware.
+func Require(perms ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+			c.Abort()
+			return
+		}
+
+		userData, ok := user.(*domain.User)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+			c.Abort()
+			return
+		}
+
+		apiKey, _ := c.Get("apiKey")
+		keyData, _ := apiKey.(*domain.APIKey)
+		store := storeAccess(c)
+		for _, perm := range perms {
+			granted := userData.Can(perm)
+			if store != nil && domain.IsStorePermission(perm) {
+				granted = store.Can(perm)
+			}
+			if !granted {
+				c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "Missing permission "+string(perm)))
+				c.Abort()
+				return
+			}
+			if keyData != nil && !keyData.HasScope(perm) {
+				c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "API key lacks scope "+string(perm)))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
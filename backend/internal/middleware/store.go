@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResolveStore sets the store the request acts for: the one named by the X-Store-ID header,
+// or by default the seller's own store or the user's only membership. Routes needing a
+// store answer 403 when there is none. Require then checks store permissions against the
+// user's role in that store instead of their account role.
+// API keys act for their owner's store only. It must run after AuthMiddleware.
+func ResolveStore(storeTeamService service.StoreTeamService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+			c.Abort()
+			return
+		}
+
+		userData, ok := user.(*domain.User)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+			c.Abort()
+			return
+		}
+
+		storeID := strings.TrimSpace(c.GetHeader("X-Store-ID"))
+		if _, isKey := c.Get("apiKey"); isKey && storeID != "" {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", "API keys act for their owner's store only"))
+			c.Abort()
+			return
+		}
+
+		access, err := storeTeamService.ResolveAccess(userData, storeID)
+		if errors.Is(err, service.ErrStoreAccessDenied) {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Forbidden", err.Error()))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", err.Error()))
+			c.Abort()
+			return
+		}
+
+		if access != nil {
+			c.Set("store", access)
+		}
+		c.Next()
+	}
+}
+
+// storeAccess returns the store set by ResolveStore, nil outside store routes
+func storeAccess(c *gin.Context) *domain.StoreAccess {
+	value, _ := c.Get("store")
+	access, _ := value.(*domain.StoreAccess)
+	return access
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/service"
+	"ecommerce/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireTwoFactorPolicy rejects users whose role must use two-factor authentication
+// but who have not enrolled yet. Enrolment itself lives under /api/auth/2fa, outside
+// the routes this guards. Team members acting for a store (see ResolveStore) follow the
+// seller policy. It must run after AuthMiddleware.
+func RequireTwoFactorPolicy(twoFactorService service.TwoFactorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Unauthorized", "No user in context"))
+			c.Abort()
+			return
+		}
+
+		userData, ok := user.(*domain.User)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", "Invalid user type"))
+			c.Abort()
+			return
+		}
+
+		if !userData.HasTwoFactor() {
+			role := userData.Role
+			if storeAccess(c) != nil {
+				role = domain.RoleSeller
+			}
+			required, err := twoFactorService.IsRequired(role)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Internal error", err.Error()))
+				c.Abort()
+				return
+			}
+			if required {
+				c.JSON(http.StatusForbidden, utils.ErrorResponse("Two-factor authentication required", "Enable two-factor authentication at /api/auth/2fa/enroll"))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,117 @@
+package nfe
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Model is the fiscal document model code for NF-e
+const Model = "55"
+
+// StateCodes maps each UF to its IBGE code (cUF)
+var StateCodes = map[string]string{
+	"RO": "11", "AC": "12", "AM": "13", "RR": "14", "PA": "15", "AP": "16", "TO": "17",
+	"MA": "21", "PI": "22", "CE": "23", "RN": "24", "PB": "25", "PE": "26", "AL": "27",
+	"SE": "28", "BA": "29", "MG": "31", "ES": "32", "RJ": "33", "SP": "35", "PR": "41",
+	"SC": "42", "RS": "43", "MS": "50", "MT": "51", "GO": "52", "DF": "53",
+}
+
+// AccessKeyParts are the fields that compose the 44-digit chave de acesso
+type AccessKeyParts struct {
+	State        string    // UF of the issuer
+	IssuedAt     time.Time // Only year and month are used (AAMM)
+	CNPJ         string    // Issuer CNPJ, 14 digits
+	Series       int
+	Number       int
+	EmissionType int    // tpEmis, 1 = normal
+	RandomCode   string // cNF, 8 digits
+}
+
+// BuildAccessKey assembles the chave de acesso and appends its check digit
+func BuildAccessKey(p AccessKeyParts) (string, error) {
+	stateCode, ok := StateCodes[strings.ToUpper(p.State)]
+	if !ok {
+		return "", errors.New("unknown issuer state: " + p.State)
+	}
+	if len(p.CNPJ) != 14 {
+		return "", errors.New("issuer CNPJ must have 14 digits")
+	}
+	if p.Series < 0 || p.Series > 999 || p.Number < 1 || p.Number > 999999999 {
+		return "", fmt.Errorf("invalid series/number %d/%d", p.Series, p.Number)
+	}
+	if len(p.RandomCode) != 8 {
+		return "", errors.New("random code (cNF) must have 8 digits")
+	}
+
+	key := fmt.Sprintf("%s%s%s%s%03d%09d%d%s",
+		stateCode,
+		p.IssuedAt.Format("0601"),
+		p.CNPJ,
+		Model,
+		p.Series,
+		p.Number,
+		p.EmissionType,
+		p.RandomCode,
+	)
+	return key + fmt.Sprint(CheckDigit(key)), nil
+}
+
+// CheckDigit computes the modulo-11 check digit (cDV) of the first 43 digits of a key,
+// with weights 2..9 applied cyclically from the rightmost digit
+func CheckDigit(key43 string) int {
+	sum, weight := 0, 2
+	for i := len(key43) - 1; i >= 0; i-- {
+		sum += int(key43[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	rest := sum % 11
+	if rest < 2 {
+		return 0
+	}
+	return 11 - rest
+}
+
+// ValidAccessKey reports whether key has 44 digits and a matching check digit
+func ValidAccessKey(key string) bool {
+	if len(key) != 44 {
+		return false
+	}
+	for _, r := range key {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return CheckDigit(key[:43]) == int(key[43]-'0')
+}
+
+// FormatAccessKey groups the key in blocks of four digits, as printed on the DANFE
+func FormatAccessKey(key string) string {
+	var groups []string
+	for i := 0; i < len(key); i += 4 {
+		end := min(i+4, len(key))
+		groups = append(groups, key[i:end])
+	}
+	return strings.Join(groups, " ")
+}
+
+// randomCode returns an 8-digit cNF that differs from the invoice number, as SEFAZ requires
+func randomCode(number int) (string, error) {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+		if err != nil {
+			return "", err
+		}
+		code := fmt.Sprintf("%08d", n.Int64())
+		if code != fmt.Sprintf("%08d", number) {
+			return code, nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package nfe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		name  string
+		key43 string
+		want  int
+	}{
+		{"example from the NF-e manual", "5206043300991100250655012000000780026730161", 5},
+		{"rest 0 gives 0", "3526101122233300018155001000000123100000008", 0},
+		{"rest 1 gives 0", "3526101122233300018155001000000123100000003", 0},
+		{"rest 2 gives 9", "3526101122233300018155001000000123100000009", 9},
+		{"rest 10 gives 1", "3526101122233300018155001000000123100000002", 1},
+		{"rest 6 gives 5", "3526101122233300018155001000000123100000000", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckDigit(tt.key43); got != tt.want {
+				t.Errorf("CheckDigit(%s) = %d, want %d", tt.key43, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidAccessKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"52060433009911002506550120000007800267301615", true},
+		{"52060433009911002506550120000007800267301614", false}, // wrong check digit
+		{"5206043300991100250655012000000780026730161", false},  // 43 digits
+		{"520604330099110025065501200000078002673016155", false},
+		{"5206043300991100250655012000000780026730161X", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidAccessKey(tt.key); got != tt.want {
+			t.Errorf("ValidAccessKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBuildAccessKey(t *testing.T) {
+	valid := AccessKeyParts{
+		State:        "sp",
+		IssuedAt:     time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		CNPJ:         "11222333000181",
+		Series:       1,
+		Number:       42,
+		EmissionType: 1,
+		RandomCode:   "12345678",
+	}
+	key, err := BuildAccessKey(valid)
+	if err != nil {
+		t.Fatalf("BuildAccessKey: %v", err)
+	}
+	// cUF, AAMM, CNPJ, model, series, number, tpEmis, cNF and cDV
+	if want := "35" + "2610" + "11222333000181" + "55" + "001" + "000000042" + "1" + "12345678" + "3"; key != want {
+		t.Errorf("BuildAccessKey = %s, want %s", key, want)
+	}
+	if !ValidAccessKey(key) {
+		t.Errorf("BuildAccessKey returned %s, which does not validate", key)
+	}
+
+	invalid := []struct {
+		name   string
+		modify func(p *AccessKeyParts)
+	}{
+		{"unknown state", func(p *AccessKeyParts) { p.State = "XX" }},
+		{"short CNPJ", func(p *AccessKeyParts) { p.CNPJ = "1122233300018" }},
+		{"series above 999", func(p *AccessKeyParts) { p.Series = 1000 }},
+		{"number zero", func(p *AccessKeyParts) { p.Number = 0 }},
+		{"number above nine digits", func(p *AccessKeyParts) { p.Number = 1000000000 }},
+		{"short random code", func(p *AccessKeyParts) { p.RandomCode = "1234567" }},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := valid
+			tt.modify(&parts)
+			if key, err := BuildAccessKey(parts); err == nil {
+				t.Errorf("BuildAccessKey = %s, want an error", key)
+			}
+		})
+	}
+}
+
+func TestFormatAccessKey(t *testing.T) {
+	got := FormatAccessKey("52060433009911002506550120000007800267301615")
+	if want := "5206 0433 0099 1100 2506 5501 2000 0007 8002 6730 1615"; got != want {
+		t.Errorf("FormatAccessKey = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,289 @@
+package nfe
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode/code128"
+	"github.com/go-pdf/fpdf"
+)
+
+// DANFE layout constants (A4 portrait, millimetres)
+const (
+	danfeMargin = 8.0
+	danfeWidth  = 210.0 - 2*danfeMargin
+	labelSize   = 5.5
+	valueSize   = 8.0
+)
+
+// RenderDANFE writes a portrait DANFE (Documento Auxiliar da NF-e) as PDF.
+// protocol is the authorization protocol, empty while the invoice is not authorized.
+func RenderDANFE(nfe *NFe, protocol string, w io.Writer) error {
+	inf := nfe.InfNFe
+	key := strings.TrimPrefix(inf.ID, "NFe")
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(danfeMargin, danfeMargin, danfeMargin)
+	pdf.SetAutoPageBreak(true, danfeMargin)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // Core fonts are cp1252
+
+	d := &danfe{pdf: pdf, tr: tr}
+	y := danfeMargin
+
+	// Header: issuer, DANFE title, barcode and access key
+	d.box(danfeMargin, y, 80, 32, "IDENTIFICAÇÃO DO EMITENTE", "")
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetXY(danfeMargin+2, y+5)
+	pdf.MultiCell(76, 4, tr(inf.Emit.XNome), "", "L", false)
+	pdf.SetFont("Helvetica", "", 7)
+	e := inf.Emit.EnderEmit
+	pdf.SetX(danfeMargin + 2)
+	pdf.MultiCell(76, 3.5, tr(fmt.Sprintf("%s, %s %s\n%s - %s/%s\nCEP %s", e.XLgr, e.Nro, e.XCpl, e.XBairro, e.XMun, e.UF, e.CEP)), "", "L", false)
+
+	x := danfeMargin + 80
+	d.box(x, y, 34, 32, "", "")
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.SetXY(x, y+2)
+	pdf.CellFormat(34, 6, "DANFE", "", 2, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 6)
+	pdf.MultiCell(34, 2.8, tr("Documento Auxiliar da Nota Fiscal Eletrônica\n\n1 - SAÍDA"), "", "C", false)
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetX(x)
+	pdf.CellFormat(34, 4, tr(fmt.Sprintf("Nº %09d", inf.Ide.NNF)), "", 2, "C", false, 0, "")
+	pdf.CellFormat(34, 4, tr(fmt.Sprintf("SÉRIE %03d", inf.Ide.Serie)), "", 0, "C", false, 0, "")
+
+	x += 34
+	barcodeWidth := danfeWidth - 114
+	d.box(x, y, barcodeWidth, 14, "", "")
+	if err := d.barcode(key, x+3, y+2, barcodeWidth-6, 10); err != nil {
+		return err
+	}
+	d.box(x, y+14, barcodeWidth, 9, "CHAVE DE ACESSO", FormatAccessKey(key))
+	status := "Consulta de autenticidade no portal nacional da NF-e www.nfe.fazenda.gov.br/portal"
+	if protocol == "" {
+		status = "NF-e ainda não autorizada - sem valor fiscal"
+	}
+	d.text(x+1, y+24, barcodeWidth-2, 6, status)
+	y += 32
+
+	// Nature of operation and protocol
+	d.box(danfeMargin, y, 114, 9, "NATUREZA DA OPERAÇÃO", inf.Ide.NatOp)
+	d.box(danfeMargin+114, y, danfeWidth-114, 9, "PROTOCOLO DE AUTORIZAÇÃO DE USO", protocol)
+	y += 9
+	d.box(danfeMargin, y, 64, 9, "INSCRIÇÃO ESTADUAL", inf.Emit.IE)
+	d.box(danfeMargin+64, y, 64, 9, "CNPJ", formatCNPJ(inf.Emit.CNPJ))
+	d.box(danfeMargin+128, y, danfeWidth-128, 9, "DATA DE EMISSÃO", formatDate(inf.Ide.DhEmi))
+	y += 12
+
+	// Recipient
+	d.section(y, "DESTINATÁRIO / REMETENTE")
+	y += 4
+	dest := inf.Dest
+	document := formatCPF(dest.CPF)
+	if dest.CNPJ != "" {
+		document = formatCNPJ(dest.CNPJ)
+	}
+	d.box(danfeMargin, y, 140, 9, "NOME / RAZÃO SOCIAL", dest.XNome)
+	d.box(danfeMargin+140, y, danfeWidth-140, 9, "CNPJ / CPF", document)
+	y += 9
+	a := dest.EnderDest
+	d.box(danfeMargin, y, 110, 9, "ENDEREÇO", strings.TrimSpace(fmt.Sprintf("%s, %s %s", a.XLgr, a.Nro, a.XCpl)))
+	d.box(danfeMargin+110, y, 50, 9, "BAIRRO / DISTRITO", a.XBairro)
+	d.box(danfeMargin+160, y, danfeWidth-160, 9, "CEP", a.CEP)
+	y += 9
+	d.box(danfeMargin, y, 110, 9, "MUNICÍPIO", a.XMun)
+	d.box(danfeMargin+110, y, 20, 9, "UF", a.UF)
+	d.box(danfeMargin+130, y, danfeWidth-130, 9, "FONE", a.Fone)
+	y += 12
+
+	// Totals
+	d.section(y, "CÁLCULO DO IMPOSTO")
+	y += 4
+	t := inf.Total.ICMSTot
+	totals := [][2]string{
+		{"BASE DE CÁLC. DO ICMS", t.VBC},
+		{"VALOR DO ICMS", t.VICMS},
+		{"V. ICMS UF DEST.", t.VICMSUFDest},
+		{"V. FCP UF DEST.", t.VFCPUFDest},
+		{"V. TOTAL PRODUTOS", t.VProd},
+		{"VALOR DO FRETE", t.VFrete},
+		{"DESCONTO", t.VDesc},
+		{"V. TOTAL DA NOTA", t.VNF},
+	}
+	cell := danfeWidth / float64(len(totals))
+	for i, total := range totals {
+		d.box(danfeMargin+float64(i)*cell, y, cell, 9, total[0], formatDecimal(total[1]))
+	}
+	y += 12
+
+	// Items
+	d.section(y, "DADOS DOS PRODUTOS / SERVIÇOS")
+	y += 4
+	columns := []struct {
+		title string
+		width float64
+		align string
+	}{
+		{"CÓDIGO", 22, "L"}, {"DESCRIÇÃO", 62, "L"}, {"NCM", 15, "C"}, {"CFOP", 10, "C"},
+		{"UN", 8, "C"}, {"QTD", 12, "R"}, {"V. UNIT.", 18, "R"}, {"V. TOTAL", 18, "R"},
+		{"V. ICMS", 14, "R"}, {"ALÍQ.", danfeWidth - 179, "R"},
+	}
+	pdf.SetFont("Helvetica", "B", labelSize)
+	pdf.SetXY(danfeMargin, y)
+	for _, col := range columns {
+		pdf.CellFormat(col.width, 5, tr(col.title), "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 6.5)
+	for _, det := range inf.Det {
+		icmsValue, icmsRate := "0,00", "0,00"
+		if icms := det.Imposto.ICMS.ICMS00; icms != nil {
+			icmsValue, icmsRate = formatDecimal(icms.VICMS), formatDecimal(icms.PICMS)
+		}
+		values := []string{
+			det.Prod.CProd, det.Prod.XProd, det.Prod.NCM, det.Prod.CFOP, det.Prod.UCom,
+			formatDecimal(det.Prod.QCom), formatDecimal(det.Prod.VUnCom), formatDecimal(det.Prod.VProd),
+			icmsValue, icmsRate,
+		}
+		for i, col := range columns {
+			pdf.CellFormat(col.width, 5, tr(fit(pdf, tr, values[i], col.width-1)), "1", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	y = pdf.GetY() + 3
+
+	// Additional information
+	d.section(y, "DADOS ADICIONAIS")
+	y += 4
+	info := ""
+	if inf.InfAdic != nil {
+		info = inf.InfAdic.InfCpl
+	}
+	if inf.Ide.TpAmb == 2 {
+		info = strings.TrimSpace(info + "\n" + homologationRecipient)
+	}
+	d.box(danfeMargin, y, danfeWidth, 20, "INFORMAÇÕES COMPLEMENTARES", "")
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.SetXY(danfeMargin+1, y+4)
+	pdf.MultiCell(danfeWidth-2, 3.5, tr(info), "", "L", false)
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// danfe bundles the PDF and its cp1252 translator for the drawing helpers
+type danfe struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+// box draws a bordered field with a small caption and a value
+func (d *danfe) box(x, y, w, h float64, label, value string) {
+	d.pdf.Rect(x, y, w, h, "D")
+	if label != "" {
+		d.pdf.SetFont("Helvetica", "", labelSize)
+		d.pdf.SetXY(x+0.8, y+0.5)
+		d.pdf.CellFormat(w-1.6, 2.5, d.tr(label), "", 0, "L", false, 0, "")
+	}
+	if value != "" {
+		d.pdf.SetFont("Helvetica", "B", valueSize)
+		d.pdf.SetXY(x+0.8, y+3.5)
+		d.pdf.CellFormat(w-1.6, h-4, d.tr(fit(d.pdf, d.tr, value, w-1.6)), "", 0, "L", false, 0, "")
+	}
+}
+
+// section prints a block title
+func (d *danfe) section(y float64, title string) {
+	d.pdf.SetFont("Helvetica", "B", 6.5)
+	d.pdf.SetXY(danfeMargin, y)
+	d.pdf.CellFormat(danfeWidth, 4, d.tr(title), "", 0, "L", false, 0, "")
+}
+
+// text prints small wrapped text
+func (d *danfe) text(x, y, w, h float64, s string) {
+	d.pdf.SetFont("Helvetica", "", 6)
+	d.pdf.SetXY(x, y)
+	d.pdf.MultiCell(w, h/2, d.tr(s), "", "C", false)
+}
+
+// barcode draws the access key as a CODE-128C barcode
+func (d *danfe) barcode(key string, x, y, w, h float64) error {
+	code, err := code128.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	bounds := code.Bounds()
+	modules := bounds.Dx()
+	moduleWidth := w / float64(modules)
+	d.pdf.SetFillColor(0, 0, 0)
+	for i := 0; i < modules; i++ {
+		r, _, _, _ := code.At(bounds.Min.X+i, bounds.Min.Y).RGBA()
+		if r == 0 {
+			d.pdf.Rect(x+float64(i)*moduleWidth, y, moduleWidth, h, "F")
+		}
+	}
+	return nil
+}
+
+// fit truncates s so it fits in width at the current font
+func fit(pdf *fpdf.Fpdf, tr func(string) string, s string, width float64) string {
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(tr(string(runes))) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
+
+// formatDecimal renders an XML decimal ("1234.50") in Brazilian notation ("1.234,50")
+func formatDecimal(v string) string {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+
+	decimals := 2
+	if i := strings.IndexByte(v, '.'); i >= 0 && len(v)-i-1 == 4 {
+		decimals = 4
+	}
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 && r != '-' {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "," + fracPart
+}
+
+func formatCNPJ(cnpj string) string {
+	if len(cnpj) != 14 {
+		return cnpj
+	}
+	return cnpj[:2] + "." + cnpj[2:5] + "." + cnpj[5:8] + "/" + cnpj[8:12] + "-" + cnpj[12:]
+}
+
+func formatCPF(cpf string) string {
+	if len(cpf) != 11 {
+		return cpf
+	}
+	return cpf[:3] + "." + cpf[3:6] + "." + cpf[6:9] + "-" + cpf[9:]
+}
+
+func formatDate(dh string) string {
+	t, err := time.Parse("2006-01-02T15:04:05-07:00", dh)
+	if err != nil {
+		return dh
+	}
+	return t.Format("02/01/2006")
+}
@@ -0,0 +1,438 @@
+package nfe
+
+import (
+	"ecommerce/internal/domain"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// brasiliaTime is the fixed UTC-3 offset used for dhEmi (Brazil dropped DST in 2019)
+var brasiliaTime = time.FixedZone("BRT", -3*60*60)
+
+// homologationRecipient is the recipient name SEFAZ requires in the test environment
+const homologationRecipient = "NF-E EMITIDA EM AMBIENTE DE HOMOLOGACAO - SEM VALOR FISCAL"
+
+// paymentTypes maps our payment methods to tPag codes
+var paymentTypes = map[string]string{
+	"cash":        "01",
+	"credit_card": "03",
+	"debit_card":  "04",
+	"boleto":      "15",
+	"pix":         "17",
+}
+
+// Input is everything needed to build one NF-e
+type Input struct {
+	Profile  *domain.FiscalProfile
+	Order    *domain.Order
+	Items    []domain.OrderItem // The issuing seller's lines, with Product loaded
+	Number   int
+	IssuedAt time.Time
+	Freight  domain.Money // Share of the order's shipping fee covered by this invoice
+	Discount domain.Money // Share of the order's discount covered by this invoice
+}
+
+// Document is a generated, unsigned NF-e
+type Document struct {
+	AccessKey string
+	NFe       *NFe
+	XML       []byte
+	Total     domain.Money
+}
+
+// Generate builds the NF-e 4.00 XML for a seller's part of an order
+func Generate(in Input) (*Document, error) {
+	if err := Validate(in); err != nil {
+		return nil, err
+	}
+
+	profile := in.Profile
+	address := in.Order.ShippingAddress
+	issuedAt := in.IssuedAt.In(brasiliaTime)
+
+	code, err := randomCode(in.Number)
+	if err != nil {
+		return nil, err
+	}
+	key, err := BuildAccessKey(AccessKeyParts{
+		State:        profile.State,
+		IssuedAt:     issuedAt,
+		CNPJ:         profile.CNPJ,
+		Series:       profile.Series,
+		Number:       in.Number,
+		EmissionType: 1,
+		RandomCode:   code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	interstate := profile.State != address.State
+	idDest, cfop := 1, "5102"
+	if interstate {
+		idDest, cfop = 2, "6108"
+	}
+
+	lineTotals := make([]domain.Money, len(in.Items))
+	weights := make([]int64, len(in.Items))
+	for i, item := range in.Items {
+		lineTotals[i] = item.PriceAtTime.Mul(int64(item.Quantity))
+		weights[i] = lineTotals[i].Amount
+	}
+	freights, err := in.Freight.Allocate(weights)
+	if err != nil {
+		return nil, err
+	}
+	discounts, err := in.Discount.Allocate(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals icmsTotals
+	det := make([]Det, len(in.Items))
+	for i, item := range in.Items {
+		det[i] = Det{
+			NItem: i + 1,
+			Prod: Prod{
+				CProd:    productCode(item.Product),
+				CEAN:     gtin(item.Product.Barcode),
+				XProd:    truncate(item.Product.Name, 120),
+				NCM:      domain.OnlyDigits(item.Product.NCM),
+				CFOP:     cfop,
+				UCom:     "UN",
+				QCom:     formatQuantity(item.Quantity),
+				VUnCom:   formatMoney(item.PriceAtTime),
+				VProd:    formatMoney(lineTotals[i]),
+				CEANTrib: gtin(item.Product.Barcode),
+				UTrib:    "UN",
+				QTrib:    formatQuantity(item.Quantity),
+				VUnTrib:  formatMoney(item.PriceAtTime),
+				VFrete:   formatOptionalMoney(freights[i]),
+				VDesc:    formatOptionalMoney(discounts[i]),
+				IndTot:   1,
+			},
+			Imposto: buildImposto(profile, item, interstate, &totals),
+		}
+		totals.products = totals.products.Add(lineTotals[i])
+	}
+
+	total := totals.products.Add(in.Freight).Sub(in.Discount)
+	nfe := &NFe{
+		Xmlns: Namespace,
+		InfNFe: InfNFe{
+			Versao: Version,
+			ID:     "NFe" + key,
+			Ide: Ide{
+				CUF:         key[:2],
+				CNF:         code,
+				NatOp:       "Venda de mercadoria",
+				Mod:         Model,
+				Serie:       profile.Series,
+				NNF:         in.Number,
+				DhEmi:       issuedAt.Format("2006-01-02T15:04:05-07:00"),
+				TpNF:        1,
+				IDDest:      idDest,
+				CMunFG:      profile.CityCode,
+				TpImp:       1,
+				TpEmis:      1,
+				CDV:         int(key[43] - '0'),
+				TpAmb:       profile.Environment,
+				FinNFe:      1,
+				IndFinal:    1,
+				IndPres:     2,
+				IndIntermed: 0,
+				ProcEmi:     0,
+				VerProc:     "ecommerce-api 1.0",
+			},
+			Emit: Emit{
+				CNPJ:  profile.CNPJ,
+				XNome: truncate(profile.LegalName, 60),
+				XFant: truncate(profile.TradeName, 60),
+				EnderEmit: Address{
+					XLgr:    truncate(profile.Street, 60),
+					Nro:     truncate(profile.Number, 60),
+					XCpl:    truncate(profile.Complement, 60),
+					XBairro: truncate(profile.Neighborhood, 60),
+					CMun:    profile.CityCode,
+					XMun:    truncate(profile.City, 60),
+					UF:      profile.State,
+					CEP:     domain.OnlyDigits(profile.PostalCode),
+					CPais:   "1058",
+					XPais:   "BRASIL",
+					Fone:    phone(profile.Phone),
+				},
+				IE:  domain.OnlyDigits(profile.StateRegistration),
+				CRT: profile.TaxRegime,
+			},
+			Dest:   buildDest(profile, in.Order),
+			Det:    det,
+			Total:  Total{ICMSTot: totals.toXML(in.Freight, in.Discount, total)},
+			Transp: Transp{ModFrete: 0},
+			Pag:    Pag{DetPag: []DetPag{buildPayment(in.Order, total)}},
+			InfAdic: &InfAdic{
+				InfCpl: "Pedido " + in.Order.OrderNumber,
+			},
+		},
+	}
+
+	body, err := xml.Marshal(nfe)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		AccessKey: key,
+		NFe:       nfe,
+		XML:       append([]byte(xml.Header), body...),
+		Total:     total,
+	}, nil
+}
+
+// Parse reads an NFe back from XML, accepting both a bare NFe and an nfeProc envelope
+func Parse(data []byte) (*NFe, error) {
+	var proc struct {
+		NFe *NFe `xml:"NFe"`
+	}
+	if err := xml.Unmarshal(data, &proc); err == nil && proc.NFe != nil {
+		return proc.NFe, nil
+	}
+
+	var nfe NFe
+	if err := xml.Unmarshal(data, &nfe); err != nil {
+		return nil, err
+	}
+	return &nfe, nil
+}
+
+// Validate checks the data SEFAZ would reject, so callers can fail before spending an invoice number
+func Validate(in Input) error {
+	profile := in.Profile
+	switch {
+	case profile == nil:
+		return errors.New("seller has no fiscal profile")
+	case !domain.IsValidCNPJ(profile.CNPJ):
+		return errors.New("fiscal profile has an invalid CNPJ")
+	case len(profile.CityCode) != 7:
+		return errors.New("fiscal profile is missing the IBGE city code")
+	case StateCodes[profile.State] == "":
+		return errors.New("fiscal profile has an invalid state")
+	}
+
+	order := in.Order
+	if order.CustomerDocument == nil || domain.NormalizeTaxDocument(*order.CustomerDocument) == "" {
+		return errors.New("order has no valid customer CPF/CNPJ")
+	}
+	if order.ShippingAddress == nil {
+		return errors.New("order has no shipping address")
+	}
+	if !order.ShippingAddress.IsDomestic() {
+		return errors.New("export NF-e (foreign recipients) are not supported, issue it manually")
+	}
+	if len(order.ShippingAddress.CityCode) != 7 {
+		return errors.New("shipping address is missing the IBGE city code")
+	}
+	if len(in.Items) == 0 {
+		return errors.New("invoice must have at least one item")
+	}
+	for _, item := range in.Items {
+		if item.Product == nil {
+			return errors.New("order item is missing its product")
+		}
+		if len(domain.OnlyDigits(item.Product.NCM)) != 8 {
+			return fmt.Errorf("product %q has no valid 8-digit NCM", item.Product.Name)
+		}
+		// The ICMS groups copy the order's tax breakdown, which must have been computed from
+		// the state the seller ships from for its rates and DIFAL to match the CFOP
+		origin := item.Tax.OriginState
+		if profile.TaxRegime == domain.TaxRegimeNormal && origin != "" && !strings.EqualFold(origin, profile.State) {
+			return fmt.Errorf("order taxes were computed from %s but the fiscal profile is in %s; the invoice must be issued manually", origin, profile.State)
+		}
+	}
+	return nil
+}
+
+// buildDest fills the recipient from the order
+func buildDest(profile *domain.FiscalProfile, order *domain.Order) Dest {
+	address := order.ShippingAddress
+	name := address.Recipient
+	if profile.Environment == domain.NFeEnvironmentHomologation {
+		name = homologationRecipient
+	}
+
+	dest := Dest{
+		XNome: truncate(name, 60),
+		EnderDest: Address{
+			XLgr:    truncate(address.Street, 60),
+			Nro:     truncate(address.Number, 60),
+			XCpl:    truncate(address.Complement, 60),
+			XBairro: truncate(address.Neighborhood, 60),
+			CMun:    address.CityCode,
+			XMun:    truncate(address.City, 60),
+			UF:      address.State,
+			CEP:     domain.OnlyDigits(address.PostalCode),
+			CPais:   "1058",
+			XPais:   "BRASIL",
+			Fone:    phone(address.Phone),
+		},
+		IndIEDest: 9,
+	}
+
+	document := domain.OnlyDigits(*order.CustomerDocument)
+	if len(document) == 14 {
+		dest.CNPJ = document
+	} else {
+		dest.CPF = document
+	}
+	return dest
+}
+
+// icmsTotals accumulates the ICMSTot values while lines are built
+type icmsTotals struct {
+	products, base, icms, fcp, fcpDest, icmsDest domain.Money
+}
+
+func (t icmsTotals) toXML(freight, discount, total domain.Money) ICMSTot {
+	return ICMSTot{
+		VBC:          formatMoney(t.base),
+		VICMS:        formatMoney(t.icms),
+		VICMSDeson:   "0.00",
+		VFCPUFDest:   formatMoney(t.fcpDest),
+		VICMSUFDest:  formatMoney(t.icmsDest),
+		VICMSUFRemet: "0.00",
+		VFCP:         formatMoney(t.fcp),
+		VBCST:        "0.00",
+		VST:          "0.00",
+		VFCPST:       "0.00",
+		VFCPSTRet:    "0.00",
+		VProd:        formatMoney(t.products),
+		VFrete:       formatMoney(freight),
+		VSeg:         "0.00",
+		VDesc:        formatMoney(discount),
+		VII:          "0.00",
+		VIPI:         "0.00",
+		VIPIDevol:    "0.00",
+		VPIS:         "0.00",
+		VCOFINS:      "0.00",
+		VOutro:       "0.00",
+		VNF:          formatMoney(total),
+	}
+}
+
+// buildImposto maps the stored TaxBreakdown of a line onto the ICMS groups.
+// PIS/COFINS are emitted as CST 99 with zero values; sellers outside the cumulative
+// regime must have their accountant adjust this before going to production.
+func buildImposto(profile *domain.FiscalProfile, item domain.OrderItem, interstate bool, totals *icmsTotals) Imposto {
+	tax := item.Tax
+	imposto := Imposto{
+		PIS:    PIS{PISOutr: PISOutr{CST: "99", VBC: "0.00", PPIS: "0.00", VPIS: "0.00"}},
+		COFINS: COFINS{COFINSOutr: COFINSOutr{CST: "99", VBC: "0.00", PCOFINS: "0.00", VCOFINS: "0.00"}},
+	}
+
+	if profile.TaxRegime != domain.TaxRegimeNormal {
+		imposto.ICMS.ICMSSN102 = &ICMSSN102{Orig: item.Product.TaxOrigin, CSOSN: "102"}
+		return imposto
+	}
+
+	icms00 := &ICMS00{
+		Orig:  item.Product.TaxOrigin,
+		CST:   "00",
+		ModBC: 3,
+		VBC:   formatMoney(tax.Base),
+		PICMS: formatRate(tax.ICMSRate),
+		VICMS: formatMoney(tax.ICMSAmount),
+	}
+	totals.base = totals.base.Add(tax.Base)
+	totals.icms = totals.icms.Add(tax.ICMSAmount)
+
+	if interstate {
+		imposto.ICMSUFDest = &ICMSUFDest{
+			VBCUFDest:      formatMoney(tax.Base),
+			VBCFCPUFDest:   formatMoney(tax.Base),
+			PFCPUFDest:     formatRate(tax.FCPRate),
+			PICMSUFDest:    formatRate(tax.ICMSRate + tax.DIFALRate),
+			PICMSInter:     formatRate(tax.ICMSRate),
+			PICMSInterPart: "100.00",
+			VFCPUFDest:     formatMoney(tax.FCPAmount),
+			VICMSUFDest:    formatMoney(tax.DIFALAmount),
+			VICMSUFRemet:   "0.00",
+		}
+		totals.fcpDest = totals.fcpDest.Add(tax.FCPAmount)
+		totals.icmsDest = totals.icmsDest.Add(tax.DIFALAmount)
+	} else if !tax.FCPAmount.IsZero() {
+		icms00.PFCP = formatRate(tax.FCPRate)
+		icms00.VFCP = formatMoney(tax.FCPAmount)
+		totals.fcp = totals.fcp.Add(tax.FCPAmount)
+	}
+
+	imposto.ICMS.ICMS00 = icms00
+	return imposto
+}
+
+// buildPayment maps the order payment method onto detPag
+func buildPayment(order *domain.Order, total domain.Money) DetPag {
+	method := ""
+	if order.PaymentMethod != nil {
+		method = strings.ToLower(*order.PaymentMethod)
+	}
+	if code, ok := paymentTypes[method]; ok {
+		return DetPag{TPag: code, VPag: formatMoney(total)}
+	}
+	return DetPag{TPag: "99", XPag: truncate(method, 60), VPag: formatMoney(total)}
+}
+
+func productCode(p *domain.Product) string {
+	if p.SKU != "" {
+		return truncate(p.SKU, 60)
+	}
+	return p.ID
+}
+
+// gtin returns the barcode when it is a GTIN, or the "SEM GTIN" placeholder
+func gtin(barcode string) string {
+	digits := domain.OnlyDigits(barcode)
+	switch len(digits) {
+	case 8, 12, 13, 14:
+		return digits
+	}
+	return "SEM GTIN"
+}
+
+func phone(s string) string {
+	digits := domain.OnlyDigits(s)
+	if len(digits) < 6 || len(digits) > 14 {
+		return ""
+	}
+	return digits
+}
+
+func truncate(s string, max int) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
+
+func formatMoney(m domain.Money) string {
+	return m.Decimal()
+}
+
+func formatOptionalMoney(m domain.Money) string {
+	if m.IsZero() {
+		return ""
+	}
+	return formatMoney(m)
+}
+
+func formatRate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func formatQuantity(q int) string {
+	return strconv.Itoa(q) + ".0000"
+}
@@ -0,0 +1,243 @@
+package nfe
+
+import "encoding/xml"
+
+// Namespace is the NF-e XML namespace
+const Namespace = "http://www.portalfiscal.inf.br/nfe"
+
+// Version is the NF-e layout version generated by this package
+const Version = "4.00"
+
+// The structs below follow the element order of the NF-e 4.00 schema (leiauteNFe_v4.00.xsd).
+// Only the groups needed for B2C sales of goods are modelled.
+
+// NFe is the root element of an electronic invoice
+type NFe struct {
+	XMLName xml.Name `xml:"NFe"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	InfNFe  InfNFe   `xml:"infNFe"`
+}
+
+// InfNFe holds the invoice data that is signed
+type InfNFe struct {
+	Versao  string   `xml:"versao,attr"`
+	ID      string   `xml:"Id,attr"`
+	Ide     Ide      `xml:"ide"`
+	Emit    Emit     `xml:"emit"`
+	Dest    Dest     `xml:"dest"`
+	Det     []Det    `xml:"det"`
+	Total   Total    `xml:"total"`
+	Transp  Transp   `xml:"transp"`
+	Pag     Pag      `xml:"pag"`
+	InfAdic *InfAdic `xml:"infAdic,omitempty"`
+}
+
+// Ide identifies the invoice
+type Ide struct {
+	CUF         string `xml:"cUF"`
+	CNF         string `xml:"cNF"`
+	NatOp       string `xml:"natOp"`
+	Mod         string `xml:"mod"`
+	Serie       int    `xml:"serie"`
+	NNF         int    `xml:"nNF"`
+	DhEmi       string `xml:"dhEmi"`
+	TpNF        int    `xml:"tpNF"`   // 1 = saída
+	IDDest      int    `xml:"idDest"` // 1 = interna, 2 = interestadual
+	CMunFG      string `xml:"cMunFG"`
+	TpImp       int    `xml:"tpImp"`  // 1 = DANFE retrato
+	TpEmis      int    `xml:"tpEmis"` // 1 = normal
+	CDV         int    `xml:"cDV"`
+	TpAmb       int    `xml:"tpAmb"`
+	FinNFe      int    `xml:"finNFe"`      // 1 = normal
+	IndFinal    int    `xml:"indFinal"`    // 1 = consumidor final
+	IndPres     int    `xml:"indPres"`     // 2 = operação pela internet
+	IndIntermed int    `xml:"indIntermed"` // 0 = sem intermediador (own site)
+	ProcEmi     int    `xml:"procEmi"`     // 0 = aplicativo do contribuinte
+	VerProc     string `xml:"verProc"`
+}
+
+// Address is the enderEmit/enderDest group
+type Address struct {
+	XLgr    string `xml:"xLgr"`
+	Nro     string `xml:"nro"`
+	XCpl    string `xml:"xCpl,omitempty"`
+	XBairro string `xml:"xBairro"`
+	CMun    string `xml:"cMun"`
+	XMun    string `xml:"xMun"`
+	UF      string `xml:"UF"`
+	CEP     string `xml:"CEP"`
+	CPais   string `xml:"cPais"`
+	XPais   string `xml:"xPais"`
+	Fone    string `xml:"fone,omitempty"`
+}
+
+// Emit is the issuer (seller)
+type Emit struct {
+	CNPJ      string  `xml:"CNPJ"`
+	XNome     string  `xml:"xNome"`
+	XFant     string  `xml:"xFant,omitempty"`
+	EnderEmit Address `xml:"enderEmit"`
+	IE        string  `xml:"IE"`
+	CRT       int     `xml:"CRT"`
+}
+
+// Dest is the recipient (customer)
+type Dest struct {
+	CNPJ      string  `xml:"CNPJ,omitempty"`
+	CPF       string  `xml:"CPF,omitempty"`
+	XNome     string  `xml:"xNome"`
+	EnderDest Address `xml:"enderDest"`
+	IndIEDest int     `xml:"indIEDest"` // 9 = não contribuinte
+	Email     string  `xml:"email,omitempty"`
+}
+
+// Det is one invoice line
+type Det struct {
+	NItem   int     `xml:"nItem,attr"`
+	Prod    Prod    `xml:"prod"`
+	Imposto Imposto `xml:"imposto"`
+}
+
+// Prod describes the goods of a line
+type Prod struct {
+	CProd    string `xml:"cProd"`
+	CEAN     string `xml:"cEAN"`
+	XProd    string `xml:"xProd"`
+	NCM      string `xml:"NCM"`
+	CFOP     string `xml:"CFOP"`
+	UCom     string `xml:"uCom"`
+	QCom     string `xml:"qCom"`
+	VUnCom   string `xml:"vUnCom"`
+	VProd    string `xml:"vProd"`
+	CEANTrib string `xml:"cEANTrib"`
+	UTrib    string `xml:"uTrib"`
+	QTrib    string `xml:"qTrib"`
+	VUnTrib  string `xml:"vUnTrib"`
+	VFrete   string `xml:"vFrete,omitempty"`
+	VDesc    string `xml:"vDesc,omitempty"`
+	IndTot   int    `xml:"indTot"`
+}
+
+// Imposto holds the taxes of a line
+type Imposto struct {
+	ICMS       ICMS        `xml:"ICMS"`
+	PIS        PIS         `xml:"PIS"`
+	COFINS     COFINS      `xml:"COFINS"`
+	ICMSUFDest *ICMSUFDest `xml:"ICMSUFDest,omitempty"`
+}
+
+// ICMS wraps exactly one ICMS situation group
+type ICMS struct {
+	ICMS00    *ICMS00    `xml:"ICMS00,omitempty"`
+	ICMSSN102 *ICMSSN102 `xml:"ICMSSN102,omitempty"`
+}
+
+// ICMS00 is "tributada integralmente" (regime normal)
+type ICMS00 struct {
+	Orig  int    `xml:"orig"`
+	CST   string `xml:"CST"`
+	ModBC int    `xml:"modBC"` // 3 = valor da operação
+	VBC   string `xml:"vBC"`
+	PICMS string `xml:"pICMS"`
+	VICMS string `xml:"vICMS"`
+	PFCP  string `xml:"pFCP,omitempty"`
+	VFCP  string `xml:"vFCP,omitempty"`
+}
+
+// ICMSSN102 is "Simples Nacional sem permissão de crédito"
+type ICMSSN102 struct {
+	Orig  int    `xml:"orig"`
+	CSOSN string `xml:"CSOSN"`
+}
+
+// PIS wraps the PIS group
+type PIS struct {
+	PISOutr PISOutr `xml:"PISOutr"`
+}
+
+// PISOutr is "outras operações"
+type PISOutr struct {
+	CST  string `xml:"CST"`
+	VBC  string `xml:"vBC"`
+	PPIS string `xml:"pPIS"`
+	VPIS string `xml:"vPIS"`
+}
+
+// COFINS wraps the COFINS group
+type COFINS struct {
+	COFINSOutr COFINSOutr `xml:"COFINSOutr"`
+}
+
+// COFINSOutr is "outras operações"
+type COFINSOutr struct {
+	CST     string `xml:"CST"`
+	VBC     string `xml:"vBC"`
+	PCOFINS string `xml:"pCOFINS"`
+	VCOFINS string `xml:"vCOFINS"`
+}
+
+// ICMSUFDest is the DIFAL group for interstate sales to final consumers
+type ICMSUFDest struct {
+	VBCUFDest      string `xml:"vBCUFDest"`
+	VBCFCPUFDest   string `xml:"vBCFCPUFDest"`
+	PFCPUFDest     string `xml:"pFCPUFDest"`
+	PICMSUFDest    string `xml:"pICMSUFDest"`
+	PICMSInter     string `xml:"pICMSInter"`
+	PICMSInterPart string `xml:"pICMSInterPart"`
+	VFCPUFDest     string `xml:"vFCPUFDest"`
+	VICMSUFDest    string `xml:"vICMSUFDest"`
+	VICMSUFRemet   string `xml:"vICMSUFRemet"`
+}
+
+// Total holds the invoice totals
+type Total struct {
+	ICMSTot ICMSTot `xml:"ICMSTot"`
+}
+
+// ICMSTot is the ICMS totals group
+type ICMSTot struct {
+	VBC          string `xml:"vBC"`
+	VICMS        string `xml:"vICMS"`
+	VICMSDeson   string `xml:"vICMSDeson"`
+	VFCPUFDest   string `xml:"vFCPUFDest"`
+	VICMSUFDest  string `xml:"vICMSUFDest"`
+	VICMSUFRemet string `xml:"vICMSUFRemet"`
+	VFCP         string `xml:"vFCP"`
+	VBCST        string `xml:"vBCST"`
+	VST          string `xml:"vST"`
+	VFCPST       string `xml:"vFCPST"`
+	VFCPSTRet    string `xml:"vFCPSTRet"`
+	VProd        string `xml:"vProd"`
+	VFrete       string `xml:"vFrete"`
+	VSeg         string `xml:"vSeg"`
+	VDesc        string `xml:"vDesc"`
+	VII          string `xml:"vII"`
+	VIPI         string `xml:"vIPI"`
+	VIPIDevol    string `xml:"vIPIDevol"`
+	VPIS         string `xml:"vPIS"`
+	VCOFINS      string `xml:"vCOFINS"`
+	VOutro       string `xml:"vOutro"`
+	VNF          string `xml:"vNF"`
+}
+
+// Transp holds freight information
+type Transp struct {
+	ModFrete int `xml:"modFrete"` // 0 = por conta do remetente (CIF)
+}
+
+// Pag holds payment information
+type Pag struct {
+	DetPag []DetPag `xml:"detPag"`
+}
+
+// DetPag is one payment
+type DetPag struct {
+	TPag string `xml:"tPag"`
+	XPag string `xml:"xPag,omitempty"` // Required when tPag is 99
+	VPag string `xml:"vPag"`
+}
+
+// InfAdic holds free-text complementary information
+type InfAdic struct {
+	InfCpl string `xml:"infCpl,omitempty"`
+}
@@ -0,0 +1,111 @@
+package nfe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// SEFAZ status codes (cStat) we act on
+const (
+	StatusAuthorized = 100 // Autorizado o uso da NF-e
+)
+
+// Authorization is SEFAZ's answer to an authorization request
+type Authorization struct {
+	Protocol      string
+	StatusCode    int
+	StatusMessage string
+	AuthorizedAt  time.Time
+}
+
+// Authorized reports whether SEFAZ accepted the invoice
+func (a *Authorization) Authorized() bool {
+	return a.StatusCode == StatusAuthorized
+}
+
+// Transmitter sends a signed NF-e to SEFAZ (NFeAutorizacao4) and returns the outcome
+type Transmitter interface {
+	Authorize(accessKey string, environment int, signedXML []byte) (*Authorization, error)
+}
+
+type stubTransmitter struct{}
+
+// NewStubTransmitter creates a transmitter that authorizes every well-formed NF-e locally,
+// for development and homologation without a SEFAZ connection
+func NewStubTransmitter() Transmitter {
+	return &stubTransmitter{}
+}
+
+// Authorize fakes a synchronous authorization
+func (t *stubTransmitter) Authorize(accessKey string, environment int, signedXML []byte) (*Authorization, error) {
+	if !ValidAccessKey(accessKey) {
+		return &Authorization{StatusCode: 236, StatusMessage: "Rejeição: Chave de Acesso com dígito verificador inválido"}, nil
+	}
+	if _, err := Parse(signedXML); err != nil {
+		return &Authorization{StatusCode: 225, StatusMessage: "Rejeição: Falha no Schema XML da NFe"}, nil
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1e9))
+	if err != nil {
+		return nil, err
+	}
+	return &Authorization{
+		// Protocol: environment + cUF + year + sequence (15 digits)
+		Protocol:      fmt.Sprintf("%d%s%s%09d", environment, accessKey[:2], time.Now().Format("06"), n.Int64()),
+		StatusCode:    StatusAuthorized,
+		StatusMessage: "Autorizado o uso da NF-e",
+		AuthorizedAt:  time.Now(),
+	}, nil
+}
+
+// protNFe is the authorization protocol appended to the signed NF-e
+type protNFe struct {
+	XMLName xml.Name `xml:"protNFe"`
+	Versao  string   `xml:"versao,attr"`
+	InfProt struct {
+		TpAmb    int    `xml:"tpAmb"`
+		VerAplic string `xml:"verAplic"`
+		ChNFe    string `xml:"chNFe"`
+		DhRecbto string `xml:"dhRecbto"`
+		NProt    string `xml:"nProt"`
+		CStat    int    `xml:"cStat"`
+		XMotivo  string `xml:"xMotivo"`
+	} `xml:"infProt"`
+}
+
+// WrapProc builds the nfeProc distribution file (signed NFe + protNFe) that is
+// handed to the customer and stored for the legal retention period
+func WrapProc(signedXML []byte, accessKey string, environment int, auth *Authorization) ([]byte, error) {
+	var prot protNFe
+	prot.Versao = Version
+	prot.InfProt.TpAmb = environment
+	prot.InfProt.VerAplic = "ecommerce-api 1.0"
+	prot.InfProt.ChNFe = accessKey
+	prot.InfProt.DhRecbto = auth.AuthorizedAt.In(brasiliaTime).Format("2006-01-02T15:04:05-07:00")
+	prot.InfProt.NProt = auth.Protocol
+	prot.InfProt.CStat = auth.StatusCode
+	prot.InfProt.XMotivo = auth.StatusMessage
+
+	protXML, err := xml.Marshal(prot)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signed NFe must be embedded byte for byte, so it is spliced in rather than re-marshalled
+	body := signedXML
+	if i := bytes.Index(body, []byte("<NFe")); i >= 0 {
+		body = body[i:]
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<nfeProc versao="` + Version + `" xmlns="` + Namespace + `">`)
+	buf.Write(bytes.TrimSpace(body))
+	buf.Write(protXML)
+	buf.WriteString(`</nfeProc>`)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package nfe
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// Signer applies the XML-DSig signature SEFAZ requires on infNFe
+type Signer interface {
+	Sign(document []byte) ([]byte, error)
+}
+
+type a1Signer struct {
+	key  *rsa.PrivateKey
+	leaf []byte // DER certificate
+}
+
+// LoadA1Signer reads an ICP-Brasil A1 certificate (PKCS#12 .pfx/.p12) from disk
+func LoadA1Signer(path, password string) (Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewA1Signer(data, password)
+}
+
+// NewA1Signer creates a signer from PKCS#12 data
+func NewA1Signer(pfx []byte, password string) (Signer, error) {
+	blocks, err := pkcs12.ToPEM(pfx, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open A1 certificate: %w", err)
+	}
+
+	var key *rsa.PrivateKey
+	var certs []*x509.Certificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "PRIVATE KEY":
+			parsed, err := parsePrivateKey(block)
+			if err != nil {
+				return nil, err
+			}
+			key = parsed
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+	if key == nil || len(certs) == 0 {
+		return nil, errors.New("A1 certificate must contain a private key and its certificate")
+	}
+
+	// SEFAZ only needs the leaf certificate, i.e. the one matching the private key
+	for _, cert := range certs {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok || !pub.Equal(&key.PublicKey) {
+			continue
+		}
+		if time.Now().After(cert.NotAfter) {
+			return nil, fmt.Errorf("A1 certificate expired on %s", cert.NotAfter.Format("2006-01-02"))
+		}
+		return &a1Signer{key: key, leaf: cert.Raw}, nil
+	}
+	return nil, errors.New("A1 certificate does not match its private key")
+}
+
+// Sign adds an enveloped RSA-SHA1 signature over infNFe as the last child of NFe,
+// using inclusive C14N as required by the NF-e manual
+func (s *a1Signer) Sign(document []byte) ([]byte, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(document); err != nil {
+		return nil, err
+	}
+
+	root := doc.Root()
+	if root == nil || root.Tag != "NFe" {
+		return nil, errors.New("document root is not NFe")
+	}
+	infNFe := root.SelectElement("infNFe")
+	if infNFe == nil {
+		return nil, errors.New("document has no infNFe")
+	}
+
+	ctx, err := dsig.NewSigningContext(s.key, [][]byte{s.leaf})
+	if err != nil {
+		return nil, err
+	}
+	ctx.Hash = crypto.SHA1
+	ctx.Prefix = ""
+	ctx.IdAttribute = "Id"
+	ctx.Canonicalizer = dsig.MakeC14N10RecCanonicalizer()
+
+	signature, err := ctx.ConstructSignature(infNFe, true)
+	if err != nil {
+		return nil, err
+	}
+	root.AddChild(signature)
+
+	return doc.WriteToBytes()
+}
+
+func parsePrivateKey(block *pem.Block) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("A1 certificate key must be RSA")
+	}
+	return key, nil
+}
@@ -0,0 +1,243 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LocalClientID is the only client the local provider accepts
+const LocalClientID = "local-client"
+
+const (
+	localCodeTTL    = time.Minute
+	localIDTokenTTL = 5 * time.Minute
+	localKeyID      = "local-1"
+)
+
+// localGrant is an issued authorization code waiting to be redeemed
+type localGrant struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	email         string
+	name          string
+	expiresAt     time.Time
+}
+
+// LocalProvider is a minimal OIDC provider served by the API itself (development and tests),
+// so the social login flow runs without network access. Anyone can sign in as any email:
+// the authorization page just asks for one. Codes live in memory, and the signing key is
+// generated at startup.
+type LocalProvider struct {
+	issuer string
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]localGrant
+}
+
+// NewLocalProvider creates the provider; issuer is the URL its handler is mounted at
+func NewLocalProvider(issuer string) (*LocalProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalProvider{
+		issuer: strings.TrimRight(issuer, "/"),
+		key:    key,
+		grants: make(map[string]localGrant),
+	}, nil
+}
+
+// Config returns the client configuration matching this provider
+func (l *LocalProvider) Config() Config {
+	return Config{Name: "local", Issuer: l.issuer, ClientID: LocalClientID}
+}
+
+// ServeHTTP serves discovery, the authorization page, the token endpoint and the JWKS
+func (l *LocalProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+		l.discovery(w)
+	case strings.HasSuffix(r.URL.Path, "/authorize"):
+		l.authorize(w, r)
+	case strings.HasSuffix(r.URL.Path, "/token"):
+		l.token(w, r)
+	case strings.HasSuffix(r.URL.Path, "/jwks"):
+		l.jwks(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (l *LocalProvider) discovery(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                l.issuer,
+		"authorization_endpoint":                l.issuer + "/authorize",
+		"token_endpoint":                        l.issuer + "/token",
+		"jwks_uri":                              l.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+var localLoginPage = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Local OIDC provider</title></head>
+<body>
+<h1>Local OIDC provider</h1>
+<p>Development only: sign in as any email address.</p>
+<form method="get">
+{{range $name, $value := .}}<input type="hidden" name="{{$name}}" value="{{index $value 0}}">
+{{end}}<label>Email <input type="email" name="login_hint" required></label>
+<button type="submit">Sign in</button>
+</form>
+</body></html>`))
+
+// authorize asks for an email (or takes login_hint) and redirects back with a code
+func (l *LocalProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != LocalClientID || query.Get("response_type") != "code" {
+		http.Error(w, "unsupported client_id or response_type", http.StatusBadRequest)
+		return
+	}
+	if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(query.Get("login_hint")))
+	if email == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		localLoginPage.Execute(w, query)
+		return
+	}
+
+	code, err := randomString(32)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	l.mu.Lock()
+	for pending, grant := range l.grants {
+		if time.Now().After(grant.expiresAt) {
+			delete(l.grants, pending)
+		}
+	}
+	l.grants[code] = localGrant{
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		email:         email,
+		name:          query.Get("name"),
+		expiresAt:     time.Now().Add(localCodeTTL),
+	}
+	l.mu.Unlock()
+
+	back := redirectURI.Query()
+	back.Set("code", code)
+	back.Set("state", query.Get("state"))
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token redeems a code once, checking the redirect URI and the PKCE verifier
+func (l *LocalProvider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != LocalClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unauthorized_client"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	l.mu.Lock()
+	grant, ok := l.grants[code]
+	delete(l.grants, code)
+	l.mu.Unlock()
+
+	challenge := CodeChallenge(r.PostForm.Get("code_verifier"))
+	if !ok || time.Now().After(grant.expiresAt) || grant.redirectURI != r.PostForm.Get("redirect_uri") ||
+		subtle.ConstantTimeCompare([]byte(challenge), []byte(grant.codeChallenge)) != 1 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	// Subjects are stable per email, like a real provider's account ID
+	sum := sha256.Sum256([]byte(grant.email))
+	name := grant.name
+	if name == "" {
+		name = strings.SplitN(grant.email, "@", 2)[0]
+	}
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    l.issuer,
+			Subject:   hex.EncodeToString(sum[:8]),
+			Audience:  jwt.ClaimStrings{LocalClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(localIDTokenTTL)),
+		},
+		Nonce:         grant.nonce,
+		Email:         grant.email,
+		EmailVerified: true,
+		Name:          name,
+	})
+	idToken.Header["kid"] = localKeyID
+	signed, err := idToken.SignedString(l.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	accessToken, _ := randomString(32)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(localIDTokenTTL.Seconds()),
+		"id_token":     signed,
+	})
+}
+
+func (l *LocalProvider) jwks(w http.ResponseWriter) {
+	public := l.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": localKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
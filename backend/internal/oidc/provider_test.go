@@ -0,0 +1,159 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testRedirectURI = "http://app.test/auth/callback/local"
+
+// startLocal serves a LocalProvider through httptest and returns a client configured for it
+func startLocal(t *testing.T) (*LocalProvider, *Provider) {
+	t.Helper()
+	var local *LocalProvider
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		local.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	local, err := NewLocalProvider(server.URL)
+	if err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+	return local, NewProvider(local.Config(), server.Client())
+}
+
+// authorize runs the authorization request as email and returns the code from the redirect
+func authorize(t *testing.T, p *Provider, email string, state string, nonce string, verifier string) string {
+	t.Helper()
+	authURL, err := p.AuthCodeURL(testRedirectURI, state, nonce, CodeChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL + "&login_hint=" + url.QueryEscape(email))
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d, want 302", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize: redirect: %v", err)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("authorize: state %q, want %q", got, state)
+	}
+	return location.Query().Get("code")
+}
+
+// signIDToken signs an ID token for the local provider's client with key and kid
+func signIDToken(t *testing.T, key *rsa.PrivateKey, kid string, issuer string, audience string, nonce string) string {
+	t.Helper()
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   "subject-1",
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+		Nonce: nonce,
+		Email: "ana@example.com",
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign ID token: %v", err)
+	}
+	return signed
+}
+
+func TestExchange(t *testing.T) {
+	_, p := startLocal(t)
+	verifier, _ := NewCodeVerifier()
+	code := authorize(t, p, "Ana@Example.com", "state-1", "nonce-1", verifier)
+
+	claims, err := p.Exchange(code, verifier, testRedirectURI, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if claims.Email != "ana@example.com" || !claims.IsEmailVerified() || claims.Subject == "" {
+		t.Errorf("claims = %+v, want a verified ana@example.com with a subject", claims)
+	}
+
+	if _, err := p.Exchange(code, verifier, testRedirectURI, "nonce-1"); err == nil {
+		t.Error("Exchange accepted an authorization code twice")
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	_, p := startLocal(t)
+	verifier, _ := NewCodeVerifier()
+	code := authorize(t, p, "ana@example.com", "state-1", "nonce-1", verifier)
+
+	_, err := p.Exchange(code, verifier, testRedirectURI, "another-nonce")
+	if !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("Exchange error = %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestExchangeRejectsBadCodeVerifier(t *testing.T) {
+	_, p := startLocal(t)
+	verifier, _ := NewCodeVerifier()
+	code := authorize(t, p, "ana@example.com", "state-1", "nonce-1", verifier)
+
+	other, _ := NewCodeVerifier()
+	if _, err := p.Exchange(code, other, testRedirectURI, "nonce-1"); err == nil {
+		t.Error("Exchange accepted a code_verifier that does not match the challenge")
+	}
+}
+
+func TestVerifyRejectsForeignTokens(t *testing.T) {
+	local, p := startLocal(t)
+	metadata, err := p.discover()
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	unknownKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"wrong audience", signIDToken(t, local.key, localKeyID, local.issuer, "another-client", "nonce-1")},
+		{"wrong issuer", signIDToken(t, local.key, localKeyID, "https://evil.example.com", LocalClientID, "nonce-1")},
+		{"unknown key ID", signIDToken(t, unknownKey, "other-key", local.issuer, LocalClientID, "nonce-1")},
+		{"known key ID, other key", signIDToken(t, unknownKey, localKeyID, local.issuer, LocalClientID, "nonce-1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := p.verify(metadata, tt.token, "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("verify error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+
+	valid := signIDToken(t, local.key, localKeyID, local.issuer, LocalClientID, "nonce-1")
+	if _, err := p.verify(metadata, valid, "nonce-1"); err != nil {
+		t.Errorf("verify rejected a valid token: %v", err)
+	}
+}
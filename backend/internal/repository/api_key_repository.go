@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository defines seller API key data operations
+type APIKeyRepository interface {
+	Create(key *domain.APIKey) error
+	GetByID(userID string, id string) (*domain.APIKey, error)
+	GetByPrefix(prefix string) (*domain.APIKey, error)
+	ListByUser(userID string) ([]domain.APIKey, error)
+	CountActive(userID string, now time.Time) (int64, error)
+	Revoke(id string, at time.Time) (bool, error)
+	TouchUsed(id string, at time.Time, ip string) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create inserts a new key
+func (r *apiKeyRepository) Create(key *domain.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// GetByID retrieves one of the user's keys
+func (r *apiKeyRepository) GetByID(userID string, id string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	result := r.db.Where("user_id = ? AND id = ?", userID, id).First(&key)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// GetByPrefix retrieves a key by its public prefix, revoked or not
+func (r *apiKeyRepository) GetByPrefix(prefix string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	result := r.db.Where("prefix = ?", prefix).First(&key)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// ListByUser retrieves all of the user's keys, newest first
+func (r *apiKeyRepository) ListByUser(userID string) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountActive counts the user's keys that are neither revoked nor expired
+func (r *apiKeyRepository) CountActive(userID string, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke marks a key as revoked. It returns false when it already was.
+func (r *apiKeyRepository) Revoke(id string, at time.Time) (bool, error) {
+	result := r.db.Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// TouchUsed records when and from where the key was last used
+func (r *apiKeyRepository) TouchUsed(id string, at time.Time, ip string) error {
+	return r.db.Model(&domain.APIKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditRepository defines audit log data operations
+type AuditRepository interface {
+	Create(entry *domain.AuditLog) error
+	ListByUser(userID string, limit int, offset int) ([]domain.AuditLog, int64, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create appends an entry to the audit log
+func (r *auditRepository) Create(entry *domain.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// ListByUser retrieves the entries of an account, newest first
+func (r *auditRepository) ListByUser(userID string, limit int, offset int) ([]domain.AuditLog, int64, error) {
+	var entries []domain.AuditLog
+	var total int64
+
+	query := r.db.Model(&domain.AuditLog{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CartRepository defines cart data operations
+type CartRepository interface {
+	GetByUserID(userID string) (*domain.Cart, error)
+	GetByToken(token string) (*domain.Cart, error)
+	Create(cart *domain.Cart) error
+	Touch(cart *domain.Cart) error
+	SaveItem(item *domain.CartItem) error
+	DeleteItem(cartID string, itemID string) error
+	Clear(cartID string) error
+	Merge(guest *domain.Cart, userCart *domain.Cart) error
+}
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+// NewCartRepository creates a new cart repository
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+// GetByUserID retrieves a user's cart with items and product details
+func (r *cartRepository) GetByUserID(userID string) (*domain.Cart, error) {
+	return r.find("user_id = ?", userID)
+}
+
+// GetByToken retrieves a guest cart that has not expired
+func (r *cartRepository) GetByToken(token string) (*domain.Cart, error) {
+	return r.find("token = ? AND user_id IS NULL AND (expires_at IS NULL OR expires_at > ?)", token, time.Now())
+}
+
+func (r *cartRepository) find(query string, args ...interface{}) (*domain.Cart, error) {
+	var cart domain.Cart
+	result := r.db.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Items.Product").
+		Preload("Items.Product.Prices").
+		Where(query, args...).
+		First(&cart)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &cart, nil
+}
+
+// Create inserts a new, empty cart
+func (r *cartRepository) Create(cart *domain.Cart) error {
+	return r.db.Omit("Items").Create(cart).Error
+}
+
+// Touch saves the cart's expiry and bumps its update time
+func (r *cartRepository) Touch(cart *domain.Cart) error {
+	return r.db.Model(cart).Select("expires_at", "updated_at").Updates(map[string]interface{}{
+		"expires_at": cart.ExpiresAt,
+		"updated_at": time.Now(),
+	}).Error
+}
+
+// SaveItem creates or updates a cart line
+func (r *cartRepository) SaveItem(item *domain.CartItem) error {
+	return r.db.Omit("Product").Save(item).Error
+}
+
+// DeleteItem removes one line from a cart
+func (r *cartRepository) DeleteItem(cartID string, itemID string) error {
+	return r.db.Where("id = ? AND cart_id = ?", itemID, cartID).Delete(&domain.CartItem{}).Error
+}
+
+// Clear removes every line from a cart
+func (r *cartRepository) Clear(cartID string) error {
+	return r.db.Where("cart_id = ?", cartID).Delete(&domain.CartItem{}).Error
+}
+
+// Merge moves a guest cart into a user's cart in a single transaction.
+// userCart.Items must already hold the merged lines (lines taken from the guest cart
+// with an empty ID); the guest cart is deleted.
+func (r *cartRepository) Merge(guest *domain.Cart, userCart *domain.Cart) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cart_id = ?", guest.ID).Delete(&domain.CartItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.Cart{}, "id = ?", guest.ID).Error; err != nil {
+			return err
+		}
+
+		for i := range userCart.Items {
+			userCart.Items[i].CartID = userCart.ID
+			if err := tx.Omit("Product").Save(&userCart.Items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(userCart).Update("updated_at", time.Now()).Error
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepository defines exchange rate data operations
+type ExchangeRateRepository interface {
+	Get(base string, quote string) (*domain.ExchangeRate, error)
+	ListFrom(base string) ([]domain.ExchangeRate, error)
+	Save(rate *domain.ExchangeRate) error
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// Get retrieves the rate from base to quote
+func (r *exchangeRateRepository) Get(base string, quote string) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	result := r.db.Where("base = ? AND quote = ?", base, quote).First(&rate)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &rate, nil
+}
+
+// ListFrom retrieves every rate quoted from a base currency
+func (r *exchangeRateRepository) ListFrom(base string) ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+	err := r.db.Where("base = ?", base).Order("quote ASC").Find(&rates).Error
+	return rates, err
+}
+
+// Save inserts the rate or updates the existing one for the same pair
+func (r *exchangeRateRepository) Save(rate *domain.ExchangeRate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+}
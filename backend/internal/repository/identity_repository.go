@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdentityRepository defines linked OIDC identity and pending login state data operations
+type IdentityRepository interface {
+	Create(identity *domain.UserIdentity) error
+	Get(provider string, subject string) (*domain.UserIdentity, error)
+	ListByUser(userID string) ([]domain.UserIdentity, error)
+	TouchLogin(id string) error
+	ClaimAccount(userID string, at time.Time) error
+	CreateState(state *domain.OIDCLoginState) error
+	ConsumeState(stateHash string) (*domain.OIDCLoginState, error)
+}
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository creates a new identity repository
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create links a new identity
+func (r *identityRepository) Create(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// Get retrieves the identity of a provider account
+func (r *identityRepository) Get(provider string, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	result := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &identity, nil
+}
+
+// ListByUser retrieves the identities linked to a user, oldest first
+func (r *identityRepository) ListByUser(userID string) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+// TouchLogin records a login through the identity
+func (r *identityRepository) TouchLogin(id string) error {
+	return r.db.Model(&domain.UserIdentity{}).Where("id = ?", id).Update("last_login_at", time.Now()).Error
+}
+
+// ClaimAccount hands an unverified account over to the owner of its mailbox, in a single
+// transaction: whoever registered it loses every way in and everything they set up. The
+// password and 2FA are cleared, sessions revoked, and linked identities, recovery codes,
+// API keys and store memberships removed. Pending invitations to the email are kept.
+func (r *identityRepository) ClaimAccount(userID string, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password_hash":         "",
+			"totp_secret":           nil,
+			"totp_last_step":        0,
+			"two_factor_enabled_at": nil,
+			"email_verified_at":     at,
+			"updated_at":            at,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		revoked := map[string]interface{}{"revoked_at": at, "revoked_reason": domain.RevokedIdentityLinked}
+		if err := tx.Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Updates(revoked).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).Updates(revoked).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.APIKey{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", at).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.StoreMember{}).Error
+	})
+}
+
+// CreateState stores a pending authorization request, dropping expired ones on the way
+func (r *identityRepository) CreateState(state *domain.OIDCLoginState) error {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&domain.OIDCLoginState{}).Error; err != nil {
+		return err
+	}
+	return r.db.Create(state).Error
+}
+
+// ConsumeState deletes and returns a pending authorization request, expired or not.
+// Only the request that deleted the row gets it, so a state cannot be redeemed twice.
+func (r *identityRepository) ConsumeState(stateHash string) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	result := r.db.Where("state_hash = ?", stateHash).First(&state)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+
+	deleted := r.db.Where("id = ?", state.ID).Delete(&domain.OIDCLoginState{})
+	if deleted.Error != nil {
+		return nil, deleted.Error
+	}
+	if deleted.RowsAffected != 1 {
+		return nil, nil
+	}
+	return &state, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvoiceOpen is returned when reserving a number for an order the seller already has a pending or authorized invoice for
+var ErrInvoiceOpen = errors.New("an invoice for this order is already authorized or being issued")
+
+// InvoiceRepository defines NF-e and fiscal profile data operations
+type InvoiceRepository interface {
+	Update(invoice *domain.Invoice) error
+	GetLatest(orderID string, sellerID string) (*domain.Invoice, error)
+	GetFiscalProfile(sellerID string) (*domain.FiscalProfile, error)
+	SaveFiscalProfile(profile *domain.FiscalProfile) error
+	ReserveNumber(invoice *domain.Invoice) error
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new invoice repository
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// Update saves an invoice reserved with ReserveNumber
+func (r *invoiceRepository) Update(invoice *domain.Invoice) error {
+	return r.db.Save(invoice).Error
+}
+
+// GetLatest retrieves the most recent invoice a seller issued for an order
+func (r *invoiceRepository) GetLatest(orderID string, sellerID string) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	result := r.db.
+		Where("order_id = ? AND seller_id = ?", orderID, sellerID).
+		Order("created_at DESC").
+		First(&invoice)
+
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &invoice, nil
+}
+
+// GetFiscalProfile retrieves a seller's fiscal profile
+func (r *invoiceRepository) GetFiscalProfile(sellerID string) (*domain.FiscalProfile, error) {
+	var profile domain.FiscalProfile
+	result := r.db.Where("seller_id = ?", sellerID).First(&profile)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &profile, nil
+}
+
+// SaveFiscalProfile creates or updates a fiscal profile
+func (r *invoiceRepository) SaveFiscalProfile(profile *domain.FiscalProfile) error {
+	return r.db.Save(profile).Error
+}
+
+// ReserveNumber atomically takes the next NF-e number of the seller's series and inserts the invoice
+// as pending with it, unless the seller already has a pending or authorized invoice for the order
+// (ErrInvoiceOpen; the idx_invoices_open unique index settles concurrent reservations).
+// Numbers are never reused, even when the invoice is later rejected (it must be voided instead).
+func (r *invoiceRepository) ReserveNumber(invoice *domain.Invoice) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		open, err := r.hasOpenInvoice(tx, invoice.OrderID, invoice.SellerID)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrInvoiceOpen
+		}
+
+		var profile domain.FiscalProfile
+		if err := tx.Where("seller_id = ?", invoice.SellerID).First(&profile).Error; err != nil {
+			return err
+		}
+
+		number := profile.NextNumber
+		result := tx.Model(&domain.FiscalProfile{}).
+			Where("id = ? AND next_number = ?", profile.ID, number).
+			Update("next_number", number+1)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invoice number was taken concurrently, try again")
+		}
+
+		invoice.Number = number
+		invoice.Series = profile.Series
+		invoice.Status = domain.InvoiceStatusPending
+		return tx.Create(invoice).Error
+	})
+	if err != nil && !errors.Is(err, ErrInvoiceOpen) {
+		// Lost the race for the unique index to a concurrent reservation
+		if open, openErr := r.hasOpenInvoice(r.db, invoice.OrderID, invoice.SellerID); openErr == nil && open {
+			return ErrInvoiceOpen
+		}
+	}
+	return err
+}
+
+// hasOpenInvoice reports whether the seller has a pending or authorized invoice for the order
+func (r *invoiceRepository) hasOpenInvoice(db *gorm.DB, orderID string, sellerID string) (bool, error) {
+	var count int64
+	err := db.Model(&domain.Invoice{}).
+		Where("order_id = ? AND seller_id = ? AND status <> ?", orderID, sellerID, domain.InvoiceStatusRejected).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MetricsRepository defines platform-wide aggregate queries
+type MetricsRepository interface {
+	PlatformMetrics(since time.Time) (*domain.PlatformMetrics, error)
+}
+
+type metricsRepository struct {
+	db *gorm.DB
+}
+
+// NewMetricsRepository creates a new metrics repository
+func NewMetricsRepository(db *gorm.DB) MetricsRepository {
+	return &metricsRepository{db: db}
+}
+
+// groupCount is one row of a COUNT(*) ... GROUP BY query
+type groupCount struct {
+	Key   string
+	Count int64
+}
+
+// PlatformMetrics counts users, products, orders and revenue; "last 30 days" figures start at since
+func (r *metricsRepository) PlatformMetrics(since time.Time) (*domain.PlatformMetrics, error) {
+	m := &domain.PlatformMetrics{
+		Users:  domain.UserMetrics{ByRole: make(map[string]int64)},
+		Orders: domain.OrderMetrics{ByStatus: make(map[string]int64)},
+	}
+
+	var roles []groupCount
+	if err := r.db.Model(&domain.User{}).Select("role AS key, COUNT(*) AS count").Group("role").Scan(&roles).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range roles {
+		m.Users.ByRole[row.Key] = row.Count
+		m.Users.Total += row.Count
+	}
+
+	var statuses []groupCount
+	if err := r.db.Model(&domain.Order{}).Select("status AS key, COUNT(*) AS count").Group("status").Scan(&statuses).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range statuses {
+		m.Orders.ByStatus[row.Key] = row.Count
+		m.Orders.Total += row.Count
+	}
+
+	counts := []struct {
+		dst   *int64
+		query *gorm.DB
+	}{
+		{&m.Users.Suspended, r.db.Model(&domain.User{}).Where("is_active = ?", false)},
+		{&m.Users.Unverified, r.db.Model(&domain.User{}).Where("email_verified_at IS NULL")},
+		{&m.Users.NewLast30d, r.db.Model(&domain.User{}).Where("created_at >= ?", since)},
+		{&m.Products.Total, r.db.Model(&domain.Product{})},
+		{&m.Products.Active, r.db.Model(&domain.Product{}).Where("is_active = ?", true)},
+		{&m.Products.OutOfStock, r.db.Model(&domain.Product{}).Where("is_active = ? AND stock_quantity <= 0", true)},
+		{&m.Orders.Last30d, r.db.Model(&domain.Order{}).Where("created_at >= ?", since)},
+		{&m.Stores, r.db.Model(&domain.Store{})},
+		{&m.PendingSellerApplications, r.db.Model(&domain.SellerApplication{}).Where("status = ?", domain.ApplicationPending)},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.dst).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	// Amounts in different currencies are never added up
+	var revenues []struct {
+		Currency string
+		Orders   int64
+		Total    int64
+	}
+	err := r.db.Model(&domain.Order{}).
+		Select("currency, COUNT(*) AS orders, COALESCE(SUM(total_amount), 0) AS total").
+		Where("status <> ?", domain.OrderCancelled).
+		Group("currency").
+		Order("currency").
+		Scan(&revenues).Error
+	if err != nil {
+		return nil, err
+	}
+	m.Revenue = make([]domain.CurrencyRevenue, 0, len(revenues))
+	for _, row := range revenues {
+		total := domain.NewMoney(row.Total, row.Currency)
+		average, err := total.Ratio(1, row.Orders)
+		if err != nil {
+			return nil, err
+		}
+		m.Revenue = append(m.Revenue, domain.CurrencyRevenue{
+			Currency:          row.Currency,
+			TotalOrders:       row.Orders,
+			TotalRevenue:      total,
+			AverageOrderValue: average,
+		})
+	}
+
+	return m, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"ecommerce/internal/domain"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +14,9 @@ type OrderRepository interface {
 	GetByUserID(userID string, limit int, offset int) ([]domain.Order, int64, error)
 	UpdateStatus(orderID string, status string) error
 	GetSellerOrders(sellerID string, limit int, offset int) ([]domain.Order, int64, error)
+	List(filter domain.OrderFilter, limit int, offset int) ([]domain.Order, int64, error)
+	Update(order *domain.Order) error
+	CountByUserAndStatus(userID string, statuses []string) (int64, error)
 }
 
 type orderRepository struct {
@@ -112,14 +116,15 @@ func (r *orderRepository) GetSellerOrders(sellerID string, limit int, offset int
 		return nil, 0, err
 	}
 
-	// Fetch orders
+	// Fetch orders; filtering by subquery keeps every order column (DISTINCT orders.id would select only the ID)
+	sellerOrderIDs := r.db.Model(&domain.OrderItem{}).
+		Select("order_items.order_id").
+		Joins("INNER JOIN products ON products.id = order_items.product_id").
+		Where("products.seller_id = ?", sellerID)
 	result := r.db.
 		Preload("Items").
 		Preload("Items.Product").
-		Joins("INNER JOIN order_items ON order_items.order_id = orders.id").
-		Joins("INNER JOIN products ON products.id = order_items.product_id").
-		Where("products.seller_id = ?", sellerID).
-		Distinct("orders.id").
+		Where("orders.id IN (?)", sellerOrderIDs).
 		Order("orders.created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -127,3 +132,44 @@ func (r *orderRepository) GetSellerOrders(sellerID string, limit int, offset int
 
 	return orders, total, result.Error
 }
+
+// List retrieves orders matching the filter with their items, newest first
+func (r *orderRepository) List(filter domain.OrderFilter, limit int, offset int) ([]domain.Order, int64, error) {
+	var orders []domain.Order
+	var total int64
+
+	query := r.db.Model(&domain.Order{})
+	if filter.Query != "" {
+		query = query.Where("UPPER(order_number) LIKE ?", strings.ToUpper(strings.TrimSpace(filter.Query))+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.
+		Preload("Items").
+		Preload("Items.Product").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// Update saves the order's own columns; items are left untouched
+func (r *orderRepository) Update(order *domain.Order) error {
+	return r.db.Omit("Items").Save(order).Error
+}
+
+// CountByUserAndStatus counts the user's orders in any of the given statuses
+func (r *orderRepository) CountByUserAndStatus(userID string, statuses []string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Order{}).Where("user_id = ? AND status IN ?", userID, statuses).Count(&count).Error
+	return count, err
+}
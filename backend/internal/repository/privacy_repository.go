@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository defines data-subject request operations
+type PrivacyRepository interface {
+	Create(request *domain.PrivacyRequest) error
+	GetByID(id string) (*domain.PrivacyRequest, error)
+	GetActive(userID string, kind string) (*domain.PrivacyRequest, error)
+	ListUnfinished() ([]domain.PrivacyRequest, error)
+	Update(request *domain.PrivacyRequest) error
+	EraseUser(userID string, at time.Time) error
+}
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository creates a new privacy repository
+func NewPrivacyRepository(db *gorm.DB) PrivacyRepository {
+	return &privacyRepository{db: db}
+}
+
+// Create saves a new request
+func (r *privacyRepository) Create(request *domain.PrivacyRequest) error {
+	return r.db.Create(request).Error
+}
+
+// GetByID retrieves a request, archive included
+func (r *privacyRepository) GetByID(id string) (*domain.PrivacyRequest, error) {
+	var request domain.PrivacyRequest
+	result := r.db.Where("id = ?", id).First(&request)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// GetActive retrieves the user's pending or running request of a kind
+func (r *privacyRepository) GetActive(userID string, kind string) (*domain.PrivacyRequest, error) {
+	var request domain.PrivacyRequest
+	result := r.db.Omit("archive").
+		Where("user_id = ? AND kind = ? AND status IN ?", userID, kind, []string{domain.PrivacyPending, domain.PrivacyRunning}).
+		First(&request)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// ListUnfinished retrieves the requests left pending or running, oldest first
+func (r *privacyRepository) ListUnfinished() ([]domain.PrivacyRequest, error) {
+	var requests []domain.PrivacyRequest
+	err := r.db.Omit("archive").
+		Where("status IN ?", []string{domain.PrivacyPending, domain.PrivacyRunning}).
+		Order("created_at ASC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// Update saves a request
+func (r *privacyRepository) Update(request *domain.PrivacyRequest) error {
+	return r.db.Save(request).Error
+}
+
+// EraseUser anonymizes an account in a single transaction.
+// Orders keep their amounts, items, tax document and destination city/state, which
+// fiscal law requires us to retain; invoices are left untouched. Everything else
+// that identifies the person is cleared or deleted, and every session is revoked.
+func (r *privacyRepository) EraseUser(userID string, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Store memberships and the invitations addressed to the user's email go first, while the email is known
+		err := tx.Where("user_id = ? OR email = (SELECT LOWER(email) FROM users WHERE id = ?)", userID, userID).
+			Delete(&domain.StoreMember{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"name":                  domain.ErasedUserName,
+			"email":                 "erased-" + userID + "@invalid",
+			"password_hash":         "",
+			"avatar_url":            nil,
+			"is_active":             false,
+			"email_verified_at":     nil,
+			"pending_email":         nil,
+			"totp_secret":           nil,
+			"totp_last_step":        0,
+			"two_factor_enabled_at": nil,
+			"erased_at":             at,
+			"updated_at":            at,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var orders []domain.Order
+		if err := tx.Select("id", "shipping_address").Where("user_id = ?", userID).Find(&orders).Error; err != nil {
+			return err
+		}
+		for _, order := range orders {
+			updates := map[string]interface{}{"notes": nil}
+			if order.ShippingAddress != nil {
+				updates["shipping_address"] = &domain.ShippingAddress{
+					City:     order.ShippingAddress.City,
+					CityCode: order.ShippingAddress.CityCode,
+					State:    order.ShippingAddress.State,
+					Country:  order.ShippingAddress.Country,
+				}
+			}
+			if err := tx.Model(&domain.Order{}).Where("id = ?", order.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&domain.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": domain.RevokedErased}).Error
+		if err != nil {
+			return err
+		}
+
+		// Device names are chosen by the user and may identify them
+		err = tx.Model(&domain.Session{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"device_name": nil, "user_agent": "", "ip_address": ""}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&domain.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": domain.RevokedErased}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&domain.AuditLog{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"details": nil, "ip_address": "", "user_agent": ""}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&domain.PrivacyRequest{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"archive": nil}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("cart_id IN (?)", tx.Model(&domain.Cart{}).Select("id").Where("user_id = ?", userID)).Delete(&domain.CartItem{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&domain.Cart{}, &domain.UserAddress{}, &domain.UserToken{}, &domain.RecoveryCode{}, &domain.UserIdentity{}, &domain.SellerApplication{}} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -13,6 +13,10 @@ type ProductRepository interface {
 	Update(product *domain.Product) error
 	Delete(id string) error
 	FindBySellerID(sellerID string, limit int, offset int) ([]domain.Product, int64, error)
+	FindActiveBySellerID(sellerID string, limit int, offset int) ([]domain.Product, int64, error)
+	SetPrices(productID string, prices []domain.ProductPrice) error
+	Search(filter domain.ProductFilter, limit int, offset int) ([]domain.Product, int64, error)
+	SetSellerSuspended(sellerID string, suspended bool) error
 }
 
 type productRepository struct {
@@ -23,19 +27,20 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
+// List retrieves a page of the public catalog; products of suspended sellers are left out
 func (r *productRepository) List(limit int, offset int) ([]domain.Product, int64, error) {
 	var products []domain.Product
 	var total int64
 
-	r.db.Model(&domain.Product{}).Count(&total)
-	res := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&products)
+	r.db.Model(&domain.Product{}).Where("seller_suspended = ?", false).Count(&total)
+	res := r.db.Preload("Prices").Where("seller_suspended = ?", false).Limit(limit).Offset(offset).Order("created_at DESC").Find(&products)
 	return products, total, res.Error
 }
 
 // FindByID retrieves a product by ID
 func (r *productRepository) FindByID(id string) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.Where("id = ?", id).First(&product).Error
+	err := r.db.Preload("Prices").Where("id = ?", id).First(&product).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -66,6 +71,61 @@ func (r *productRepository) FindBySellerID(sellerID string, limit int, offset in
 	var total int64
 
 	r.db.Where("seller_id = ?", sellerID).Model(&domain.Product{}).Count(&total)
-	err := r.db.Where("seller_id = ?", sellerID).Limit(limit).Offset(offset).Order("created_at DESC").Find(&products).Error
+	err := r.db.Preload("Prices").Where("seller_id = ?", sellerID).Limit(limit).Offset(offset).Order("created_at DESC").Find(&products).Error
 	return products, total, err
 }
+
+// FindActiveBySellerID retrieves the seller's products that are on sale
+func (r *productRepository) FindActiveBySellerID(sellerID string, limit int, offset int) ([]domain.Product, int64, error) {
+	var products []domain.Product
+	var total int64
+
+	r.db.Where("seller_id = ? AND is_active = ? AND seller_suspended = ?", sellerID, true, false).Model(&domain.Product{}).Count(&total)
+	err := r.db.Preload("Prices").Where("seller_id = ? AND is_active = ? AND seller_suspended = ?", sellerID, true, false).Limit(limit).Offset(offset).Order("created_at DESC").Find(&products).Error
+	return products, total, err
+}
+
+// SetPrices replaces a product's price lists in a single transaction
+func (r *productRepository) SetPrices(productID string, prices []domain.ProductPrice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductPrice{}).Error; err != nil {
+			return err
+		}
+		for i := range prices {
+			prices[i].ProductID = productID
+			if err := tx.Create(&prices[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Search retrieves products matching the filter, including inactive ones, newest first
+func (r *productRepository) Search(filter domain.ProductFilter, limit int, offset int) ([]domain.Product, int64, error) {
+	var products []domain.Product
+	var total int64
+
+	query := r.db.Model(&domain.Product{})
+	if filter.Query != "" {
+		pattern := domain.SearchPattern(filter.Query)
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(slug) LIKE ? ESCAPE '\' OR LOWER(sku) LIKE ? ESCAPE '\'`, pattern, pattern, pattern)
+	}
+	if filter.SellerID != "" {
+		query = query.Where("seller_id = ?", filter.SellerID)
+	}
+	if filter.Active != nil {
+		query = query.Where("is_active = ?", *filter.Active)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Prices").Order("created_at DESC").Limit(limit).Offset(offset).Find(&products).Error
+	return products, total, err
+}
+
+// SetSellerSuspended hides (or shows again) every product of a seller
+func (r *productRepository) SetSellerSuspended(sellerID string, suspended bool) error {
+	return r.db.Model(&domain.Product{}).Where("seller_id = ?", sellerID).Update("seller_suspended", suspended).Error
+}
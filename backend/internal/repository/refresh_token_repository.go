@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// errTokenAlreadyRevoked rolls back a rotation that lost a race with another exchange
+var errTokenAlreadyRevoked = errors.New("refresh token already revoked")
+
+// RefreshTokenRepository defines refresh token data operations.
+// Revoking tokens also revokes their sessions (domain.Session), in the same transaction.
+type RefreshTokenRepository interface {
+	Create(token *domain.RefreshToken) error
+	GetByHash(hash string) (*domain.RefreshToken, error)
+	Rotate(current *domain.RefreshToken, next *domain.RefreshToken) (bool, error)
+	RevokeFamily(familyID string, reason string) error
+	RevokeUser(userID string, reason string) error
+	RevokeUserExcept(userID string, keepFamilyID string, reason string) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// Create inserts a new refresh token
+func (r *refreshTokenRepository) Create(token *domain.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash retrieves a token by the SHA-256 of its value, revoked or not
+func (r *refreshTokenRepository) GetByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	result := r.db.Where("token_hash = ?", hash).First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// Rotate revokes current and inserts next in a single transaction.
+// It returns false without inserting when current was revoked concurrently,
+// so the same token can never be exchanged twice.
+func (r *refreshTokenRepository) Rotate(current *domain.RefreshToken, next *domain.RefreshToken) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"revoked_reason": domain.RevokedRotated,
+				"replaced_by_id": next.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTokenAlreadyRevoked
+		}
+		rotated = true
+		return nil
+	})
+	if errors.Is(err, errTokenAlreadyRevoked) {
+		return false, nil
+	}
+	return rotated, err
+}
+
+// RevokeFamily revokes the session and every active token issued from the same login
+func (r *refreshTokenRepository) RevokeFamily(familyID string, reason string) error {
+	return r.revoke(reason, "family_id = ?", "id = ?", familyID)
+}
+
+// RevokeUser revokes every active session and token of a user
+func (r *refreshTokenRepository) RevokeUser(userID string, reason string) error {
+	return r.revoke(reason, "user_id = ?", "user_id = ?", userID)
+}
+
+// RevokeUserExcept revokes every active session and token of a user outside the kept login
+func (r *refreshTokenRepository) RevokeUserExcept(userID string, keepFamilyID string, reason string) error {
+	return r.revoke(reason, "user_id = ? AND family_id <> ?", "user_id = ? AND id <> ?", userID, keepFamilyID)
+}
+
+// revoke revokes the matching refresh tokens and sessions in a single transaction
+func (r *refreshTokenRepository) revoke(reason string, tokenQuery string, sessionQuery string, args ...interface{}) error {
+	updates := map[string]interface{}{
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.RefreshToken{}).
+			Where(tokenQuery+" AND revoked_at IS NULL", args...).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&domain.Session{}).
+			Where(sessionQuery+" AND revoked_at IS NULL", args...).
+			Updates(updates).Error
+	})
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository defines login session data operations.
+// Sessions are revoked together with their refresh tokens, through RefreshTokenRepository.
+type SessionRepository interface {
+	Create(session *domain.Session) error
+	GetByID(id string) (*domain.Session, error)
+	ListActive(userID string) ([]domain.Session, error)
+	Touch(id string, at time.Time) error
+	Refreshed(id string, client domain.ClientInfo, expiresAt time.Time) error
+	Rename(id string, deviceName *string) error
+	Backfill() (int64, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create inserts a new session
+func (r *sessionRepository) Create(session *domain.Session) error {
+	return r.db.Create(session).Error
+}
+
+// GetByID retrieves a session, revoked or not
+func (r *sessionRepository) GetByID(id string) (*domain.Session, error) {
+	var session domain.Session
+	result := r.db.Where("id = ?", id).First(&session)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// ListActive retrieves the user's unrevoked, unexpired sessions, most recently seen first.
+// Impersonation sessions are left out: they are not devices of the user.
+func (r *sessionRepository) ListActive(userID string) ([]domain.Session, error) {
+	var sessions []domain.Session
+	err := r.db.
+		Where("user_id = ? AND actor_id IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// Touch records that the session was used
+func (r *sessionRepository) Touch(id string, at time.Time) error {
+	return r.db.Model(&domain.Session{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// Refreshed records a refresh: the device's current address and the new expiry
+func (r *sessionRepository) Refreshed(id string, client domain.ClientInfo, expiresAt time.Time) error {
+	return r.db.Model(&domain.Session{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"ip_address":   client.IPAddress,
+		"user_agent":   client.UserAgent,
+		"last_seen_at": time.Now(),
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+// Rename sets or clears the device name
+func (r *sessionRepository) Rename(id string, deviceName *string) error {
+	return r.db.Model(&domain.Session{}).Where("id = ?", id).Update("device_name", deviceName).Error
+}
+
+// Backfill creates the sessions of logins made before sessions existed, from the
+// active refresh token of each family. It returns how many it created.
+func (r *sessionRepository) Backfill() (int64, error) {
+	result := r.db.Exec(`INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at)
+		SELECT rt.family_id, rt.user_id, rt.user_agent, rt.ip_address, rt.created_at, rt.created_at, rt.expires_at
+		FROM refresh_tokens rt
+		WHERE rt.revoked_at IS NULL AND rt.expires_at > ?
+		AND NOT EXISTS (SELECT 1 FROM sessions s WHERE s.id = rt.family_id)`, time.Now())
+	return result.RowsAffected, result.Error
+}
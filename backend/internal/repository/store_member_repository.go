@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StoreMemberRepository defines store team member and invitation data operations
+type StoreMemberRepository interface {
+	Create(member *domain.StoreMember) error
+	GetByID(id string) (*domain.StoreMember, error)
+	GetByStoreAndEmail(storeID string, email string) (*domain.StoreMember, error)
+	GetAccepted(storeID string, userID string) (*domain.StoreMember, error)
+	ListByStore(storeID string) ([]domain.StoreMember, error)
+	CountByStore(storeID string) (int64, error)
+	ListPendingByEmail(email string) ([]domain.StoreMember, error)
+	ListAcceptedByUser(userID string) ([]domain.StoreMember, error)
+	Update(member *domain.StoreMember) error
+	Delete(id string) error
+}
+
+type storeMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreMemberRepository creates a new store member repository
+func NewStoreMemberRepository(db *gorm.DB) StoreMemberRepository {
+	return &storeMemberRepository{db: db}
+}
+
+// Create inserts a new invitation
+func (r *storeMemberRepository) Create(member *domain.StoreMember) error {
+	return r.db.Create(member).Error
+}
+
+// GetByID retrieves a member or invitation with its store
+func (r *storeMemberRepository) GetByID(id string) (*domain.StoreMember, error) {
+	return r.first(r.db.Preload("Store").Where("id = ?", id))
+}
+
+// GetByStoreAndEmail retrieves the member or invitation of an email in a store
+func (r *storeMemberRepository) GetByStoreAndEmail(storeID string, email string) (*domain.StoreMember, error) {
+	return r.first(r.db.Where("store_id = ? AND email = ?", storeID, email))
+}
+
+// GetAccepted retrieves the user's membership of a store, nil when they are not a member
+func (r *storeMemberRepository) GetAccepted(storeID string, userID string) (*domain.StoreMember, error) {
+	return r.first(r.db.Where("store_id = ? AND user_id = ? AND accepted_at IS NOT NULL", storeID, userID))
+}
+
+// ListByStore retrieves a store's members and pending invitations, oldest first
+func (r *storeMemberRepository) ListByStore(storeID string) ([]domain.StoreMember, error) {
+	var members []domain.StoreMember
+	err := r.db.Where("store_id = ?", storeID).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+// CountByStore counts a store's members and pending invitations
+func (r *storeMemberRepository) CountByStore(storeID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.StoreMember{}).Where("store_id = ?", storeID).Count(&count).Error
+	return count, err
+}
+
+// ListPendingByEmail retrieves the invitations waiting for an email, with their stores
+func (r *storeMemberRepository) ListPendingByEmail(email string) ([]domain.StoreMember, error) {
+	var members []domain.StoreMember
+	err := r.db.Preload("Store").
+		Where("email = ? AND accepted_at IS NULL", email).
+		Order("created_at DESC").
+		Find(&members).Error
+	return members, err
+}
+
+// ListAcceptedByUser retrieves the user's memberships, with their stores and sellers
+func (r *storeMemberRepository) ListAcceptedByUser(userID string) ([]domain.StoreMember, error) {
+	var members []domain.StoreMember
+	err := r.db.Preload("Store.Seller").
+		Where("user_id = ? AND accepted_at IS NOT NULL", userID).
+		Order("accepted_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// Update saves changes to a member
+func (r *storeMemberRepository) Update(member *domain.StoreMember) error {
+	return r.db.Omit("Store").Save(member).Error
+}
+
+// Delete removes a member or invitation
+func (r *storeMemberRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&domain.StoreMember{}).Error
+}
+
+func (r *storeMemberRepository) first(query *gorm.DB) (*domain.StoreMember, error) {
+	var member domain.StoreMember
+	result := query.First(&member)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &member, nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrApplicationNotPending is returned when reviewing an application that was already reviewed
+	ErrApplicationNotPending = errors.New("application is not pending")
+	// ErrApplicantNotCustomer is returned when approving an application whose applicant is no longer
+	// an active customer (promoted, suspended or erased since applying)
+	ErrApplicantNotCustomer = errors.New("applicant is no longer an active customer")
+)
+
+// StoreRepository defines seller application and store data operations
+type StoreRepository interface {
+	CreateApplication(application *domain.SellerApplication) error
+	GetApplication(id string) (*domain.SellerApplication, error)
+	GetLatestApplication(userID string) (*domain.SellerApplication, error)
+	ListApplications(status string, limit int, offset int) ([]domain.SellerApplication, int64, error)
+	ApproveApplication(application *domain.SellerApplication, store *domain.Store) error
+	RejectApplication(application *domain.SellerApplication) error
+	GetStoreBySlug(slug string) (*domain.Store, error)
+	GetStoreByID(id string) (*domain.Store, error)
+	GetStoreBySellerID(sellerID string) (*domain.Store, error)
+	SlugExists(slug string) (bool, error)
+	UpdateStore(store *domain.Store) error
+}
+
+type storeRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreRepository creates a new store repository
+func NewStoreRepository(db *gorm.DB) StoreRepository {
+	return &storeRepository{db: db}
+}
+
+// CreateApplication inserts a new seller application
+func (r *storeRepository) CreateApplication(application *domain.SellerApplication) error {
+	return r.db.Create(application).Error
+}
+
+// GetApplication retrieves an application by ID with its applicant
+func (r *storeRepository) GetApplication(id string) (*domain.SellerApplication, error) {
+	var application domain.SellerApplication
+	result := r.db.Preload("User").Where("id = ?", id).First(&application)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &application, nil
+}
+
+// GetLatestApplication retrieves the user's most recent application
+func (r *storeRepository) GetLatestApplication(userID string) (*domain.SellerApplication, error) {
+	var application domain.SellerApplication
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&application)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &application, nil
+}
+
+// ListApplications retrieves applications oldest first, optionally filtered by status
+func (r *storeRepository) ListApplications(status string, limit int, offset int) ([]domain.SellerApplication, int64, error) {
+	var applications []domain.SellerApplication
+	var total int64
+
+	query := r.db.Model(&domain.SellerApplication{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("User").Order("created_at ASC").Limit(limit).Offset(offset).Find(&applications).Error
+	return applications, total, err
+}
+
+// ApproveApplication marks the application approved, creates the store and
+// upgrades the applicant to seller in a single transaction. Only an active customer is
+// upgraded; a former seller gets their existing store back, updated from the application,
+// and store is replaced with it.
+func (r *storeRepository) ApproveApplication(application *domain.SellerApplication, store *domain.Store) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).
+			Where("id = ? AND role = ? AND is_active = ? AND erased_at IS NULL", application.UserID, domain.RoleCustomer, true).
+			Update("role", domain.RoleSeller)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrApplicantNotCustomer
+		}
+
+		var existing domain.Store
+		err := tx.Where("seller_id = ?", application.UserID).First(&existing).Error
+		switch {
+		case err == nil:
+			existing.Name = store.Name
+			existing.Description = store.Description
+			existing.LegalName = store.LegalName
+			existing.CNPJ = store.CNPJ
+			existing.BankAccount = store.BankAccount
+			existing.Address = store.Address
+			if err := tx.Save(&existing).Error; err != nil {
+				return err
+			}
+			*store = existing
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(store).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		now := time.Now()
+		result = tx.Model(&domain.SellerApplication{}).
+			Where("id = ? AND status = ?", application.ID, domain.ApplicationPending).
+			Updates(map[string]interface{}{
+				"status":      domain.ApplicationApproved,
+				"reviewed_by": application.ReviewedBy,
+				"reviewed_at": now,
+				"store_id":    store.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrApplicationNotPending
+		}
+
+		application.Status = domain.ApplicationApproved
+		application.ReviewedAt = &now
+		application.StoreID = &store.ID
+		return nil
+	})
+}
+
+// RejectApplication marks a pending application rejected with its reason
+func (r *storeRepository) RejectApplication(application *domain.SellerApplication) error {
+	now := time.Now()
+	result := r.db.Model(&domain.SellerApplication{}).
+		Where("id = ? AND status = ?", application.ID, domain.ApplicationPending).
+		Updates(map[string]interface{}{
+			"status":           domain.ApplicationRejected,
+			"reviewed_by":      application.ReviewedBy,
+			"reviewed_at":      now,
+			"rejection_reason": application.RejectionReason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrApplicationNotPending
+	}
+
+	application.Status = domain.ApplicationRejected
+	application.ReviewedAt = &now
+	return nil
+}
+
+// GetStoreBySlug retrieves a store by its public slug with its seller
+func (r *storeRepository) GetStoreBySlug(slug string) (*domain.Store, error) {
+	var store domain.Store
+	result := r.db.Preload("Seller").Where("slug = ?", slug).First(&store)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &store, nil
+}
+
+// GetStoreByID retrieves a store with its seller
+func (r *storeRepository) GetStoreByID(id string) (*domain.Store, error) {
+	var store domain.Store
+	result := r.db.Preload("Seller").Where("id = ?", id).First(&store)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &store, nil
+}
+
+// GetStoreBySellerID retrieves the seller's store
+func (r *storeRepository) GetStoreBySellerID(sellerID string) (*domain.Store, error) {
+	var store domain.Store
+	result := r.db.Where("seller_id = ?", sellerID).First(&store)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &store, nil
+}
+
+// SlugExists reports whether a store already uses slug
+func (r *storeRepository) SlugExists(slug string) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Store{}).Where("slug = ?", slug).Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateStore saves changes to an existing store
+func (r *storeRepository) UpdateStore(store *domain.Store) error {
+	return r.db.Save(store).Error
+}
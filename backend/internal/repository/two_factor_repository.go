@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository defines recovery code and role policy data operations
+type TwoFactorRepository interface {
+	ReplaceRecoveryCodes(userID string, codes []domain.RecoveryCode) error
+	ConsumeRecoveryCode(userID string, codeHash string) (bool, error)
+	CountRecoveryCodes(userID string) (int64, error)
+	DeleteRecoveryCodes(userID string) error
+	GetRolePolicy(role string) (*domain.RolePolicy, error)
+	ListRolePolicies() ([]domain.RolePolicy, error)
+	SaveRolePolicy(policy *domain.RolePolicy) error
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes and stores new ones
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID string, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeRecoveryCode marks an unused code of the user as used.
+// It returns false when the code is unknown or was already used.
+func (r *twoFactorRepository) ConsumeRecoveryCode(userID string, codeHash string) (bool, error) {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountRecoveryCodes counts the user's unused codes
+func (r *twoFactorRepository) CountRecoveryCodes(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// DeleteRecoveryCodes deletes every recovery code of the user
+func (r *twoFactorRepository) DeleteRecoveryCodes(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
+
+// GetRolePolicy retrieves the policy of a role, or nil when none was set
+func (r *twoFactorRepository) GetRolePolicy(role string) (*domain.RolePolicy, error) {
+	var policy domain.RolePolicy
+	result := r.db.Where("role = ?", role).First(&policy)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &policy, nil
+}
+
+// ListRolePolicies retrieves every policy that was set
+func (r *twoFactorRepository) ListRolePolicies() ([]domain.RolePolicy, error) {
+	var policies []domain.RolePolicy
+	err := r.db.Order("role ASC").Find(&policies).Error
+	return policies, err
+}
+
+// SaveRolePolicy creates or replaces the policy of a role
+func (r *twoFactorRepository) SaveRolePolicy(policy *domain.RolePolicy) error {
+	return r.db.Save(policy).Error
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// UserAddressRepository defines address book data operations
+type UserAddressRepository interface {
+	Create(address *domain.UserAddress) error
+	GetByID(userID string, id string) (*domain.UserAddress, error)
+	ListByUser(userID string) ([]domain.UserAddress, error)
+	CountByUser(userID string) (int64, error)
+	Update(address *domain.UserAddress) error
+	Delete(address *domain.UserAddress) error
+}
+
+type userAddressRepository struct {
+	db *gorm.DB
+}
+
+// NewUserAddressRepository creates a new address book repository
+func NewUserAddressRepository(db *gorm.DB) UserAddressRepository {
+	return &userAddressRepository{db: db}
+}
+
+// Create saves a new address, taking over the user's default flags it sets
+func (r *userAddressRepository) Create(address *domain.UserAddress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearOtherDefaults(tx, address); err != nil {
+			return err
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// GetByID retrieves an address of the given user
+func (r *userAddressRepository) GetByID(userID string, id string) (*domain.UserAddress, error) {
+	var address domain.UserAddress
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).First(&address)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &address, nil
+}
+
+// ListByUser retrieves the user's addresses, defaults first
+func (r *userAddressRepository) ListByUser(userID string) ([]domain.UserAddress, error) {
+	var addresses []domain.UserAddress
+	err := r.db.Where("user_id = ?", userID).
+		Order("is_default_shipping DESC, is_default_billing DESC, created_at DESC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+// CountByUser counts the user's addresses
+func (r *userAddressRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.UserAddress{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Update saves an address, taking over the user's default flags it sets
+func (r *userAddressRepository) Update(address *domain.UserAddress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := clearOtherDefaults(tx, address); err != nil {
+			return err
+		}
+		return tx.Save(address).Error
+	})
+}
+
+// Delete removes an address. A default it held passes to the user's newest remaining address.
+func (r *userAddressRepository) Delete(address *domain.UserAddress) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(address).Error; err != nil {
+			return err
+		}
+		if !address.IsDefaultShipping && !address.IsDefaultBilling {
+			return nil
+		}
+
+		var next domain.UserAddress
+		result := tx.Where("user_id = ?", address.UserID).Order("created_at DESC").First(&next)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		updates := map[string]interface{}{}
+		if address.IsDefaultShipping {
+			updates["is_default_shipping"] = true
+		}
+		if address.IsDefaultBilling {
+			updates["is_default_billing"] = true
+		}
+		return tx.Model(&next).Updates(updates).Error
+	})
+}
+
+// clearOtherDefaults unsets the defaults held by the user's other addresses for each default address sets
+func clearOtherDefaults(tx *gorm.DB, address *domain.UserAddress) error {
+	others := tx.Model(&domain.UserAddress{}).Where("user_id = ? AND id <> ?", address.UserID, address.ID)
+	if address.IsDefaultShipping {
+		if err := others.Session(&gorm.Session{}).Update("is_default_shipping", false).Error; err != nil {
+			return err
+		}
+	}
+	if address.IsDefaultBilling {
+		if err := others.Session(&gorm.Session{}).Update("is_default_billing", false).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ type UserRepository interface {
 	GetByID(id string) (*domain.User, error)
 	Update(user *domain.User) error
 	Delete(id string) error
+	List(filter domain.UserFilter, limit int, offset int) ([]domain.User, int64, error)
 }
 
 type userRepository struct {
@@ -67,3 +68,30 @@ func (r *userRepository) Delete(id string) error {
 	result := r.db.Delete(&domain.User{}, "id = ?", id)
 	return result.Error
 }
+
+// List retrieves users matching the filter, newest first
+func (r *userRepository) List(filter domain.UserFilter, limit int, offset int) ([]domain.User, int64, error) {
+	var users []domain.User
+	var total int64
+
+	query := r.db.Model(&domain.User{})
+	if filter.Query != "" {
+		pattern := domain.SearchPattern(filter.Query)
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case "active":
+		query = query.Where("is_active = ?", true)
+	case "suspended":
+		query = query.Where("is_active = ?", false)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&users).Error
+	return users, total, err
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"ecommerce/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserTokenRepository defines emailed token data operations
+type UserTokenRepository interface {
+	Create(token *domain.UserToken) error
+	GetByHash(purpose string, hash string) (*domain.UserToken, error)
+	Consume(id string) (bool, error)
+	InvalidateUser(userID string, purpose string) error
+	ListSince(userID string, purpose string, since time.Time) ([]domain.UserToken, error)
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository creates a new user token repository
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+// Create inserts a new token
+func (r *userTokenRepository) Create(token *domain.UserToken) error {
+	return r.db.Create(token).Error
+}
+
+// GetByHash retrieves a token of a purpose by the SHA-256 of its value, used or not
+func (r *userTokenRepository) GetByHash(purpose string, hash string) (*domain.UserToken, error) {
+	var token domain.UserToken
+	result := r.db.Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// Consume marks an unused token as used. It returns false when the token was already used,
+// so concurrent requests cannot both redeem it.
+func (r *userTokenRepository) Consume(id string) (bool, error) {
+	result := r.db.Model(&domain.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// InvalidateUser marks every outstanding token of a purpose as used
+func (r *userTokenRepository) InvalidateUser(userID string, purpose string) error {
+	return r.db.Model(&domain.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+// ListSince retrieves the tokens of a purpose issued to a user since a time, newest first
+func (r *userTokenRepository) ListSince(userID string, purpose string, since time.Time) ([]domain.UserToken, error) {
+	var tokens []domain.UserToken
+	err := r.db.
+		Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, purpose, since).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
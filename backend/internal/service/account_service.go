@@ -0,0 +1,500 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/mail"
+	"ecommerce/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTTL     = time.Hour      // How long a password reset link can be used
+	emailVerificationTTL = 48 * time.Hour // How long an email verification link can be used
+	emailChangeTTL       = 24 * time.Hour // How long the link confirming a new email address can be used
+
+	// Verification emails can be resent once a minute, at most five times an hour
+	verificationResendInterval = time.Minute
+	verificationMaxPerHour     = 5
+)
+
+var (
+	// ErrInvalidResetToken is returned for unknown, expired or already used reset tokens
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	// ErrInvalidVerificationToken is returned for unknown, expired or already used verification tokens
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	// ErrInvalidEmailChangeToken is returned for unknown, expired, used or superseded email change tokens
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+	// ErrEmailAlreadyVerified is returned when resending a verification email that is no longer needed
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	// ErrWrongPassword is returned when the current password given to confirm a change is wrong
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrEmailInUse is returned when changing to an email address that belongs to another account
+	ErrEmailInUse = errors.New("email already in use")
+)
+
+// ThrottledError is returned when an email was requested too often
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many requests, try again in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds is RetryAfter rounded up, for the Retry-After header
+func (e *ThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// AccountService defines profile, account recovery and email verification operations
+type AccountService interface {
+	ForgotPassword(email string) error
+	ResetPassword(token string, password string) error
+	SendVerification(userID string) error
+	VerifyEmail(token string) (*domain.User, error)
+	UpdateProfile(userID string, req *domain.UpdateProfileRequest, client domain.ClientInfo) (*domain.User, error)
+	ChangePassword(userID string, sessionID string, req *domain.ChangePasswordRequest, client domain.ClientInfo) error
+	ChangeEmail(userID string, req *domain.ChangeEmailRequest, client domain.ClientInfo) (*domain.User, error)
+	ConfirmEmailChange(token string, client domain.ClientInfo) (*domain.User, error)
+}
+
+type accountService struct {
+	userRepo         repository.UserRepository
+	userTokenRepo    repository.UserTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditRepository
+	mailer           mail.Mailer
+	frontendURL      string
+}
+
+// NewAccountService creates a new account service.
+// Links in emails point to frontendURL (e.g. http://localhost:5173).
+func NewAccountService(userRepo repository.UserRepository, userTokenRepo repository.UserTokenRepository, refreshTokenRepo repository.RefreshTokenRepository, auditRepo repository.AuditRepository, mailer mail.Mailer, frontendURL string) AccountService {
+	return &accountService{
+		userRepo:         userRepo,
+		userTokenRepo:    userTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+		mailer:           mailer,
+		frontendURL:      strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// ForgotPassword emails a reset link when the address belongs to an active account.
+// It succeeds either way so callers cannot learn which accounts exist.
+func (s *accountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive {
+		return nil
+	}
+
+	// Only the latest link works
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	value, err := s.issueToken(user.ID, domain.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/reset-password?token=%s", s.frontendURL, url.QueryEscape(value))
+	s.sendAsync(mail.Message{
+		To:      user.Email,
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Recebemos um pedido para redefinir a senha da sua conta. Para escolher uma nova senha, acesse:\n\n%s\n\n"+
+			"O link expira em 1 hora e só pode ser usado uma vez. Se você não fez este pedido, ignore este email.\n",
+			user.Name, link),
+	})
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and logs out every session
+func (s *accountService) ResetPassword(value string, password string) error {
+	user, err := s.redeemToken(domain.TokenPurposePasswordReset, value)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeUser(user.ID, domain.RevokedPasswordReset)
+}
+
+// SendVerification emails a new verification link, replacing earlier ones
+func (s *accountService) SendVerification(userID string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	recent, err := s.userTokenRepo.ListSince(user.ID, domain.TokenPurposeEmailVerification, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(recent) > 0 {
+		if wait := recent[0].CreatedAt.Add(verificationResendInterval).Sub(now); wait > 0 {
+			return &ThrottledError{RetryAfter: wait}
+		}
+	}
+	if len(recent) >= verificationMaxPerHour {
+		oldest := recent[verificationMaxPerHour-1]
+		return &ThrottledError{RetryAfter: oldest.CreatedAt.Add(time.Hour).Sub(now)}
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+// VerifyEmail marks the email of the token's user as verified
+func (s *accountService) VerifyEmail(value string) (*domain.User, error) {
+	user, err := s.redeemToken(domain.TokenPurposeEmailVerification, value)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		user.UpdatedAt = now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// UpdateProfile changes the user's name or avatar
+func (s *accountService) UpdateProfile(userID string, req *domain.UpdateProfileRequest, client domain.ClientInfo) (*domain.User, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]string)
+	if req.Name != nil {
+		if name := strings.Join(strings.Fields(*req.Name), " "); name != user.Name {
+			if len(name) < 2 {
+				return nil, &domain.ValidationError{Fields: map[string]string{"name": "must have at least 2 characters"}}
+			}
+			changed["name"] = name
+			user.Name = name
+		}
+	}
+	if req.AvatarURL != nil {
+		avatar := strings.TrimSpace(*req.AvatarURL)
+		if avatar != "" {
+			if parsed, err := url.Parse(avatar); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+				return nil, &domain.ValidationError{Fields: map[string]string{"avatar_url": "must be an http(s) URL"}}
+			}
+		}
+		current := ""
+		if user.AvatarURL != nil {
+			current = *user.AvatarURL
+		}
+		if avatar != current {
+			changed["avatarUrl"] = avatar
+			user.AvatarURL = optionalString(avatar)
+		}
+	}
+	if len(changed) == 0 {
+		return user, nil
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	s.audit(user.ID, domain.AuditProfileUpdated, changed, client)
+	return user, nil
+}
+
+// ChangePassword sets a new password after checking the current one.
+// Every other session is logged out; sessionID (the access token's jti) stays signed in.
+func (s *accountService) ChangePassword(userID string, sessionID string, req *domain.ChangePasswordRequest, client domain.ClientInfo) error {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		return ErrWrongPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.PasswordHash = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeUserExcept(user.ID, sessionID, domain.RevokedPasswordChange); err != nil {
+		return err
+	}
+	// A reset link requested before the change must not undo it
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	s.audit(user.ID, domain.AuditPasswordChanged, nil, client)
+	s.sendAsync(mail.Message{
+		To:      user.Email,
+		Subject: "Sua senha foi alterada",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"A senha da sua conta foi alterada e as outras sessões foram encerradas.\n\n"+
+			"Se não foi você, redefina sua senha em %s/auth/forgot-password.\n",
+			user.Name, s.frontendURL),
+	})
+	return nil
+}
+
+// ChangeEmail asks to move the account to a new address after checking the password.
+// The address is kept as pending and a link is sent to it; the account keeps its current
+// email until that link is opened. Asking again replaces the pending address.
+func (s *accountService) ChangeEmail(userID string, req *domain.ChangeEmailRequest, client domain.ClientInfo) (*domain.User, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return nil, ErrWrongPassword
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(req.NewEmail))
+	if strings.EqualFold(newEmail, user.Email) {
+		return user, nil
+	}
+	existing, err := s.userRepo.GetByEmail(newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailInUse
+	}
+
+	user.PendingEmail = &newEmail
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	// Only the link for the latest address works
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposeEmailChange); err != nil {
+		return nil, err
+	}
+	value, err := s.issueToken(user.ID, domain.TokenPurposeEmailChange, emailChangeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(user.ID, domain.AuditEmailChangeRequested, map[string]string{"to": newEmail}, client)
+	link := fmt.Sprintf("%s/auth/confirm-email-change?token=%s", s.frontendURL, url.QueryEscape(value))
+	s.sendAsync(mail.Message{
+		To:      newEmail,
+		Subject: "Confirme seu novo email",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Para usar este endereço na sua conta, acesse:\n\n%s\n\n"+
+			"O link expira em 24 horas. Se você não pediu esta alteração, ignore este email.\n",
+			user.Name, link),
+	})
+	s.sendAsync(mail.Message{
+		To:      user.Email,
+		Subject: "Pedido de alteração de email",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Foi pedida a alteração do email da sua conta para %s. Ela só vale depois de confirmada pelo novo endereço.\n\n"+
+			"Se não foi você, altere sua senha e entre em contato com o suporte.\n",
+			user.Name, newEmail),
+	})
+	return user, nil
+}
+
+// ConfirmEmailChange switches the account to its pending address with the link sent to that address.
+// Opening the link proves the mailbox, so the new address is verified.
+func (s *accountService) ConfirmEmailChange(value string, client domain.ClientInfo) (*domain.User, error) {
+	user, err := s.redeemToken(domain.TokenPurposeEmailChange, value)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.PendingEmail == nil {
+		return nil, ErrInvalidEmailChangeToken
+	}
+
+	newEmail := *user.PendingEmail
+	existing, err := s.userRepo.GetByEmail(newEmail)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != user.ID {
+		return nil, ErrEmailInUse
+	}
+
+	oldEmail := user.Email
+	now := time.Now()
+	user.Email = newEmail
+	user.EmailVerifiedAt = &now
+	user.PendingEmail = nil
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	// Reset and verification links went to the old address
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return nil, err
+	}
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return nil, err
+	}
+
+	s.audit(user.ID, domain.AuditEmailChanged, map[string]string{"from": oldEmail, "to": newEmail}, client)
+	s.sendAsync(mail.Message{
+		To:      oldEmail,
+		Subject: "Seu email foi alterado",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"O email da sua conta foi alterado para %s.\n\n"+
+			"Se não foi você, entre em contato com o suporte imediatamente.\n",
+			user.Name, newEmail),
+	})
+	return user, nil
+}
+
+// sendVerificationEmail emails a verification link to the user's current address, replacing earlier links
+func (s *accountService) sendVerificationEmail(user *domain.User) error {
+	if err := s.userTokenRepo.InvalidateUser(user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+	value, err := s.issueToken(user.ID, domain.TokenPurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/auth/verify-email?token=%s", s.frontendURL, url.QueryEscape(value))
+	s.sendAsync(mail.Message{
+		To:      user.Email,
+		Subject: "Confirme seu email",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Confirme seu endereço de email para concluir o cadastro:\n\n%s\n\n"+
+			"O link expira em 48 horas. Se você não criou uma conta, ignore este email.\n",
+			user.Name, link),
+	})
+	return nil
+}
+
+// activeUser loads a user that may still change their account
+func (s *accountService) activeUser(userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
+	return user, nil
+}
+
+// audit records an account change made by its owner
+func (s *accountService) audit(userID string, action string, details map[string]string, client domain.ClientInfo) {
+	recordAudit(s.auditRepo, userID, userID, action, details, client)
+}
+
+// recordAudit appends an entry to the audit log. Failures are only logged:
+// the change itself already happened.
+func recordAudit(auditRepo repository.AuditRepository, userID string, actorID string, action string, details map[string]string, client domain.ClientInfo) {
+	entry := &domain.AuditLog{
+		UserID:    userID,
+		ActorID:   actorID,
+		Action:    action,
+		Details:   details,
+		IPAddress: client.IPAddress,
+		UserAgent: truncate(client.UserAgent, 255),
+	}
+	if err := auditRepo.Create(entry); err != nil {
+		log.Printf("failed to audit %s for user %s: %v", action, userID, err)
+	}
+}
+
+// issueToken stores a new emailed token and returns its value
+func (s *accountService) issueToken(userID string, purpose string, ttl time.Duration) (string, error) {
+	value, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	token := &domain.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(value),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.userTokenRepo.Create(token); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// redeemToken consumes a token and returns its user, or nil when the token cannot be used
+func (s *accountService) redeemToken(purpose string, value string) (*domain.User, error) {
+	token, err := s.userTokenRepo.GetByHash(purpose, hashToken(value))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.UsedAt != nil || !time.Now().Before(token.ExpiresAt) {
+		return nil, nil
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, nil
+	}
+
+	consumed, err := s.userTokenRepo.Consume(token.ID)
+	if err != nil || !consumed {
+		return nil, err
+	}
+	return user, nil
+}
+
+// sendAsync delivers mail in the background, so responses take as long whether or not a message was sent
+func (s *accountService) sendAsync(msg mail.Message) {
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("failed to send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
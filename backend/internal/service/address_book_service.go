@@ -0,0 +1,130 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrAddressNotFound is returned when an address book entry does not exist or belongs to someone else
+	ErrAddressNotFound = errors.New("address not found")
+	// ErrAddressBookFull is returned when adding an address beyond domain.MaxUserAddresses
+	ErrAddressBookFull = fmt.Errorf("an address book holds at most %d addresses", domain.MaxUserAddresses)
+)
+
+// AddressBookService defines operations on a customer's saved addresses
+type AddressBookService interface {
+	List(userID string) ([]domain.UserAddress, error)
+	Get(userID string, id string) (*domain.UserAddress, error)
+	Create(userID string, req *domain.UserAddressRequest) (*domain.UserAddress, error)
+	Update(userID string, id string, req *domain.UserAddressRequest) (*domain.UserAddress, error)
+	Delete(userID string, id string) error
+}
+
+type addressBookService struct {
+	addressRepo   repository.UserAddressRepository
+	addressLookup AddressLookup // optional, addresses are validated as typed without it
+}
+
+// NewAddressBookService creates a new address book service
+func NewAddressBookService(addressRepo repository.UserAddressRepository, addressLookup AddressLookup) AddressBookService {
+	return &addressBookService{
+		addressRepo:   addressRepo,
+		addressLookup: addressLookup,
+	}
+}
+
+// List returns the user's addresses, defaults first
+func (s *addressBookService) List(userID string) ([]domain.UserAddress, error) {
+	addresses, err := s.addressRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if addresses == nil {
+		addresses = []domain.UserAddress{}
+	}
+	return addresses, nil
+}
+
+// Get returns one of the user's addresses
+func (s *addressBookService) Get(userID string, id string) (*domain.UserAddress, error) {
+	address, err := s.addressRepo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// Create adds an address. The user's first address becomes the default for shipping and billing.
+func (s *addressBookService) Create(userID string, req *domain.UserAddressRequest) (*domain.UserAddress, error) {
+	count, err := s.addressRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxUserAddresses {
+		return nil, ErrAddressBookFull
+	}
+
+	address := &domain.UserAddress{UserID: userID}
+	if err := s.apply(address, req); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		address.IsDefaultShipping = true
+		address.IsDefaultBilling = true
+	}
+
+	if err := s.addressRepo.Create(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Update replaces one of the user's addresses.
+// Orders keep their own copy of the address, so editing it does not change past orders.
+func (s *addressBookService) Update(userID string, id string, req *domain.UserAddressRequest) (*domain.UserAddress, error) {
+	address, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(address, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.addressRepo.Update(address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Delete removes one of the user's addresses
+func (s *addressBookService) Delete(userID string, id string) error {
+	address, err := s.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	return s.addressRepo.Delete(address)
+}
+
+// apply validates a request and copies it onto address
+func (s *addressBookService) apply(address *domain.UserAddress, req *domain.UserAddressRequest) error {
+	fields := req.Address
+	problems := domain.NewValidationError()
+	problems.Merge("address", normalizeShippingAddress(s.addressLookup, &fields))
+	if err := problems.OrNil(); err != nil {
+		return err
+	}
+
+	address.Label = strings.Join(strings.Fields(req.Label), " ")
+	address.Address = fields
+	// A default is only moved, by setting it on another address, so it never goes missing
+	address.IsDefaultShipping = address.IsDefaultShipping || req.IsDefaultShipping
+	address.IsDefaultBilling = address.IsDefaultBilling || req.IsDefaultBilling
+	return nil
+}
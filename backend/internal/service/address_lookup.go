@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"ecommerce/internal/domain"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrPostalCodeNotFound is returned by an AddressLookup that has no entry for a CEP
+var ErrPostalCodeNotFound = errors.New("postal code not found")
+
+// AddressLookup resolves street, neighborhood, city and state from a CEP
+type AddressLookup interface {
+	Lookup(postalCode string) (*domain.ShippingAddress, error)
+}
+
+//go:embed data/ceps.json
+var bundledCEPs []byte
+
+// cepEntry is one row of a local CEP dataset
+type cepEntry struct {
+	CEP          string `json:"cep"`
+	Street       string `json:"street"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	CityCode     string `json:"ibge"`
+}
+
+type localAddressLookup struct {
+	entries map[string]cepEntry
+}
+
+// NewBundledAddressLookup creates a lookup backed by the CEP dataset shipped with the binary
+func NewBundledAddressLookup() (AddressLookup, error) {
+	return NewLocalAddressLookup(bytes.NewReader(bundledCEPs))
+}
+
+// NewLocalAddressLookup creates a lookup from a JSON array of CEP entries
+// (same shape as data/ceps.json), e.g. an export of the Correios DNE
+func NewLocalAddressLookup(r io.Reader) (AddressLookup, error) {
+	var rows []cepEntry
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("invalid CEP dataset: %w", err)
+	}
+
+	entries := make(map[string]cepEntry, len(rows))
+	for _, row := range rows {
+		cep := domain.NormalizePostalCode(row.CEP)
+		if cep == "" {
+			continue
+		}
+		entries[cep] = row
+	}
+	return &localAddressLookup{entries: entries}, nil
+}
+
+// Lookup finds a CEP in the local dataset
+func (l *localAddressLookup) Lookup(postalCode string) (*domain.ShippingAddress, error) {
+	cep := domain.NormalizePostalCode(postalCode)
+	entry, ok := l.entries[cep]
+	if cep == "" || !ok {
+		return nil, ErrPostalCodeNotFound
+	}
+
+	return &domain.ShippingAddress{
+		Street:       entry.Street,
+		Neighborhood: entry.Neighborhood,
+		City:         entry.City,
+		CityCode:     entry.CityCode,
+		State:        strings.ToUpper(entry.State),
+		PostalCode:   domain.FormatPostalCode(cep),
+		Country:      domain.DefaultCountry,
+	}, nil
+}
+
+type httpAddressLookup struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPAddressLookup creates a lookup against a ViaCEP-compatible API
+// (GET {baseURL}/{cep}/json/)
+func NewHTTPAddressLookup(baseURL string, timeout time.Duration) AddressLookup {
+	return &httpAddressLookup{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// viaCEPResponse mirrors the ViaCEP payload
+type viaCEPResponse struct {
+	CEP        string `json:"cep"`
+	Logradouro string `json:"logradouro"`
+	Bairro     string `json:"bairro"`
+	Localidade string `json:"localidade"`
+	UF         string `json:"uf"`
+	IBGE       string `json:"ibge"`
+	Erro       any    `json:"erro"` // bool or "true" depending on API version
+}
+
+// Lookup queries the HTTP provider for a CEP
+func (l *httpAddressLookup) Lookup(postalCode string) (*domain.ShippingAddress, error) {
+	cep := domain.NormalizePostalCode(postalCode)
+	if cep == "" {
+		return nil, ErrPostalCodeNotFound
+	}
+
+	resp, err := l.client.Get(l.baseURL + "/" + cep + "/json/")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrPostalCodeNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CEP provider returned status %d", resp.StatusCode)
+	}
+
+	var body viaCEPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if body.Erro != nil && body.Erro != false && body.Erro != "false" {
+		return nil, ErrPostalCodeNotFound
+	}
+
+	return &domain.ShippingAddress{
+		Street:       body.Logradouro,
+		Neighborhood: body.Bairro,
+		City:         body.Localidade,
+		CityCode:     body.IBGE,
+		State:        strings.ToUpper(body.UF),
+		PostalCode:   domain.FormatPostalCode(cep),
+		Country:      domain.DefaultCountry,
+	}, nil
+}
+
+// normalizeShippingAddress canonicalizes an address, fills blanks from the lookup
+// (when one is configured) and validates the result.
+// Lookup failures are not fatal: the address is still validated as typed.
+func normalizeShippingAddress(lookup AddressLookup, addr *domain.ShippingAddress) *domain.ValidationError {
+	addr.Normalize()
+
+	v := domain.NewValidationError()
+	if lookup != nil && addr.IsDomestic() && domain.NormalizePostalCode(addr.PostalCode) != "" {
+		found, err := lookup.Lookup(addr.PostalCode)
+		switch {
+		case err == nil:
+			if addr.State != "" && found.State != "" && addr.State != found.State {
+				v.Add("state", "does not match the postal code ("+found.State+")")
+			}
+			fillBlank(&addr.Street, found.Street)
+			fillBlank(&addr.Neighborhood, found.Neighborhood)
+			fillBlank(&addr.City, found.City)
+			if strings.EqualFold(addr.City, found.City) {
+				fillBlank(&addr.CityCode, found.CityCode)
+			}
+			fillBlank(&addr.State, found.State)
+		case errors.Is(err, ErrPostalCodeNotFound):
+			// Datasets are never complete; fall back to the typed address
+		default:
+			log.Printf("address lookup failed for %s: %v", addr.PostalCode, err)
+		}
+	}
+
+	v.Merge("", addr.Validate())
+	return v
+}
+
+// fillBlank sets *dst to value when dst is empty
+func fillBlank(dst *string, value string) {
+	if *dst == "" {
+		*dst = value
+	}
+}
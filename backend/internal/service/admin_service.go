@@ -0,0 +1,341 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrOrderNotFound is returned when an order does not exist
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrInvalidRole is returned for roles other than the domain.Role* constants
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrCannotModifySelf is returned when an admin suspends or changes the role of their own account
+	ErrCannotModifySelf = errors.New("admins cannot suspend or change the role of their own account")
+	// ErrAccountErased is returned when reactivating or changing the role of an anonymized account
+	ErrAccountErased = errors.New("account was erased at its owner's request")
+	// ErrNoStore is returned when making a user without a store a seller; they must apply to sell instead
+	ErrNoStore = errors.New("user has no store, approve a seller application instead")
+)
+
+// AdminService defines platform administration operations
+type AdminService interface {
+	ListUsers(filter domain.UserFilter, page int, perPage int) (interface{}, error)
+	GetUser(userID string) (*domain.User, error)
+	SuspendUser(adminID string, userID string, reason string, client domain.ClientInfo) (*domain.User, error)
+	ReactivateUser(adminID string, userID string, client domain.ClientInfo) (*domain.User, error)
+	ChangeRole(adminID string, userID string, role string, client domain.ClientInfo) (*domain.User, error)
+	ListAuditLog(userID string, page int, perPage int) (interface{}, error)
+	ListProducts(filter domain.ProductFilter, page int, perPage int) (interface{}, error)
+	ModerateProduct(productID string, req *domain.ModerateProductRequest) (*domain.Product, error)
+	DeleteProduct(productID string) error
+	ListOrders(filter domain.OrderFilter, page int, perPage int) (interface{}, error)
+	GetOrder(orderID string) (*domain.OrderResponse, error)
+	UpdateOrder(orderID string, req *domain.AdminUpdateOrderRequest) (*domain.OrderResponse, error)
+	Metrics() (*domain.PlatformMetrics, error)
+}
+
+type adminService struct {
+	userRepo         repository.UserRepository
+	productRepo      repository.ProductRepository
+	orderRepo        repository.OrderRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	metricsRepo      repository.MetricsRepository
+	auditRepo        repository.AuditRepository
+	storeRepo        repository.StoreRepository
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(userRepo repository.UserRepository, productRepo repository.ProductRepository, orderRepo repository.OrderRepository, refreshTokenRepo repository.RefreshTokenRepository, metricsRepo repository.MetricsRepository, auditRepo repository.AuditRepository, storeRepo repository.StoreRepository) AdminService {
+	return &adminService{
+		userRepo:         userRepo,
+		productRepo:      productRepo,
+		orderRepo:        orderRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		metricsRepo:      metricsRepo,
+		auditRepo:        auditRepo,
+		storeRepo:        storeRepo,
+	}
+}
+
+// ListUsers returns a page of users matching the filter
+func (s *adminService) ListUsers(filter domain.UserFilter, page int, perPage int) (interface{}, error) {
+	page, perPage = normalizePage(page, perPage)
+	users, total, err := s.userRepo.List(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(users, total, page, perPage), nil
+}
+
+// GetUser retrieves any user by ID
+func (s *adminService) GetUser(userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// SuspendUser blocks the account, signs it out everywhere and hides its products.
+// Access tokens already issued stop working because every request reloads the user.
+func (s *adminService) SuspendUser(adminID string, userID string, reason string, client domain.ClientInfo) (*domain.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reason = strings.TrimSpace(reason)
+	user.IsActive = false
+	user.SuspendedAt = &now
+	user.SuspensionReason = &reason
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.refreshTokenRepo.RevokeUser(user.ID, domain.RevokedSuspended); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.SetSellerSuspended(user.ID, true); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, user.ID, adminID, domain.AuditUserSuspended, map[string]string{"reason": reason}, client)
+	return user, nil
+}
+
+// ReactivateUser lifts a suspension; products the seller had on sale are listed again
+func (s *adminService) ReactivateUser(adminID string, userID string, client domain.ClientInfo) (*domain.User, error) {
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ErasedAt != nil {
+		return nil, ErrAccountErased
+	}
+
+	user.IsActive = true
+	user.SuspendedAt = nil
+	user.SuspensionReason = nil
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	if err := s.productRepo.SetSellerSuspended(user.ID, false); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, user.ID, adminID, domain.AuditUserReactivated, nil, client)
+	return user, nil
+}
+
+// ChangeRole sets the user's role. Demoted sellers keep their products and store,
+// which they can no longer manage until they are sellers again. Only users with a
+// store can be made sellers here; others go through the seller application.
+func (s *adminService) ChangeRole(adminID string, userID string, role string, client domain.ClientInfo) (*domain.User, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !domain.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if adminID == userID {
+		return nil, ErrCannotModifySelf
+	}
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ErasedAt != nil {
+		return nil, ErrAccountErased
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+	if role == domain.RoleSeller {
+		store, err := s.storeRepo.GetStoreBySellerID(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if store == nil {
+			return nil, ErrNoStore
+		}
+	}
+
+	previous := user.Role
+	user.Role = role
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, user.ID, adminID, domain.AuditRoleChanged, map[string]string{"from": previous, "to": role}, client)
+	return user, nil
+}
+
+// ListAuditLog returns a page of the changes made to an account, newest first
+func (s *adminService) ListAuditLog(userID string, page int, perPage int) (interface{}, error) {
+	if _, err := s.GetUser(userID); err != nil {
+		return nil, err
+	}
+	page, perPage = normalizePage(page, perPage)
+	entries, total, err := s.auditRepo.ListByUser(userID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(entries, total, page, perPage), nil
+}
+
+// ListProducts returns a page of products matching the filter, including inactive ones
+func (s *adminService) ListProducts(filter domain.ProductFilter, page int, perPage int) (interface{}, error) {
+	page, perPage = normalizePage(page, perPage)
+	products, total, err := s.productRepo.Search(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(products, total, page, perPage), nil
+}
+
+// ModerateProduct takes any seller's product off sale (or back on) and sets whether it is featured
+func (s *adminService) ModerateProduct(productID string, req *domain.ModerateProductRequest) (*domain.Product, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, ErrProductNotFound
+	}
+
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+	if req.IsFeatured != nil {
+		product.IsFeatured = *req.IsFeatured
+	}
+	product.UpdatedAt = time.Now()
+	if err := s.productRepo.Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// DeleteProduct removes any seller's product
+func (s *adminService) DeleteProduct(productID string) error {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return ErrProductNotFound
+	}
+	return s.productRepo.Delete(product.ID)
+}
+
+// ListOrders returns a page of orders matching the filter
+func (s *adminService) ListOrders(filter domain.OrderFilter, page int, perPage int) (interface{}, error) {
+	page, perPage = normalizePage(page, perPage)
+	orders, total, err := s.orderRepo.List(filter, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.OrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = *order.ToResponse()
+	}
+	return pageOf(responses, total, page, perPage), nil
+}
+
+// GetOrder retrieves any order by ID
+func (s *adminService) GetOrder(orderID string) (*domain.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	return order.ToResponse(), nil
+}
+
+// UpdateOrder changes the status, tracking number or notes of any order
+func (s *adminService) UpdateOrder(orderID string, req *domain.AdminUpdateOrderRequest) (*domain.OrderResponse, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+
+	if req.Status != nil {
+		status := strings.ToLower(strings.TrimSpace(*req.Status))
+		if !domain.IsValidOrderStatus(status) {
+			return nil, ErrInvalidOrderStatus
+		}
+		order.Status = status
+	}
+	if req.TrackingNumber != nil {
+		order.TrackingNumber = optionalString(*req.TrackingNumber)
+	}
+	if req.Notes != nil {
+		order.Notes = optionalString(*req.Notes)
+	}
+	order.UpdatedAt = time.Now()
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+	return order.ToResponse(), nil
+}
+
+// Metrics returns platform-wide counts for the last 30 days and all time
+func (s *adminService) Metrics() (*domain.PlatformMetrics, error) {
+	return s.metricsRepo.PlatformMetrics(time.Now().AddDate(0, 0, -30))
+}
+
+// normalizePage applies the default page size of 20 and caps it at 100
+func normalizePage(page int, perPage int) (int, int) {
+	if perPage <= 0 {
+		perPage = 20
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	if page <= 0 {
+		page = 1
+	}
+	return page, perPage
+}
+
+// pageOf wraps a page of items in the pagination envelope used by the list endpoints
+func pageOf(items interface{}, total int64, page int, perPage int) map[string]interface{} {
+	return map[string]interface{}{
+		"items": items,
+		"pagination": map[string]int{
+			"page":        page,
+			"per_page":    perPage,
+			"total":       int(total),
+			"total_pages": (int(total) + perPage - 1) / perPage,
+		},
+	}
+}
+
+// optionalString trims s, returning nil when it is empty
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
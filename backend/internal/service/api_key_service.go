@@ -0,0 +1,201 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// apiKeyTouchInterval limits how often a key's last use is written, so busy integrations
+// do not cost a write per request
+const apiKeyTouchInterval = time.Minute
+
+var (
+	// ErrAPIKeyNotFound is returned when the key does not exist or belongs to another user
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyLimitReached is returned when the user already has MaxAPIKeysPerUser active keys
+	ErrAPIKeyLimitReached = fmt.Errorf("at most %d active API keys are allowed, revoke one first", domain.MaxAPIKeysPerUser)
+	// ErrInvalidAPIKey is returned for malformed, unknown, revoked or expired keys
+	ErrInvalidAPIKey = errors.New("invalid, expired or revoked API key")
+)
+
+// APIKeyService defines seller API key operations
+type APIKeyService interface {
+	List(userID string) ([]domain.APIKey, error)
+	Create(user *domain.User, req *domain.CreateAPIKeyRequest, client domain.ClientInfo) (*domain.CreatedAPIKey, error)
+	Revoke(userID string, id string, client domain.ClientInfo) error
+	Authenticate(key string, client domain.ClientInfo) (*domain.User, *domain.APIKey, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repository.APIKeyRepository
+	userRepo   repository.UserRepository
+	auditRepo  repository.AuditRepository
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, auditRepo repository.AuditRepository) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		userRepo:   userRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+// List retrieves the user's keys, revoked and expired ones included
+func (s *apiKeyService) List(userID string) ([]domain.APIKey, error) {
+	return s.apiKeyRepo.ListByUser(userID)
+}
+
+// Create issues a new key. Its scopes must be API key scopes the user's role grants.
+func (s *apiKeyService) Create(user *domain.User, req *domain.CreateAPIKeyRequest, client domain.ClientInfo) (*domain.CreatedAPIKey, error) {
+	now := time.Now()
+	problems := domain.NewValidationError()
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		problems.Add("name", "is required")
+	}
+	scopes := make([]domain.Permission, 0, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		switch {
+		case !domain.IsAPIKeyScope(scope):
+			problems.Add(fmt.Sprintf("scopes[%d]", i), "is not an API key scope")
+		case !user.Can(scope):
+			problems.Add(fmt.Sprintf("scopes[%d]", i), "is not granted to your account")
+		case !containsPermission(scopes, scope):
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		problems.Add("expires_at", "must be in the future")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	count, err := s.apiKeyRepo.CountActive(user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxAPIKeysPerUser {
+		return nil, ErrAPIKeyLimitReached
+	}
+
+	prefix, secret, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	value := prefix + "_" + secret
+	key := &domain.APIKey{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(value),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditAPIKeyCreated, map[string]string{"keyId": key.ID, "prefix": prefix}, client)
+	return &domain.CreatedAPIKey{APIKey: key, Key: value}, nil
+}
+
+// Revoke disables one of the user's keys for good
+func (s *apiKeyService) Revoke(userID string, id string, client domain.ClientInfo) error {
+	key, err := s.apiKeyRepo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return ErrAPIKeyNotFound
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(key.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if revoked {
+		recordAudit(s.auditRepo, userID, userID, domain.AuditAPIKeyRevoked, map[string]string{"keyId": key.ID, "prefix": key.Prefix}, client)
+	}
+	return nil
+}
+
+// Authenticate checks a key presented in an Authorization header and loads its user
+func (s *apiKeyService) Authenticate(value string, client domain.ClientInfo) (*domain.User, *domain.APIKey, error) {
+	prefix, _, ok := splitAPIKey(value)
+	if !ok {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetByPrefix(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if key == nil || subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(value))) != 1 || !key.IsActive(now) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.userRepo.GetByID(key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if !user.IsActive {
+		return nil, nil, ErrAccountSuspended
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchUsed(key.ID, now, truncate(client.IPAddress, 45)); err != nil {
+			log.Printf("failed to record use of API key %s: %v", key.ID, err)
+		}
+	}
+	return user, key, nil
+}
+
+// newAPIKey generates the public prefix ("ek_" and 12 hex characters) and the secret of a key
+func newAPIKey() (string, string, error) {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	return domain.APIKeyPrefix + hex.EncodeToString(id), base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// splitAPIKey splits "ek_<prefix>_<secret>" into its public prefix and secret
+func splitAPIKey(value string) (string, string, bool) {
+	rest, found := strings.CutPrefix(value, domain.APIKeyPrefix)
+	if !found {
+		return "", "", false
+	}
+	id, secret, found := strings.Cut(rest, "_")
+	if !found || len(id) != 12 || secret == "" {
+		return "", "", false
+	}
+	return domain.APIKeyPrefix + id, secret, true
+}
+
+// containsPermission reports whether perms includes perm
+func containsPermission(perms []domain.Permission, perm domain.Permission) bool {
+	for _, p := range perms {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -1,68 +1,219 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"ecommerce/internal/domain"
+	"ecommerce/internal/jwtkeys"
 	"ecommerce/internal/repository"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// AuthService defines authentication operations
+// Default token lifetimes, overridden by JWT_EXPIRATION and REFRESH_TOKEN_EXPIRATION
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+
+	loginChallengeTTL = 5 * time.Minute  // How long a password-checked login waits for its two-factor code
+	sessionTouchEvery = time.Minute      // How stale a session's LastSeenAt may get before a request updates it
+	impersonationTTL  = 15 * time.Minute // Longest impersonation token, shortened to the access token lifetime
+)
+
+// TokenIssuer is the iss claim of access tokens, which services verifying them should check
+const TokenIssuer = "ecommerce-api"
+
+var (
+	// ErrInvalidRefreshToken is returned for unknown, expired or logged out refresh tokens
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a rotated token is presented again; its whole family is revoked
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected, all sessions from this login were revoked")
+	// ErrAccountSuspended is returned when a suspended user signs in or uses an earlier token
+	ErrAccountSuspended = errors.New("account suspended")
+	// ErrTokenRevoked is returned for access tokens whose session was logged out
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrInvalidLoginChallenge is returned for unknown, expired or already used login challenges
+	ErrInvalidLoginChallenge = errors.New("invalid or expired login challenge, log in again")
+	// ErrInvalidCredentials is returned for a wrong password and for unknown emails alike
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrCannotImpersonate is returned when the target is the admin, another admin or a suspended account
+	ErrCannotImpersonate = errors.New("only active customer and seller accounts other than your own can be impersonated")
+)
+
+// dummyPasswordHash is compared against when the email is unknown (or the account has no
+// password), so a login takes as long whether or not the account exists
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+
+// AuthService defines authentication operations.
+// Sessions are a short-lived access token plus a rotating refresh token, tied to a
+// domain.Session whose ID is the access token's jti and the refresh token family,
+// so revoking the session revokes both.
+// Accounts with two-factor authentication get a LoginChallenge from Login (or SignIn) and
+// their session from CompleteLogin. Impersonate gives admins a session acting as a user,
+// whose token carries the admin in its act claim.
 type AuthService interface {
-	Login(email, password string) (*domain.LoginResponse, error)
-	Register(req *domain.RegisterRequest) (*domain.LoginResponse, error)
-	ValidateToken(tokenString string) (*jwt.RegisteredClaims, error)
+	Login(email, password string, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error)
+	SignIn(user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error)
+	CompleteLogin(challengeToken string, code string, client domain.ClientInfo) (*domain.LoginResponse, error)
+	Register(req *domain.RegisterRequest, client domain.ClientInfo) (*domain.LoginResponse, error)
+	Refresh(refreshToken string, client domain.ClientInfo) (*domain.LoginResponse, error)
+	Logout(claims *domain.AccessClaims) error
+	LogoutAll(userID string) error
+	ValidateToken(tokenString string) (*domain.AccessClaims, error)
+	Authenticate(tokenString string) (*domain.User, *domain.AccessClaims, error)
+	AuthenticateAPIKey(key string, client domain.ClientInfo) (*domain.User, *domain.APIKey, error)
+	Impersonate(admin *domain.User, userID string, reason string, client domain.ClientInfo) (*domain.ImpersonationResponse, error)
+	RecordImpersonatedRequest(claims *domain.AccessClaims, method string, path string, status int, client domain.ClientInfo)
 	GetUserFromToken(tokenString string) (*domain.User, error)
 }
 
 type authService struct {
-	userRepo  repository.UserRepository
-	jwtSecret string
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.SessionRepository
+	userTokenRepo    repository.UserTokenRepository
+	auditRepo        repository.AuditRepository
+	twoFactor        TwoFactorService
+	apiKeys          APIKeyService
+	guard            LoginGuard
+	keys             *jwtkeys.Ring
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, jwtSecret string) AuthService {
+func NewAuthService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, sessionRepo repository.SessionRepository, userTokenRepo repository.UserTokenRepository, auditRepo repository.AuditRepository, twoFactor TwoFactorService, apiKeys APIKeyService, guard LoginGuard, keys *jwtkeys.Ring, accessTTL time.Duration, refreshTTL time.Duration) AuthService {
 	return &authService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		userTokenRepo:    userTokenRepo,
+		auditRepo:        auditRepo,
+		twoFactor:        twoFactor,
+		apiKeys:          apiKeys,
+		guard:            guard,
+		keys:             keys,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
 	}
 }
 
-// Login authenticates a user and returns JWT token
-func (s *authService) Login(email, password string) (*domain.LoginResponse, error) {
+// Login authenticates a user and starts a new session.
+// When the account has two-factor authentication, only a challenge is returned.
+// Attempts are throttled per account and per IP; blocked ones get a *ThrottledError.
+func (s *authService) Login(email, password string, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error) {
+	if s.guard != nil {
+		if err := s.guard.CheckLogin(email, client.IPAddress); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Find user by email
 	user, err := s.userRepo.GetByEmail(email)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if user == nil {
-		return nil, errors.New("invalid credentials")
+	// Verify password, spending the bcrypt time even when there is no hash to check
+	hash := dummyPasswordHash
+	if user != nil && user.PasswordHash != "" {
+		hash = []byte(user.PasswordHash)
+	}
+	err = bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if user == nil || user.PasswordHash == "" || err != nil {
+		s.loginFailed(email, user, "password", client)
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	resp, challenge, err := s.SignIn(user, client)
+	if err == nil && challenge == nil && s.guard != nil {
+		s.guard.LoginSucceeded(email)
+	}
+	return resp, challenge, err
+}
+
+// SignIn starts a session for a user already authenticated by other means (password,
+// social login), going through the two-factor challenge like Login does
+func (s *authService) SignIn(user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error) {
+	if !user.IsActive {
+		return nil, nil, ErrAccountSuspended
 	}
 
-	// Verify password
-	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if s.twoFactor != nil && user.HasTwoFactor() {
+		challenge, err := s.newLoginChallenge(user.ID)
+		return nil, challenge, err
+	}
+
+	resp, err := s.startSession(user, client)
+	return resp, nil, err
+}
+
+// CompleteLogin starts the session of a login challenge after checking its TOTP or recovery code.
+// A challenge is used up by the first attempt, so every guess costs a password check.
+func (s *authService) CompleteLogin(challengeToken string, code string, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	if s.twoFactor == nil {
+		return nil, ErrInvalidLoginChallenge
+	}
+	challenge, err := s.userTokenRepo.GetByHash(domain.TokenPurposeLoginChallenge, hashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.UsedAt != nil || !time.Now().Before(challenge.ExpiresAt) {
+		return nil, ErrInvalidLoginChallenge
+	}
+	consumed, err := s.userTokenRepo.Consume(challenge.ID)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidLoginChallenge
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user.ID, user.Email, user.Role)
+	user, err := s.userRepo.GetByID(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, ErrInvalidLoginChallenge
+	}
+	if s.guard != nil {
+		if err := s.guard.CheckLogin(user.Email, client.IPAddress); err != nil {
+			return nil, err
+		}
+	}
+	ok, err := s.twoFactor.Verify(user, code, client)
 	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		s.loginFailed(user.Email, user, "two_factor", client)
+		return nil, ErrInvalidTwoFactorCode
+	}
 
-	return &domain.LoginResponse{
-		Token: token,
-		User:  user.ToResponse(),
-	}, nil
+	if s.guard != nil {
+		s.guard.LoginSucceeded(user.Email)
+	}
+	return s.startSession(user, client)
 }
 
-// Register creates a new user account
-func (s *authService) Register(req *domain.RegisterRequest) (*domain.LoginResponse, error) {
+// Register creates a new user account. Attempts are throttled per IP.
+func (s *authService) Register(req *domain.RegisterRequest, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	if s.guard != nil {
+		if err := s.guard.CheckRegistration(client.IPAddress); err != nil {
+			return nil, err
+		}
+		s.guard.RegistrationAttempted(client.IPAddress)
+	}
+
 	// Check if user already exists
 	existingUser, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
@@ -84,7 +235,7 @@ func (s *authService) Register(req *domain.RegisterRequest) (*domain.LoginRespon
 		Name:         req.Name,
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
-		Role:         "customer",
+		Role:         domain.RoleCustomer,
 		IsActive:     true,
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -94,65 +245,372 @@ func (s *authService) Register(req *domain.RegisterRequest) (*domain.LoginRespon
 		return nil, err
 	}
 
-	// Generate JWT token
-	token, err := s.generateToken(user.ID, user.Email, user.Role)
+	return s.startSession(user, client)
+}
+
+// Refresh exchanges a refresh token for a new session of the same family.
+// Presenting an already rotated token revokes the family: either the client or an attacker holds a stolen copy.
+func (s *authService) Refresh(refreshToken string, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	current, err := s.refreshTokenRepo.GetByHash(hashToken(refreshToken))
 	if err != nil {
 		return nil, err
 	}
+	if current == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.RevokedReason == domain.RevokedRotated {
+		if err := s.refreshTokenRepo.RevokeFamily(current.FamilyID, domain.RevokedReuse); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if !current.IsActive(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
 
-	return &domain.LoginResponse{
-		Token: token,
-		User:  user.ToResponse(),
-	}, nil
+	user, err := s.userRepo.GetByID(current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	value, next, err := s.newRefreshToken(user.ID, current.FamilyID, client)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.refreshTokenRepo.Rotate(current, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request exchanged the same token first
+		if err := s.refreshTokenRepo.RevokeFamily(current.FamilyID, domain.RevokedReuse); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+	if err := s.sessionRepo.Refreshed(current.FamilyID, domain.ClientInfo{
+		UserAgent: truncate(client.UserAgent, 255),
+		IPAddress: truncate(client.IPAddress, 45),
+	}, next.ExpiresAt); err != nil {
+		log.Printf("failed to update session %s: %v", current.FamilyID, err)
+	}
+
+	return s.sessionResponse(user, value, next)
 }
 
-// ValidateToken validates a JWT token
-func (s *authService) ValidateToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.jwtSecret), nil
-	})
+// Logout revokes the session the access token belongs to
+func (s *authService) Logout(claims *domain.AccessClaims) error {
+	return s.refreshTokenRepo.RevokeFamily(claims.ID, domain.RevokedLogout)
+}
+
+// LogoutAll revokes every session of the user
+func (s *authService) LogoutAll(userID string) error {
+	return s.refreshTokenRepo.RevokeUser(userID, domain.RevokedLogoutAll)
+}
+
+// ValidateToken validates a JWT access token and checks its session was not revoked
+func (s *authService) ValidateToken(tokenString string) (*domain.AccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &domain.AccessClaims{}, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.Algorithms()), jwt.WithIssuer(TokenIssuer))
 
 	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*domain.AccessClaims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
 
+	// Tokens without a session (issued before refresh tokens existed) cannot be revoked, so they are refused
+	if claims.ID == "" {
+		return nil, ErrTokenRevoked
+	}
+	session, err := s.sessionRepo.GetByID(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if session == nil || session.UserID != claims.Subject || !session.IsActive(now) {
+		return nil, ErrTokenRevoked
+	}
+	// An act claim is only honoured on the impersonation session it was issued for
+	actorID := ""
+	if session.ActorID != nil {
+		actorID = *session.ActorID
+	}
+	if claims.IsImpersonation() != (session.ActorID != nil) || (claims.IsImpersonation() && claims.Act.Subject != actorID) {
+		return nil, ErrTokenRevoked
+	}
+	if now.Sub(session.LastSeenAt) > sessionTouchEvery {
+		if err := s.sessionRepo.Touch(session.ID, now); err != nil {
+			log.Printf("failed to touch session %s: %v", session.ID, err)
+		}
+	}
+
 	return claims, nil
 }
 
-// GetUserFromToken retrieves user from JWT token
-func (s *authService) GetUserFromToken(tokenString string) (*domain.User, error) {
+// Authenticate validates an access token and loads its user
+func (s *authService) Authenticate(tokenString string) (*domain.User, *domain.AccessClaims, error) {
 	claims, err := s.ValidateToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// User ID is in the Subject claim
 	user, err := s.userRepo.GetByID(claims.Subject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user == nil {
+		return nil, nil, errors.New("user not found")
+	}
+	if !user.IsActive {
+		return nil, nil, ErrAccountSuspended
+	}
+
+	// Impersonation ends as soon as the admin loses the right to it
+	if claims.IsImpersonation() {
+		actor, err := s.userRepo.GetByID(claims.Act.Subject)
+		if err != nil {
+			return nil, nil, err
+		}
+		if actor == nil || !actor.IsActive || !actor.Can(domain.PermAdminImpersonate) {
+			return nil, nil, ErrTokenRevoked
+		}
+	}
+
+	return user, claims, nil
+}
+
+// Impersonate starts a session letting the admin act as the user, audited with the reason.
+// Its access token carries the admin in the act claim and cannot be refreshed.
+func (s *authService) Impersonate(admin *domain.User, userID string, reason string, client domain.ClientInfo) (*domain.ImpersonationResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.ID == admin.ID || user.Role == domain.RoleAdmin || !user.IsActive {
+		return nil, ErrCannotImpersonate
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(min(impersonationTTL, s.accessTTL))
+	session := &domain.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  truncate(client.IPAddress, 45),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+		ActorID:    &admin.ID,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	claims := s.accessClaims(user, session.ID, expiresAt)
+	claims.Act = &domain.ActorClaim{Subject: admin.ID, Email: admin.Email}
+	token, err := s.keys.Sign(claims)
 	if err != nil {
 		return nil, err
 	}
 
+	recordAudit(s.auditRepo, user.ID, admin.ID, domain.AuditImpersonationStarted, map[string]string{
+		"reason":    strings.TrimSpace(reason),
+		"sessionId": session.ID,
+	}, client)
+	return &domain.ImpersonationResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      user.ToResponse(),
+	}, nil
+}
+
+// RecordImpersonatedRequest audits a request made with an impersonation token, on the
+// impersonated account with the admin as actor
+func (s *authService) RecordImpersonatedRequest(claims *domain.AccessClaims, method string, path string, status int, client domain.ClientInfo) {
+	if !claims.IsImpersonation() {
+		return
+	}
+	recordAudit(s.auditRepo, claims.Subject, claims.Act.Subject, domain.AuditImpersonatedRequest, map[string]string{
+		"method":    method,
+		"path":      truncate(path, 255),
+		"status":    strconv.Itoa(status),
+		"sessionId": claims.ID,
+	}, client)
+}
+
+// AuthenticateAPIKey validates a seller API key and loads its user
+func (s *authService) AuthenticateAPIKey(key string, client domain.ClientInfo) (*domain.User, *domain.APIKey, error) {
+	if s.apiKeys == nil {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	return s.apiKeys.Authenticate(key, client)
+}
+
+// GetUserFromToken retrieves user from JWT token
+func (s *authService) GetUserFromToken(tokenString string) (*domain.User, error) {
+	user, _, err := s.Authenticate(tokenString)
+	return user, err
+}
+
+// loginFailed counts a failed password or two-factor code and audits it on the account,
+// when there is one
+func (s *authService) loginFailed(email string, user *domain.User, reason string, client domain.ClientInfo) {
+	locked := false
+	if s.guard != nil {
+		locked = s.guard.LoginFailed(email, client.IPAddress)
+	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		log.Printf("failed login for an unknown email from %s", client.IPAddress)
+		return
+	}
+	if s.auditRepo == nil {
+		return
+	}
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditLoginFailed, map[string]string{"reason": reason}, client)
+	if locked {
+		recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditLoginLocked, nil, client)
 	}
+}
 
-	return user, nil
+// newLoginChallenge stores a challenge for a login whose password was checked
+func (s *authService) newLoginChallenge(userID string) (*domain.LoginChallenge, error) {
+	value, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	token := &domain.UserToken{
+		UserID:    userID,
+		Purpose:   domain.TokenPurposeLoginChallenge,
+		TokenHash: hashToken(value),
+		ExpiresAt: time.Now().Add(loginChallengeTTL),
+	}
+	if err := s.userTokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+	return &domain.LoginChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    value,
+		ExpiresAt:         token.ExpiresAt,
+	}, nil
+}
+
+// startSession records a new session and issues its first refresh token and access token
+func (s *authService) startSession(user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, error) {
+	now := time.Now()
+	session := &domain.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  truncate(client.IPAddress, 45),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL),
+	}
+	if client.DeviceName != "" {
+		name := truncate(client.DeviceName, 100)
+		session.DeviceName = &name
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	value, refreshToken, err := s.newRefreshToken(user.ID, session.ID, client)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken.ExpiresAt = session.ExpiresAt
+	if err := s.refreshTokenRepo.Create(refreshToken); err != nil {
+		return nil, err
+	}
+	return s.sessionResponse(user, value, refreshToken)
 }
 
-// generateToken creates a JWT token
-func (s *authService) generateToken(userID, email, role string) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Issuer:    "ecommerce-api",
+// sessionResponse signs an access token for the refresh token's family
+func (s *authService) sessionResponse(user *domain.User, refreshValue string, refreshToken *domain.RefreshToken) (*domain.LoginResponse, error) {
+	expiresAt := time.Now().Add(s.accessTTL)
+	token, err := s.generateToken(user, refreshToken.FamilyID, expiresAt)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return &domain.LoginResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshValue,
+		RefreshExpiresAt: refreshToken.ExpiresAt,
+		User:             user.ToResponse(),
+	}, nil
+}
+
+// newRefreshToken generates a refresh token value and its (unsaved) record for a session
+func (s *authService) newRefreshToken(userID string, familyID string, client domain.ClientInfo) (string, *domain.RefreshToken, error) {
+	value, err := newOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return value, &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(value),
+		UserAgent: truncate(client.UserAgent, 255),
+		IPAddress: truncate(client.IPAddress, 45),
+		ExpiresAt: time.Now().Add(s.refreshTTL),
+	}, nil
+}
+
+// generateToken creates a JWT access token for a session, signed by the current key of the ring
+func (s *authService) generateToken(user *domain.User, familyID string, expiresAt time.Time) (string, error) {
+	return s.keys.Sign(s.accessClaims(user, familyID, expiresAt))
+}
+
+// accessClaims builds the claims of an access token for a session
+func (s *authService) accessClaims(user *domain.User, sessionID string, expiresAt time.Time) *domain.AccessClaims {
+	return &domain.AccessClaims{
+		Email: user.Email,
+		Role:  user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    TokenIssuer,
+		},
+	}
+}
+
+// newOpaqueToken generates an unguessable URL-safe token
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex SHA-256 of a token, which is how opaque tokens are stored
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/jwtkeys"
+	"ecommerce/internal/repository"
+	"ecommerce/internal/throttle"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// racingRefreshTokenRepository exchanges the token in a competing request right before every rotation
+type racingRefreshTokenRepository struct {
+	repository.RefreshTokenRepository
+}
+
+func (r *racingRefreshTokenRepository) Rotate(current *domain.RefreshToken, next *domain.RefreshToken) (bool, error) {
+	competing := *current
+	if _, err := r.RefreshTokenRepository.Rotate(&competing, &domain.RefreshToken{
+		UserID:    current.UserID,
+		FamilyID:  current.FamilyID,
+		TokenHash: hashToken("competing " + next.TokenHash),
+		ExpiresAt: next.ExpiresAt,
+	}); err != nil {
+		return false, err
+	}
+	return r.RefreshTokenRepository.Rotate(current, next)
+}
+
+type authFixture struct {
+	service  AuthService
+	db       *gorm.DB
+	userRepo repository.UserRepository
+	user     *domain.User
+}
+
+// newAuthFixture wires the service to a sqlite database holding one active customer.
+// With racing set, every rotation loses to a concurrent exchange of the same token.
+func newAuthFixture(t *testing.T, racing bool) *authFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	err = db.AutoMigrate(&domain.User{}, &domain.RefreshToken{}, &domain.Session{}, &domain.UserToken{}, &domain.AuditLog{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	keys, err := jwtkeys.Generate(time.Minute)
+	if err != nil {
+		t.Fatalf("generate signing keys: %v", err)
+	}
+
+	var refreshTokenRepo repository.RefreshTokenRepository = repository.NewRefreshTokenRepository(db)
+	if racing {
+		refreshTokenRepo = &racingRefreshTokenRepository{refreshTokenRepo}
+	}
+	f := &authFixture{db: db, userRepo: repository.NewUserRepository(db)}
+	f.service = NewAuthService(
+		f.userRepo,
+		refreshTokenRepo,
+		repository.NewSessionRepository(db),
+		repository.NewUserTokenRepository(db),
+		repository.NewAuditRepository(db),
+		nil,
+		nil,
+		NewLoginGuard(throttle.NewMemoryStore(), DefaultAccountLoginPolicy, DefaultIPLoginPolicy, DefaultRegistrationPolicy),
+		keys,
+		time.Minute,
+		time.Hour,
+	)
+
+	f.user = &domain.User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash", Role: domain.RoleCustomer, IsActive: true}
+	if err := f.userRepo.Create(f.user); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return f
+}
+
+// signIn starts a new session for the fixture's user
+func (f *authFixture) signIn(t *testing.T) *domain.LoginResponse {
+	t.Helper()
+	resp, _, err := f.service.SignIn(f.user, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	return resp
+}
+
+// refresh exchanges a token that must still be valid
+func (f *authFixture) refresh(t *testing.T, token string) *domain.LoginResponse {
+	t.Helper()
+	resp, err := f.service.Refresh(token, domain.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return resp
+}
+
+// sessionRevokedReason returns why the session was revoked, or "" while it is active
+func (f *authFixture) sessionRevokedReason(t *testing.T, sessionID string) string {
+	t.Helper()
+	var session domain.Session
+	if err := f.db.First(&session, "id = ?", sessionID).Error; err != nil {
+		t.Fatalf("load session: %v", err)
+	}
+	return session.RevokedReason
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		racing bool
+		// present sets up the session and returns the token to exchange and the valid token it should revoke, if any
+		present       func(t *testing.T, f *authFixture) (token string, other string)
+		wantErr       error
+		wantRevokedBy string
+	}{
+		{
+			name: "current token rotates",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				return f.refresh(t, f.signIn(t).RefreshToken).RefreshToken, ""
+			},
+		},
+		{
+			name: "rotated token revokes the family",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				first := f.signIn(t).RefreshToken
+				second := f.refresh(t, first).RefreshToken
+				return first, f.refresh(t, second).RefreshToken
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantRevokedBy: domain.RevokedReuse,
+		},
+		{
+			name:   "losing a concurrent exchange revokes the family",
+			racing: true,
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				return f.signIn(t).RefreshToken, ""
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantRevokedBy: domain.RevokedReuse,
+		},
+		{
+			name: "logged out token is not reuse",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				resp := f.signIn(t)
+				if err := f.service.LogoutAll(f.user.ID); err != nil {
+					t.Fatalf("LogoutAll: %v", err)
+				}
+				return resp.RefreshToken, ""
+			},
+			wantErr:       ErrInvalidRefreshToken,
+			wantRevokedBy: domain.RevokedLogoutAll,
+		},
+		{
+			name: "expired token",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				token := f.signIn(t).RefreshToken
+				f.db.Model(&domain.RefreshToken{}).Where("token_hash = ?", hashToken(token)).Update("expires_at", time.Now().Add(-time.Second))
+				return token, ""
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "suspended account",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				token := f.signIn(t).RefreshToken
+				f.db.Model(&domain.User{}).Where("id = ?", f.user.ID).Update("is_active", false)
+				return token, ""
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "unknown token",
+			present: func(t *testing.T, f *authFixture) (string, string) {
+				return "not-a-token", ""
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t, tt.racing)
+			token, other := tt.present(t, f)
+
+			resp, err := f.service.Refresh(token, domain.ClientInfo{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == token) {
+				t.Errorf("Refresh = %+v, want a new access and refresh token", resp)
+			}
+			if other != "" {
+				if _, err := f.service.Refresh(other, domain.ClientInfo{}); !errors.Is(err, ErrInvalidRefreshToken) {
+					t.Errorf("Refresh with the family's latest token: error = %v, want ErrInvalidRefreshToken", err)
+				}
+			}
+
+			stored, _ := repository.NewRefreshTokenRepository(f.db).GetByHash(hashToken(token))
+			if stored == nil {
+				return
+			}
+			if got := f.sessionRevokedReason(t, stored.FamilyID); got != tt.wantRevokedBy {
+				t.Errorf("session revoked reason = %q, want %q", got, tt.wantRevokedBy)
+			}
+		})
+	}
+}
+
+func TestRefreshReuseSparesOtherSessions(t *testing.T) {
+	f := newAuthFixture(t, false)
+	stolen := f.signIn(t).RefreshToken
+	f.refresh(t, stolen)
+	other := f.signIn(t).RefreshToken
+
+	if _, err := f.service.Refresh(stolen, domain.ClientInfo{}); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh with a rotated token: error = %v, want ErrRefreshTokenReused", err)
+	}
+	f.refresh(t, other)
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"crypto/rand"
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// guestCartTTL is how long an untouched guest cart is kept
+const guestCartTTL = 30 * 24 * time.Hour
+
+// ErrCartItemNotFound is returned when a line does not exist in the caller's cart
+var ErrCartItemNotFound = errors.New("cart item not found")
+
+// CartService defines shopping cart operations.
+// Every method takes the logged-in user's ID or, for guests, the cart token (one of them is empty),
+// and prices the returned cart in the storefront currency.
+type CartService interface {
+	GetCart(userID string, token string, currency string) (*domain.CartResponse, error)
+	AddItem(userID string, token string, currency string, req *domain.AddCartItemRequest) (*domain.CartResponse, error)
+	UpdateItem(userID string, token string, currency string, itemID string, quantity int) (*domain.CartResponse, error)
+	RemoveItem(userID string, token string, currency string, itemID string) (*domain.CartResponse, error)
+	Clear(userID string, token string, currency string) (*domain.CartResponse, error)
+	MergeGuestCart(token string, userID string) error
+}
+
+type cartService struct {
+	cartRepo    repository.CartRepository
+	productRepo repository.ProductRepository
+}
+
+// NewCartService creates a new cart service
+func NewCartService(cartRepo repository.CartRepository, productRepo repository.ProductRepository) CartService {
+	return &cartService{
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+	}
+}
+
+// GetCart returns the caller's cart re-priced against current products
+func (s *cartService) GetCart(userID string, token string, currency string) (*domain.CartResponse, error) {
+	cart, err := s.findCart(userID, token)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return emptyCart(currency), nil
+	}
+	return buildCartResponse(cart, currency), nil
+}
+
+// AddItem adds a product to the cart, creating the cart (and a guest token) when needed
+func (s *cartService) AddItem(userID string, token string, currency string, req *domain.AddCartItemRequest) (*domain.CartResponse, error) {
+	product, err := s.productRepo.FindByID(req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.New("product not found: " + req.ProductID)
+	}
+	if !product.IsAvailable() {
+		return nil, errors.New("product is not available: " + product.Name)
+	}
+	if _, ok := product.PriceIn(currency); !ok {
+		return nil, fmt.Errorf("%s is not sold in %s", product.Name, currency)
+	}
+
+	cart, err := s.findOrCreateCart(userID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &domain.CartItem{CartID: cart.ID, ProductID: product.ID, Color: req.Color, Size: req.Size}
+	for i := range cart.Items {
+		if cart.Items[i].SameVariant(product.ID, req.Color, req.Size) {
+			item = &cart.Items[i]
+			break
+		}
+	}
+
+	quantity := item.Quantity + req.Quantity
+	if quantity > product.StockQuantity {
+		return nil, fmt.Errorf("insufficient stock for product: %s (%d available)", product.Name, product.StockQuantity)
+	}
+	item.Quantity = quantity
+	item.PriceAtAdd = product.Price
+
+	if err := s.cartRepo.SaveItem(item); err != nil {
+		return nil, err
+	}
+	return s.refresh(cart, currency)
+}
+
+// UpdateItem sets the quantity of a cart line
+func (s *cartService) UpdateItem(userID string, token string, currency string, itemID string, quantity int) (*domain.CartResponse, error) {
+	cart, item, err := s.findItem(userID, token, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Product == nil || !item.Product.IsAvailable() {
+		return nil, errors.New("product is not available")
+	}
+	if quantity > item.Product.StockQuantity {
+		return nil, fmt.Errorf("insufficient stock for product: %s (%d available)", item.Product.Name, item.Product.StockQuantity)
+	}
+
+	item.Quantity = quantity
+	if err := s.cartRepo.SaveItem(item); err != nil {
+		return nil, err
+	}
+	return s.refresh(cart, currency)
+}
+
+// RemoveItem deletes a cart line
+func (s *cartService) RemoveItem(userID string, token string, currency string, itemID string) (*domain.CartResponse, error) {
+	cart, item, err := s.findItem(userID, token, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepo.DeleteItem(cart.ID, item.ID); err != nil {
+		return nil, err
+	}
+	return s.refresh(cart, currency)
+}
+
+// Clear empties the cart
+func (s *cartService) Clear(userID string, token string, currency string) (*domain.CartResponse, error) {
+	cart, err := s.findCart(userID, token)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return emptyCart(currency), nil
+	}
+
+	if err := s.cartRepo.Clear(cart.ID); err != nil {
+		return nil, err
+	}
+	return s.refresh(cart, currency)
+}
+
+// MergeGuestCart moves a guest cart into the user's cart after login or registration.
+// Matching lines have their quantities added; a missing or expired guest cart is not an error.
+func (s *cartService) MergeGuestCart(token string, userID string) error {
+	if token == "" {
+		return nil
+	}
+
+	guest, err := s.cartRepo.GetByToken(token)
+	if err != nil || guest == nil {
+		return err
+	}
+
+	userCart, err := s.findOrCreateCart(userID, "")
+	if err != nil {
+		return err
+	}
+
+	for _, guestItem := range guest.Items {
+		merged := false
+		for i := range userCart.Items {
+			if userCart.Items[i].SameVariant(guestItem.ProductID, guestItem.Color, guestItem.Size) {
+				userCart.Items[i].Quantity += guestItem.Quantity
+				userCart.Items[i].PriceAtAdd = guestItem.PriceAtAdd
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			guestItem.ID = ""
+			guestItem.Product = nil
+			userCart.Items = append(userCart.Items, guestItem)
+		}
+	}
+
+	return s.cartRepo.Merge(guest, userCart)
+}
+
+// findCart loads the user's cart, or the guest cart for the token
+func (s *cartService) findCart(userID string, token string) (*domain.Cart, error) {
+	if userID != "" {
+		return s.cartRepo.GetByUserID(userID)
+	}
+	if token != "" {
+		return s.cartRepo.GetByToken(token)
+	}
+	return nil, nil
+}
+
+// findOrCreateCart loads the caller's cart, creating it on first write
+func (s *cartService) findOrCreateCart(userID string, token string) (*domain.Cart, error) {
+	cart, err := s.findCart(userID, token)
+	if err != nil || cart != nil {
+		return cart, err
+	}
+
+	cart = &domain.Cart{}
+	if userID != "" {
+		cart.UserID = &userID
+	} else {
+		newToken, err := newCartToken()
+		if err != nil {
+			return nil, err
+		}
+		cart.Token = &newToken
+	}
+
+	if err := s.cartRepo.Create(cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// findItem loads the caller's cart and one of its lines
+func (s *cartService) findItem(userID string, token string, itemID string) (*domain.Cart, *domain.CartItem, error) {
+	cart, err := s.findCart(userID, token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cart == nil {
+		return nil, nil, ErrCartItemNotFound
+	}
+
+	for i := range cart.Items {
+		if cart.Items[i].ID == itemID {
+			return cart, &cart.Items[i], nil
+		}
+	}
+	return nil, nil, ErrCartItemNotFound
+}
+
+// refresh extends a guest cart's expiry and returns the cart as stored
+func (s *cartService) refresh(cart *domain.Cart, currency string) (*domain.CartResponse, error) {
+	if cart.UserID == nil {
+		expiresAt := time.Now().Add(guestCartTTL)
+		cart.ExpiresAt = &expiresAt
+	}
+	if err := s.cartRepo.Touch(cart); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if cart.UserID != nil {
+		cart, err = s.cartRepo.GetByUserID(*cart.UserID)
+	} else {
+		cart, err = s.cartRepo.GetByToken(*cart.Token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buildCartResponse(cart, currency), nil
+}
+
+// emptyCart is returned to callers that have no cart yet
+func emptyCart(currency string) *domain.CartResponse {
+	return &domain.CartResponse{Currency: currency, Items: []domain.CartItemResponse{}, Subtotal: domain.NewMoney(0, currency)}
+}
+
+// buildCartResponse prices each line at the current list price in currency and flags lines that cannot be checked out.
+// PriceAtAdd is kept in the product's own currency, so price changes are detected there.
+func buildCartResponse(cart *domain.Cart, currency string) *domain.CartResponse {
+	resp := &domain.CartResponse{
+		ID:        cart.ID,
+		Currency:  currency,
+		Items:     make([]domain.CartItemResponse, len(cart.Items)),
+		UpdatedAt: &cart.UpdatedAt,
+	}
+	if cart.Token != nil {
+		resp.Token = *cart.Token
+	}
+
+	subtotal := domain.NewMoney(0, currency)
+	for i, item := range cart.Items {
+		line := domain.CartItemResponse{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Color:     item.Color,
+			Size:      item.Size,
+			UnitPrice: domain.NewMoney(0, currency),
+		}
+
+		product := item.Product
+		price, sold := domain.Money{}, false
+		if product != nil {
+			price, sold = product.PriceIn(currency)
+		}
+		switch {
+		case product == nil || !product.IsAvailable():
+			line.Issue = domain.CartIssueUnavailable
+		case !sold:
+			line.Issue = domain.CartIssueNotSoldInCurrency
+		case product.StockQuantity <= 0:
+			line.Issue = domain.CartIssueOutOfStock
+		case product.StockQuantity < item.Quantity:
+			line.Issue = domain.CartIssueInsufficientStock
+		}
+
+		if product != nil {
+			line.ProductName = product.Name
+			line.AvailableQuantity = max(product.StockQuantity, 0)
+			line.PriceChanged = !product.Price.Equal(item.PriceAtAdd.In(product.Currency))
+		}
+		if sold {
+			line.UnitPrice = price
+		}
+		line.LineTotal = line.UnitPrice.Mul(int64(item.Quantity))
+		line.Available = line.Issue == ""
+
+		if line.Available {
+			subtotal = subtotal.Add(line.LineTotal)
+			resp.ItemCount += item.Quantity
+		} else {
+			resp.HasIssues = true
+		}
+		resp.Items[i] = line
+	}
+	resp.Subtotal = subtotal
+
+	return resp
+}
+
+// newCartToken generates an unguessable guest cart token
+func newCartToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
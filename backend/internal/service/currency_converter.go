@@ -0,0 +1,101 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"errors"
+	"math"
+)
+
+// ErrNoExchangeRate is returned when neither direction of a currency pair has a rate
+var ErrNoExchangeRate = errors.New("no exchange rate for currency pair")
+
+// CurrencyConverter converts amounts between storefront currencies for display
+type CurrencyConverter interface {
+	Convert(amount domain.Money, to string) (domain.Money, error)
+	Currencies() (*domain.CurrenciesResponse, error)
+}
+
+type currencyConverter struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+// NewCurrencyConverter creates a converter backed by the exchange rate table
+func NewCurrencyConverter(rateRepo repository.ExchangeRateRepository) CurrencyConverter {
+	return &currencyConverter{rateRepo: rateRepo}
+}
+
+// Convert converts with the base->quote rate, or the inverse of quote->base when only that one is stored
+func (c *currencyConverter) Convert(amount domain.Money, to string) (domain.Money, error) {
+	if amount.Currency == to || amount.Currency == "" {
+		return amount.In(to), nil
+	}
+
+	rate, err := c.rateRepo.Get(amount.Currency, to)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	// Rates are applied with 6 decimals (see Money.Convert): smaller ones count as missing
+	if rate != nil && math.Round(rate.Rate*1e6) > 0 {
+		return amount.Convert(to, rate.Rate), nil
+	}
+
+	inverse, err := c.rateRepo.Get(to, amount.Currency)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	if inverse != nil && math.Round(inverse.Rate*1e6) > 0 {
+		converted := amount.MulRatio(1e6, int64(math.Round(inverse.Rate*1e6)))
+		return converted.In(to), nil
+	}
+	return domain.Money{}, ErrNoExchangeRate
+}
+
+// Currencies lists the supported currencies with the rate from DefaultCurrency to each of them
+func (c *currencyConverter) Currencies() (*domain.CurrenciesResponse, error) {
+	resp := &domain.CurrenciesResponse{
+		Default:   domain.DefaultCurrency,
+		Supported: domain.SupportedCurrencies(),
+		Rates:     map[string]float64{domain.DefaultCurrency: 1},
+	}
+
+	// One major unit converted, so inverse rates are reported too
+	unit := domain.NewMoney(1e6, domain.DefaultCurrency)
+	for _, currency := range resp.Supported {
+		if currency == domain.DefaultCurrency {
+			continue
+		}
+		converted, err := c.Convert(unit, currency)
+		if errors.Is(err, ErrNoExchangeRate) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp.Rates[currency] = float64(converted.Amount) / 1e6
+	}
+	return resp, nil
+}
+
+// applyDisplayPrice sets the product's storefront price in a currency: its list price when it
+// has one, a conversion otherwise. Without either DisplayPrice is left nil.
+func applyDisplayPrice(converter CurrencyConverter, product *domain.Product, currency string) error {
+	if price, ok := product.PriceIn(currency); ok {
+		product.DisplayPrice = &price
+		return nil
+	}
+	if converter == nil {
+		return nil
+	}
+
+	converted, err := converter.Convert(product.Price, currency)
+	if errors.Is(err, ErrNoExchangeRate) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	product.DisplayPrice = &converted
+	product.PriceConverted = true
+	return nil
+}
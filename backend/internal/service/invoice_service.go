@@ -0,0 +1,272 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/nfe"
+	"ecommerce/internal/repository"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrInvoiceExists is returned when issuing an invoice for an order that already has an authorized or pending one
+var ErrInvoiceExists = errors.New("an invoice for this order is already authorized or being issued")
+
+// InvoiceService defines NF-e issuing operations for sellers
+type InvoiceService interface {
+	GetFiscalProfile(sellerID string) (*domain.FiscalProfile, error)
+	SaveFiscalProfile(sellerID string, req *domain.FiscalProfileRequest) (*domain.FiscalProfile, error)
+	IssueInvoice(sellerID string, orderID string) (*domain.Invoice, error)
+	GetInvoice(sellerID string, orderID string) (*domain.Invoice, error)
+	RenderDANFE(sellerID string, orderID string, w io.Writer) error
+}
+
+type invoiceService struct {
+	invoiceRepo repository.InvoiceRepository
+	orderRepo   repository.OrderRepository
+	signer      nfe.Signer // optional, unsigned invoices are only accepted by the local stub
+	transmitter nfe.Transmitter
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, orderRepo repository.OrderRepository, signer nfe.Signer, transmitter nfe.Transmitter) InvoiceService {
+	return &invoiceService{
+		invoiceRepo: invoiceRepo,
+		orderRepo:   orderRepo,
+		signer:      signer,
+		transmitter: transmitter,
+	}
+}
+
+// GetFiscalProfile retrieves the seller's fiscal data
+func (s *invoiceService) GetFiscalProfile(sellerID string) (*domain.FiscalProfile, error) {
+	profile, err := s.invoiceRepo.GetFiscalProfile(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.New("fiscal profile not found")
+	}
+	return profile, nil
+}
+
+// SaveFiscalProfile validates and stores the seller's fiscal data
+func (s *invoiceService) SaveFiscalProfile(sellerID string, req *domain.FiscalProfileRequest) (*domain.FiscalProfile, error) {
+	v := domain.NewValidationError()
+	if !domain.IsValidCNPJ(req.CNPJ) {
+		v.Add("cnpj", "must be a valid CNPJ")
+	}
+	state := strings.ToUpper(req.State)
+	if !domain.IsValidState(state) {
+		v.Add("state", "must be a valid UF (e.g. SP, RJ, MG)")
+	}
+	postalCode := domain.NormalizePostalCode(req.PostalCode)
+	if postalCode == "" {
+		v.Add("postal_code", "must be a valid 8-digit CEP")
+	}
+	if err := v.OrNil(); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.invoiceRepo.GetFiscalProfile(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		profile = &domain.FiscalProfile{SellerID: sellerID, NextNumber: 1}
+	}
+
+	profile.CNPJ = domain.OnlyDigits(req.CNPJ)
+	profile.StateRegistration = req.StateRegistration
+	profile.LegalName = req.LegalName
+	profile.TradeName = req.TradeName
+	profile.TaxRegime = req.TaxRegime
+	profile.Street = req.Street
+	profile.Number = req.Number
+	profile.Complement = req.Complement
+	profile.Neighborhood = req.Neighborhood
+	profile.City = req.City
+	profile.CityCode = req.CityCode
+	profile.State = state
+	profile.PostalCode = postalCode
+	profile.Phone = domain.OnlyDigits(req.Phone)
+	profile.Series = req.Series
+	profile.Environment = req.Environment
+	if profile.Environment == 0 {
+		profile.Environment = domain.NFeEnvironmentHomologation
+	}
+
+	if err := s.invoiceRepo.SaveFiscalProfile(profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// IssueInvoice generates, signs and transmits the NF-e for the seller's items of an order
+func (s *invoiceService) IssueInvoice(sellerID string, orderID string) (*domain.Invoice, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != "confirmed" && order.Status != "shipped" {
+		return nil, errors.New("invoices can only be issued for confirmed or shipped orders")
+	}
+	if order.Currency != domain.DefaultCurrency {
+		return nil, errors.New("NF-e can only be issued for orders in " + domain.DefaultCurrency)
+	}
+
+	items, sellerTotal, orderTotal, err := sellerItems(order, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("order not found")
+	}
+
+	profile, err := s.invoiceRepo.GetFiscalProfile(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := nfe.Input{
+		Profile:  profile,
+		Order:    order,
+		Items:    items,
+		IssuedAt: time.Now(),
+	}
+	if !orderTotal.IsZero() {
+		input.Freight = order.ShippingFee.MulRatio(sellerTotal.Amount, orderTotal.Amount)
+		input.Discount = order.DiscountAmount.MulRatio(sellerTotal.Amount, orderTotal.Amount)
+	}
+	if err := nfe.Validate(input); err != nil {
+		return nil, err
+	}
+
+	// Reserving the number also claims the order: a concurrent request gets ErrInvoiceOpen
+	invoice := &domain.Invoice{
+		OrderID:     orderID,
+		SellerID:    sellerID,
+		Environment: profile.Environment,
+		IssuedAt:    input.IssuedAt,
+	}
+	if err := s.invoiceRepo.ReserveNumber(invoice); err != nil {
+		if errors.Is(err, repository.ErrInvoiceOpen) {
+			return nil, ErrInvoiceExists
+		}
+		return nil, err
+	}
+	input.Number = invoice.Number
+
+	if err := s.transmit(invoice, input, profile.Environment); err != nil {
+		// The number is spent; recording the failure lets the order be invoiced again
+		invoice.Status = domain.InvoiceStatusRejected
+		invoice.StatusMessage = truncate(err.Error(), 255)
+		if updateErr := s.invoiceRepo.Update(invoice); updateErr != nil {
+			log.Printf("failed to record NF-e %d of seller %s as rejected: %v", invoice.Number, sellerID, updateErr)
+		}
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// transmit generates, signs and sends a reserved invoice to SEFAZ, filling in the outcome
+func (s *invoiceService) transmit(invoice *domain.Invoice, input nfe.Input, environment int) error {
+	document, err := nfe.Generate(input)
+	if err != nil {
+		return err
+	}
+
+	signed := document.XML
+	if s.signer != nil {
+		if signed, err = s.signer.Sign(document.XML); err != nil {
+			return err
+		}
+	}
+
+	auth, err := s.transmitter.Authorize(document.AccessKey, environment, signed)
+	if err != nil {
+		return err
+	}
+
+	invoice.AccessKey = document.AccessKey
+	invoice.Status = domain.InvoiceStatusRejected
+	invoice.Signed = s.signer != nil
+	invoice.StatusCode = auth.StatusCode
+	invoice.StatusMessage = auth.StatusMessage
+	invoice.TotalAmount = document.Total
+	invoice.XML = string(signed)
+
+	if auth.Authorized() {
+		// SEFAZ authorized it: keep the invoice authorized even if the nfeProc cannot be built
+		invoice.Status = domain.InvoiceStatusAuthorized
+		invoice.Protocol = &auth.Protocol
+		invoice.AuthorizedAt = &auth.AuthorizedAt
+		proc, err := nfe.WrapProc(signed, document.AccessKey, environment, auth)
+		if err != nil {
+			log.Printf("NF-e %s authorized, but wrapping it in nfeProc failed: %v", document.AccessKey, err)
+			return nil
+		}
+		invoice.XML = string(proc)
+	} else {
+		log.Printf("NF-e %s rejected: %d %s", document.AccessKey, auth.StatusCode, auth.StatusMessage)
+	}
+	return nil
+}
+
+// GetInvoice retrieves the latest invoice the seller issued for an order
+func (s *invoiceService) GetInvoice(sellerID string, orderID string) (*domain.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetLatest(orderID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+	return invoice, nil
+}
+
+// RenderDANFE writes the DANFE PDF of the latest invoice
+func (s *invoiceService) RenderDANFE(sellerID string, orderID string, w io.Writer) error {
+	invoice, err := s.GetInvoice(sellerID, orderID)
+	if err != nil {
+		return err
+	}
+
+	document, err := nfe.Parse([]byte(invoice.XML))
+	if err != nil {
+		return err
+	}
+
+	protocol := ""
+	if invoice.Protocol != nil && invoice.AuthorizedAt != nil {
+		protocol = *invoice.Protocol + " - " + invoice.AuthorizedAt.Format("02/01/2006 15:04:05")
+	}
+	return nfe.RenderDANFE(document, protocol, w)
+}
+
+// sellerItems returns the order lines belonging to a seller, their goods total and the order's goods total
+func sellerItems(order *domain.Order, sellerID string) ([]domain.OrderItem, domain.Money, domain.Money, error) {
+	var items []domain.OrderItem
+	var sellerTotal, orderTotal domain.Money
+	for _, item := range order.Items {
+		lineTotal := item.PriceAtTime.Mul(int64(item.Quantity))
+		var err error
+		if orderTotal, err = orderTotal.TryAdd(lineTotal); err != nil {
+			return nil, domain.Money{}, domain.Money{}, err
+		}
+		if item.Product != nil && item.Product.SellerID == sellerID {
+			items = append(items, item)
+			sellerTotal = sellerTotal.Add(lineTotal)
+		}
+	}
+	return items, sellerTotal, orderTotal, nil
+}
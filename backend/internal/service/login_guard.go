@@ -0,0 +1,164 @@
+package service
+
+import (
+	"ecommerce/internal/throttle"
+	"log"
+	"strings"
+	"time"
+)
+
+// ThrottlePolicy describes how failures of one kind (per account, per IP) are slowed down.
+// Each failure past FreeAttempts blocks the next attempt for BaseDelay, doubling per failure
+// up to MaxDelay; LockoutAfter failures block it for LockoutDuration. Failures are counted
+// over Window, which restarts with every failure.
+type ThrottlePolicy struct {
+	FreeAttempts    int64
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutAfter    int64
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// Default throttling; LOGIN_MAX_FAILURES and LOGIN_LOCKOUT_DURATION override the account lockout
+var (
+	DefaultAccountLoginPolicy = ThrottlePolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+	}
+	// Shared networks put many customers behind one address, so the IP allows far more
+	DefaultIPLoginPolicy = ThrottlePolicy{
+		FreeAttempts:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutAfter:    100,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+	// Every registration counts, successful or not
+	DefaultRegistrationPolicy = ThrottlePolicy{
+		FreeAttempts:    5,
+		BaseDelay:       time.Minute,
+		MaxDelay:        15 * time.Minute,
+		LockoutAfter:    20,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+)
+
+// LoginGuard throttles password guessing per account and per IP, and account creation per IP.
+// Accounts are keyed by the email typed, whether it exists or not, so throttling does not
+// reveal which emails are registered.
+type LoginGuard interface {
+	CheckLogin(email string, ip string) error
+	LoginFailed(email string, ip string) (locked bool)
+	LoginSucceeded(email string)
+	CheckRegistration(ip string) error
+	RegistrationAttempted(ip string)
+}
+
+type loginGuard struct {
+	store        throttle.Store
+	account      ThrottlePolicy
+	ip           ThrottlePolicy
+	registration ThrottlePolicy
+}
+
+// NewLoginGuard creates a guard keeping its counters in store
+func NewLoginGuard(store throttle.Store, account ThrottlePolicy, ip ThrottlePolicy, registration ThrottlePolicy) LoginGuard {
+	return &loginGuard{
+		store:        store,
+		account:      account,
+		ip:           ip,
+		registration: registration,
+	}
+}
+
+// CheckLogin returns a *ThrottledError while the account or the IP is blocked
+func (g *loginGuard) CheckLogin(email string, ip string) error {
+	return g.check(accountKey(email), "ip:"+ip)
+}
+
+// LoginFailed counts a failed password or two-factor code, blocking further attempts when
+// the policies say so. It reports whether the account just reached its lockout.
+func (g *loginGuard) LoginFailed(email string, ip string) bool {
+	locked := g.fail(accountKey(email), g.account)
+	g.fail("ip:"+ip, g.ip)
+	return locked
+}
+
+// LoginSucceeded clears the account's failures. The IP keeps its count: credential stuffing
+// with a few valid passwords must not reset it.
+func (g *loginGuard) LoginSucceeded(email string) {
+	key := accountKey(email)
+	if err := g.store.Reset(key, key+":block"); err != nil {
+		log.Printf("failed to reset login throttle: %v", err)
+	}
+}
+
+// CheckRegistration returns a *ThrottledError while the IP is blocked from registering
+func (g *loginGuard) CheckRegistration(ip string) error {
+	return g.check("register:" + ip)
+}
+
+// RegistrationAttempted counts an account creation attempt from the IP
+func (g *loginGuard) RegistrationAttempted(ip string) {
+	g.fail("register:"+ip, g.registration)
+}
+
+// check returns the longest block among keys. Store failures are logged and let the
+// attempt through: an unreachable store must not lock everybody out.
+func (g *loginGuard) check(keys ...string) error {
+	var longest time.Duration
+	for _, key := range keys {
+		remaining, err := g.store.BlockedFor(key + ":block")
+		if err != nil {
+			log.Printf("failed to check login throttle: %v", err)
+			return nil
+		}
+		if remaining > longest {
+			longest = remaining
+		}
+	}
+	if longest > 0 {
+		return &ThrottledError{RetryAfter: longest}
+	}
+	return nil
+}
+
+// fail counts a failure under key and applies the policy's backoff or lockout
+func (g *loginGuard) fail(key string, policy ThrottlePolicy) bool {
+	count, err := g.store.Incr(key, policy.Window)
+	if err != nil {
+		log.Printf("failed to count login failure: %v", err)
+		return false
+	}
+
+	var block time.Duration
+	locked := count >= policy.LockoutAfter
+	switch {
+	case locked:
+		block = policy.LockoutDuration
+	case count > policy.FreeAttempts:
+		block = policy.BaseDelay << min(count-policy.FreeAttempts-1, 30)
+		if block <= 0 || block > policy.MaxDelay {
+			block = policy.MaxDelay
+		}
+	default:
+		return false
+	}
+
+	if err := g.store.Block(key+":block", block); err != nil {
+		log.Printf("failed to block after login failures: %v", err)
+	}
+	return locked && count == policy.LockoutAfter
+}
+
+// accountKey is the counter key of the account an email designates
+func accountKey(email string) string {
+	return "account:" + hashToken(strings.ToLower(strings.TrimSpace(email)))
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeThrottleStore records blocks by their duration instead of their expiry, so tests do not depend on the clock
+type fakeThrottleStore struct {
+	counts map[string]int64
+	blocks map[string]time.Duration
+	err    error
+}
+
+func newFakeThrottleStore() *fakeThrottleStore {
+	return &fakeThrottleStore{counts: map[string]int64{}, blocks: map[string]time.Duration{}}
+}
+
+func (s *fakeThrottleStore) Incr(key string, ttl time.Duration) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.counts[key]++
+	return s.counts[key], nil
+}
+
+func (s *fakeThrottleStore) Block(key string, d time.Duration) error {
+	s.blocks[key] = d
+	return s.err
+}
+
+func (s *fakeThrottleStore) BlockedFor(key string) (time.Duration, error) {
+	return s.blocks[key], s.err
+}
+
+func (s *fakeThrottleStore) Reset(keys ...string) error {
+	for _, key := range keys {
+		delete(s.counts, key)
+		delete(s.blocks, key)
+	}
+	return s.err
+}
+
+func TestLoginGuardBackoffAndLockout(t *testing.T) {
+	policy := ThrottlePolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        20 * time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+	}
+	tests := []struct {
+		failure    int64
+		wantBlock  time.Duration
+		wantLocked bool // LoginFailed reports the lockout only when it is reached
+	}{
+		{1, 0, false},
+		{3, 0, false},
+		{4, time.Second, false},
+		{5, 2 * time.Second, false},
+		{6, 4 * time.Second, false},
+		{8, 16 * time.Second, false},
+		{9, 20 * time.Second, false}, // 32s, capped at MaxDelay
+		{10, 15 * time.Minute, true},
+		{11, 15 * time.Minute, false},
+	}
+
+	store := newFakeThrottleStore()
+	guard := NewLoginGuard(store, policy, DefaultIPLoginPolicy, DefaultRegistrationPolicy)
+	key := accountKey("ana@example.com") + ":block"
+	var failures int64
+	for _, tt := range tests {
+		var locked bool
+		for failures < tt.failure {
+			locked = guard.LoginFailed(" Ana@Example.com ", "192.0.2.1")
+			failures++
+		}
+		if got := store.blocks[key]; got != tt.wantBlock {
+			t.Errorf("after %d failures: block %v, want %v", tt.failure, got, tt.wantBlock)
+		}
+		if locked != tt.wantLocked {
+			t.Errorf("failure %d: locked = %v, want %v", tt.failure, locked, tt.wantLocked)
+		}
+	}
+}
+
+func TestLoginGuardBackoffDoesNotOverflow(t *testing.T) {
+	policy := ThrottlePolicy{FreeAttempts: 0, BaseDelay: time.Second, MaxDelay: time.Hour, LockoutAfter: 1000, Window: time.Hour}
+	store := newFakeThrottleStore()
+	guard := NewLoginGuard(store, policy, policy, policy)
+
+	for i := 0; i < 100; i++ {
+		guard.RegistrationAttempted("192.0.2.1")
+		if block := store.blocks["register:192.0.2.1:block"]; block <= 0 || block > time.Hour {
+			t.Fatalf("attempt %d: block %v, want between 0 and MaxDelay", i+1, block)
+		}
+	}
+	if block := store.blocks["register:192.0.2.1:block"]; block != time.Hour {
+		t.Errorf("block after 100 attempts = %v, want MaxDelay", block)
+	}
+}
+
+func TestLoginGuardCheck(t *testing.T) {
+	store := newFakeThrottleStore()
+	guard := NewLoginGuard(store, DefaultAccountLoginPolicy, DefaultIPLoginPolicy, DefaultRegistrationPolicy)
+	account := accountKey("ana@example.com")
+
+	if err := guard.CheckLogin("ana@example.com", "192.0.2.1"); err != nil {
+		t.Fatalf("CheckLogin before any failure: %v", err)
+	}
+
+	store.blocks[account+":block"] = 10 * time.Second
+	store.blocks["ip:192.0.2.1:block"] = time.Minute
+	var throttled *ThrottledError
+	if err := guard.CheckLogin("ana@example.com", "192.0.2.1"); !errors.As(err, &throttled) || throttled.RetryAfter != time.Minute {
+		t.Errorf("CheckLogin = %v, want the longer block of a minute", err)
+	}
+
+	// A success clears the account but not the IP, which may be stuffing credentials
+	store.counts[account] = 5
+	guard.LoginSucceeded("ana@example.com")
+	if store.counts[account] != 0 || store.blocks[account+":block"] != 0 {
+		t.Error("LoginSucceeded kept the account's failures")
+	}
+	if store.blocks["ip:192.0.2.1:block"] != time.Minute {
+		t.Error("LoginSucceeded cleared the IP block")
+	}
+
+	store.err = errors.New("store unreachable")
+	if err := guard.CheckLogin("ana@example.com", "192.0.2.1"); err != nil {
+		t.Errorf("CheckLogin with the store down = %v, want the attempt let through", err)
+	}
+	if guard.LoginFailed("ana@example.com", "192.0.2.1") {
+		t.Error("LoginFailed reported a lockout with the store down")
+	}
+}
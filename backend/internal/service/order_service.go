@@ -4,8 +4,13 @@ import (
 	"ecommerce/internal/domain"
 	"ecommerce/internal/repository"
 	"errors"
+	"fmt"
+	"sort"
 )
 
+// ErrInvalidOrderStatus is returned for statuses other than the domain.Order* constants
+var ErrInvalidOrderStatus = errors.New("invalid order status")
+
 // OrderService defines order operations
 type OrderService interface {
 	CreateOrder(userID string, req *domain.CreateOrderRequest) (*domain.OrderResponse, error)
@@ -14,32 +19,196 @@ type OrderService interface {
 	UpdateOrderStatus(orderID string, status string) error
 	GetSellerOrders(sellerID string, limit int, offset int) ([]domain.OrderResponse, int64, error)
 	GetSellerAnalytics(sellerID string) (*domain.AnalyticsResponse, error)
+	QuoteOrder(userID string, req *domain.CreateOrderRequest) (*domain.CheckoutQuote, error)
 }
 
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	productRepo repository.ProductRepository
+	orderRepo          repository.OrderRepository
+	productRepo        repository.ProductRepository
+	addressRepo        repository.UserAddressRepository
+	addressLookup      AddressLookup      // optional, fills street/neighborhood/city from the CEP
+	taxCalculator      TaxCalculator      // optional, orders carry no tax breakdown without it
+	shippingCalculator ShippingCalculator // optional, orders ship for free without it
+	converter          CurrencyConverter  // optional, orders outside DefaultCurrency cannot be shipped without it
 }
 
-// NewOrderService creates a new order service
-func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository) OrderService {
+// NewOrderService creates a new order service.
+// addressLookup may be nil, in which case addresses are only validated.
+func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, addressRepo repository.UserAddressRepository, addressLookup AddressLookup, taxCalculator TaxCalculator, shippingCalculator ShippingCalculator, converter CurrencyConverter) OrderService {
 	return &orderService{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:          orderRepo,
+		productRepo:        productRepo,
+		addressRepo:        addressRepo,
+		addressLookup:      addressLookup,
+		taxCalculator:      taxCalculator,
+		shippingCalculator: shippingCalculator,
+		converter:          converter,
 	}
 }
 
 // CreateOrder creates a new order from cart items
 func (s *orderService) CreateOrder(userID string, req *domain.CreateOrderRequest) (*domain.OrderResponse, error) {
+	priced, err := s.priceOrder(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := priced.problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	// Create order with calculated total
+	order := &domain.Order{
+		UserID:           userID,
+		Status:           "pending",
+		Currency:         priced.currency,
+		TotalAmount:      priced.total,
+		ShippingFee:      priced.shippingFee,
+		ShippingMethod:   &priced.shippingMethod,
+		DiscountAmount:   priced.discount,
+		TaxAmount:        priced.taxAmount,
+		ShippingAddress:  &priced.address,
+		AddressID:        priced.addressID,
+		PaymentMethod:    &req.PaymentMethod,
+		CustomerDocument: priced.customerDocument,
+	}
+
+	// Products are only needed for pricing, the items reference them by ID
+	for i := range priced.items {
+		priced.items[i].Product = nil
+	}
+
+	// Save order with items in transaction
+	if err := s.orderRepo.CreateOrderWithItems(order, priced.items); err != nil {
+		return nil, err
+	}
+
+	// Fetch the created order with full details
+	createdOrder, err := s.orderRepo.GetByID(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return createdOrder.ToResponse(), nil
+}
+
+// QuoteOrder prices an order request without placing it.
+// Problems that would make CreateOrder fail are reported in the quote instead of as an error.
+// userID is empty for guests, who cannot quote to a saved address.
+func (s *orderService) QuoteOrder(userID string, req *domain.CreateOrderRequest) (*domain.CheckoutQuote, error) {
+	priced, err := s.priceOrder(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.CheckoutQuote{
+		Currency:        priced.currency,
+		Lines:           make([]domain.QuoteLine, len(priced.items)),
+		Subtotal:        priced.subtotal,
+		DiscountAmount:  priced.discount,
+		ShippingOptions: priced.shippingOptions,
+		ShippingMethod:  priced.shippingMethod,
+		ShippingFee:     priced.shippingFee,
+		TaxAmount:       priced.taxAmount,
+		Total:           priced.total,
+		Valid:           !priced.problems.HasErrors(),
+	}
+	if !quote.Valid {
+		quote.Problems = priced.problems.Fields
+	}
+	if quote.ShippingOptions == nil {
+		quote.ShippingOptions = []domain.ShippingOption{}
+	}
+
+	for i, item := range priced.items {
+		line := domain.QuoteLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.PriceAtTime,
+			LineTotal: item.PriceAtTime.Mul(int64(item.Quantity)),
+			Color:     item.Color,
+			Size:      item.Size,
+		}
+		if item.Product != nil {
+			line.ProductName = item.Product.Name
+		}
+		if item.Tax.DestinationState != "" {
+			tax := item.Tax
+			line.Tax = &tax
+		}
+		quote.Lines[i] = line
+	}
+
+	return quote, nil
+}
+
+// pricedOrder is the result of the pricing pipeline shared by CreateOrder and QuoteOrder
+type pricedOrder struct {
+	currency         string                 // Every amount below is in this currency
+	address          domain.ShippingAddress // Copied onto the order, so later address book edits do not rewrite it
+	addressID        *string                // Address book entry the address was copied from
+	customerDocument *string
+	items            []domain.OrderItem // Priced lines with Product loaded, only for products that exist
+	subtotal         domain.Money
+	discount         domain.Money // No promotions exist yet, totals already account for it
+	shippingOptions  []domain.ShippingOption
+	shippingMethod   string
+	shippingFee      domain.Money
+	taxAmount        domain.Money
+	total            domain.Money
+	problems         *domain.ValidationError
+}
+
+// priceOrder validates and prices an order request.
+// Request problems are collected in pricedOrder.problems; the error is only for infrastructure failures.
+func (s *orderService) priceOrder(userID string, req *domain.CreateOrderRequest) (*pricedOrder, error) {
+	priced := &pricedOrder{
+		currency: domain.DefaultCurrency,
+		problems: domain.NewValidationError(),
+	}
+
+	// The order is priced in a single currency, from the products' list prices in it
+	if currency := domain.NormalizeCurrency(req.Currency); currency != "" {
+		if domain.IsSupportedCurrency(currency) {
+			priced.currency = currency
+		} else {
+			priced.problems.Add("currency", "unsupported currency: "+req.Currency)
+		}
+	}
+	zero := domain.NewMoney(0, priced.currency)
+	priced.subtotal, priced.discount, priced.shippingFee, priced.taxAmount = zero, zero, zero, zero
+
 	if len(req.Items) == 0 {
-		return nil, errors.New("order must have at least one item")
+		priced.problems.Add("items", "order must have at least one item")
 	}
 
-	// Calculate total and create order items
-	var totalAmount float64
-	orderItems := make([]domain.OrderItem, 0)
+	if err := s.resolveAddress(priced, userID, req); err != nil {
+		return nil, err
+	}
+	destinationState := ""
+	if priced.address.IsDomestic() && domain.IsValidState(priced.address.State) {
+		destinationState = priced.address.State
+	}
+	// ICMS applies to sales delivered in Brazil and is computed on BRL prices
+	taxable := destinationState != "" && priced.currency == domain.DefaultCurrency
+
+	if req.CustomerDocument != "" {
+		if document := domain.NormalizeTaxDocument(req.CustomerDocument); document != "" {
+			priced.customerDocument = &document
+		} else {
+			priced.problems.Add("customer_document", "must be a valid CPF or CNPJ")
+		}
+	}
 
+	// Stock is checked against the total requested per product, across lines
+	requested := make(map[string]int)
 	for _, item := range req.Items {
+		requested[item.ProductID] += item.Quantity
+	}
+
+	shippingLines := make([]ShippingLine, 0, len(req.Items))
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+
 		// CRITICAL: Fetch actual product price from database
 		// Never trust prices sent from frontend
 		product, err := s.productRepo.FindByID(item.ProductID)
@@ -47,51 +216,157 @@ func (s *orderService) CreateOrder(userID string, req *domain.CreateOrderRequest
 			return nil, err
 		}
 		if product == nil {
-			return nil, errors.New("product not found: " + item.ProductID)
+			priced.problems.Add(field+".product_id", "product not found")
+			continue
 		}
 
 		// Verify product is available
-		if !product.IsActive {
-			return nil, errors.New("product is not available: " + product.Name)
+		if !product.IsAvailable() {
+			priced.problems.Add(field+".product_id", "product is not available: "+product.Name)
+		} else if product.StockQuantity < requested[item.ProductID] {
+			priced.problems.Add(field+".quantity", fmt.Sprintf("insufficient stock for %s: %d available", product.Name, product.StockQuantity))
 		}
 
-		// Verify stock
-		if product.StockQuantity < item.Quantity {
-			return nil, errors.New("insufficient stock for: " + product.Name)
+		// Exchange rates are for display only, an order needs a list price in its currency
+		price, ok := product.PriceIn(priced.currency)
+		if !ok {
+			priced.problems.Add(field+".product_id", fmt.Sprintf("%s is not sold in %s", product.Name, priced.currency))
+			continue
 		}
 
 		orderItem := domain.OrderItem{
 			ProductID:   item.ProductID,
 			Quantity:    item.Quantity,
-			PriceAtTime: product.Price, // Use actual price from database
+			PriceAtTime: price, // Use actual price from database
 			Color:       item.Color,
 			Size:        item.Size,
+			Product:     product,
 		}
-		orderItems = append(orderItems, orderItem)
-		totalAmount += product.Price * float64(item.Quantity)
-	}
 
-	// Create order with calculated total
-	order := &domain.Order{
-		UserID:          userID,
-		Status:          "pending",
-		TotalAmount:     totalAmount,
-		ShippingAddress: &req.ShippingAddress,
-		PaymentMethod:   &req.PaymentMethod,
+		if s.taxCalculator != nil && taxable {
+			tax, err := s.taxCalculator.Calculate(TaxInput{
+				Product:          product,
+				Quantity:         item.Quantity,
+				UnitPrice:        price,
+				DestinationState: destinationState,
+			})
+			if err != nil {
+				return nil, err
+			}
+			orderItem.Tax = *tax
+			priced.taxAmount = priced.taxAmount.Add(tax.TotalTax)
+		}
+
+		priced.items = append(priced.items, orderItem)
+		priced.subtotal = priced.subtotal.Add(price.Mul(int64(item.Quantity)))
+		shippingLines = append(shippingLines, ShippingLine{Product: product, Quantity: item.Quantity})
 	}
 
-	// Save order with items in transaction
-	if err := s.orderRepo.CreateOrderWithItems(order, orderItems); err != nil {
+	if err := s.selectShipping(priced, req.ShippingMethod, shippingLines, destinationState); err != nil {
 		return nil, err
 	}
 
-	// Fetch the created order with full details
-	createdOrder, err := s.orderRepo.GetByID(order.ID)
+	priced.total = priced.subtotal.Sub(priced.discount).Add(priced.shippingFee)
+	return priced, nil
+}
+
+// resolveAddress sets the destination from the saved address or the inline one, whichever the request gives
+func (s *orderService) resolveAddress(priced *pricedOrder, userID string, req *domain.CreateOrderRequest) error {
+	switch {
+	case req.AddressID != "" && req.ShippingAddress != nil:
+		priced.problems.Add("shipping_address", "give either address_id or shipping_address, not both")
+		return nil
+	case req.AddressID != "":
+		if userID == "" {
+			priced.problems.Add("address_id", "log in to use a saved address")
+			return nil
+		}
+		saved, err := s.addressRepo.GetByID(userID, req.AddressID)
+		if err != nil {
+			return err
+		}
+		if saved == nil {
+			priced.problems.Add("address_id", "address not found")
+			return nil
+		}
+		priced.address = saved.Address
+		priced.addressID = &saved.ID
+		priced.problems.Merge("address_id", normalizeShippingAddress(s.addressLookup, &priced.address))
+	case req.ShippingAddress != nil:
+		priced.address = *req.ShippingAddress
+		priced.problems.Merge("shipping_address", normalizeShippingAddress(s.addressLookup, &priced.address))
+	default:
+		priced.problems.Add("shipping_address", "address_id or shipping_address is required")
+	}
+	return nil
+}
+
+// selectShipping lists the shipping options and applies the requested one (or the cheapest)
+func (s *orderService) selectShipping(priced *pricedOrder, method string, lines []ShippingLine, destinationState string) error {
+	if s.shippingCalculator == nil {
+		if method != "" {
+			priced.problems.Add("shipping_method", "shipping is not available")
+		}
+		return nil
+	}
+	if len(lines) == 0 || (priced.address.IsDomestic() && destinationState == "") {
+		return nil
+	}
+
+	// Freight is priced in DefaultCurrency and converted to the order currency
+	subtotal, err := s.convert(priced.subtotal, domain.DefaultCurrency)
+	if errors.Is(err, ErrNoExchangeRate) {
+		priced.problems.Add("shipping_method", "shipping is not available in "+priced.currency)
+		return nil
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return createdOrder.ToResponse(), nil
+	options, err := s.shippingCalculator.Options(&priced.address, lines, subtotal)
+	if err != nil {
+		return err
+	}
+	for i := range options {
+		if options[i].Price, err = s.convert(options[i].Price, priced.currency); err != nil {
+			return err
+		}
+	}
+	priced.shippingOptions = options
+	if len(options) == 0 {
+		priced.problems.Add("shipping_method", "no shipping option delivers to this address")
+		return nil
+	}
+
+	selected := &options[0]
+	if method != "" {
+		selected = nil
+		for i := range options {
+			if options[i].Method == method {
+				selected = &options[i]
+				break
+			}
+		}
+		if selected == nil {
+			priced.problems.Add("shipping_method", "unknown shipping method: "+method)
+			return nil
+		}
+	}
+
+	priced.shippingMethod = selected.Method
+	priced.shippingFee = selected.Price
+	return nil
+}
+
+// convert converts an amount with the exchange rate table; amounts already in the currency pass through
+func (s *orderService) convert(amount domain.Money, currency string) (domain.Money, error) {
+	if amount.Currency == currency {
+		return amount, nil
+	}
+	if s.converter == nil {
+		return domain.Money{}, ErrNoExchangeRate
+	}
+	return s.converter.Convert(amount, currency)
 }
 
 // GetOrder retrieves an order by ID
@@ -132,16 +407,8 @@ func (s *orderService) GetUserOrders(userID string, limit int, offset int) ([]do
 
 // UpdateOrderStatus updates the status of an order
 func (s *orderService) UpdateOrderStatus(orderID string, status string) error {
-	validStatuses := map[string]bool{
-		"pending":   true,
-		"confirmed": true,
-		"shipped":   true,
-		"delivered": true,
-		"cancelled": true,
-	}
-
-	if !validStatuses[status] {
-		return errors.New("invalid order status")
+	if !domain.IsValidOrderStatus(status) {
+		return ErrInvalidOrderStatus
 	}
 
 	return s.orderRepo.UpdateStatus(orderID, status)
@@ -177,24 +444,32 @@ func (s *orderService) GetSellerAnalytics(sellerID string) (*domain.AnalyticsRes
 		return nil, err
 	}
 
-	totalOrders := int64(len(orders))
-	totalRevenue := 0.0
-
+	// Revenue is reported per currency, amounts in different currencies are never added up
+	byCurrency := make(map[string]*domain.CurrencyRevenue)
 	for _, order := range orders {
-		totalRevenue += order.TotalAmount
+		revenue, ok := byCurrency[order.Currency]
+		if !ok {
+			revenue = &domain.CurrencyRevenue{Currency: order.Currency, TotalRevenue: domain.NewMoney(0, order.Currency)}
+			byCurrency[order.Currency] = revenue
+		}
+		revenue.TotalOrders++
+		revenue.TotalRevenue = revenue.TotalRevenue.Add(order.TotalAmount)
 	}
 
-	avgOrder := 0.0
-	if totalOrders > 0 {
-		avgOrder = totalRevenue / float64(totalOrders)
+	revenues := make([]domain.CurrencyRevenue, 0, len(byCurrency))
+	for _, revenue := range byCurrency {
+		revenue.AverageOrderValue = revenue.TotalRevenue.MulRatio(1, revenue.TotalOrders)
+		revenues = append(revenues, *revenue)
 	}
+	sort.Slice(revenues, func(i, j int) bool {
+		return revenues[i].Currency < revenues[j].Currency
+	})
 
 	return &domain.AnalyticsResponse{
-		TotalOrders:       totalOrders,
-		TotalRevenue:      totalRevenue,
-		AverageOrderValue: avgOrder,
-		TotalProducts:     0, // Should fetch from product repo
-		TotalViews:        0, // Should be tracked separately
-		ConversionRate:    0.0,
+		TotalOrders:    int64(len(orders)),
+		Revenue:        revenues,
+		TotalProducts:  0, // Should fetch from product repo
+		TotalViews:     0, // Should be tracked separately
+		ConversionRate: 0.0,
 	}, nil
 }
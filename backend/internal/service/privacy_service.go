@@ -0,0 +1,369 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"ecommerce/internal/domain"
+	"ecommerce/internal/mail"
+	"ecommerce/internal/repository"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	exportTTL       = 7 * 24 * time.Hour // How long an export archive can be downloaded
+	exportBatchSize = 100                // Orders loaded per query while exporting
+
+	// Accounts without a password confirm an erasure by having signed in this recently
+	erasureReauthWindow = 10 * time.Minute
+)
+
+var (
+	// ErrPrivacyRequestNotFound is returned when a data-subject request does not exist or belongs to someone else
+	ErrPrivacyRequestNotFound = errors.New("privacy request not found")
+	// ErrExportNotReady is returned when downloading an export that is still being produced or failed
+	ErrExportNotReady = errors.New("export is not ready")
+	// ErrExportExpired is returned when downloading an export after exportTTL
+	ErrExportExpired = errors.New("export has expired, request a new one")
+	// ErrErasureNotAllowed is returned when a seller or admin asks to erase their account
+	ErrErasureNotAllowed = errors.New("seller and admin accounts must be closed through support")
+	// ErrOpenOrders is returned when erasing an account with orders that were not delivered or cancelled
+	ErrOpenOrders = errors.New("account has orders in progress; erase it once they are delivered or cancelled")
+	// ErrReauthRequired is returned when an account without a password asks for erasure from an older session
+	ErrReauthRequired = errors.New("sign in again to confirm the erasure of your account")
+)
+
+// openOrderStatuses still need the customer's address and contact
+var openOrderStatuses = []string{domain.OrderPending, domain.OrderConfirmed, domain.OrderShipped}
+
+// PrivacyService defines LGPD data-subject requests: data export and account erasure.
+// Both run in the background and are tracked as domain.PrivacyRequest.
+type PrivacyService interface {
+	RequestExport(userID string, client domain.ClientInfo) (*domain.PrivacyRequest, error)
+	GetExport(userID string, id string) (*domain.PrivacyRequest, error)
+	DownloadExport(userID string, id string) (*domain.PrivacyRequest, error)
+	RequestErasure(userID string, sessionID string, req *domain.EraseAccountRequest) (*domain.PrivacyRequest, error)
+	GetErasure(id string) (*domain.PrivacyRequest, error)
+	ResumeUnfinished() error
+}
+
+type privacyService struct {
+	privacyRepo repository.PrivacyRepository
+	userRepo    repository.UserRepository
+	addressRepo repository.UserAddressRepository
+	orderRepo   repository.OrderRepository
+	auditRepo   repository.AuditRepository
+	storeRepo   repository.StoreRepository
+	sessionRepo repository.SessionRepository
+	mailer      mail.Mailer
+	frontendURL string
+}
+
+// NewPrivacyService creates a new privacy service.
+// Users are emailed when their request completes, with links to frontendURL.
+func NewPrivacyService(privacyRepo repository.PrivacyRepository, userRepo repository.UserRepository, addressRepo repository.UserAddressRepository, orderRepo repository.OrderRepository, auditRepo repository.AuditRepository, storeRepo repository.StoreRepository, sessionRepo repository.SessionRepository, mailer mail.Mailer, frontendURL string) PrivacyService {
+	return &privacyService{
+		privacyRepo: privacyRepo,
+		userRepo:    userRepo,
+		addressRepo: addressRepo,
+		orderRepo:   orderRepo,
+		auditRepo:   auditRepo,
+		storeRepo:   storeRepo,
+		sessionRepo: sessionRepo,
+		mailer:      mailer,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// RequestExport queues a copy of the user's data. While one is queued or running it is returned instead.
+func (s *privacyService) RequestExport(userID string, client domain.ClientInfo) (*domain.PrivacyRequest, error) {
+	if _, err := s.activeUser(userID); err != nil {
+		return nil, err
+	}
+	request, err := s.enqueue(userID, domain.PrivacyExport)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.auditRepo, userID, userID, domain.AuditDataExportRequested, nil, client)
+	return request, nil
+}
+
+// GetExport retrieves one of the user's export requests
+func (s *privacyService) GetExport(userID string, id string) (*domain.PrivacyRequest, error) {
+	request, err := s.privacyRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.UserID != userID || request.Kind != domain.PrivacyExport {
+		return nil, ErrPrivacyRequestNotFound
+	}
+	return request, nil
+}
+
+// DownloadExport retrieves a completed export with its archive
+func (s *privacyService) DownloadExport(userID string, id string) (*domain.PrivacyRequest, error) {
+	request, err := s.GetExport(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domain.PrivacyCompleted {
+		return nil, ErrExportNotReady
+	}
+	if request.ExpiresAt == nil || !time.Now().Before(*request.ExpiresAt) || len(request.Archive) == 0 {
+		return nil, ErrExportExpired
+	}
+	return request, nil
+}
+
+// RequestErasure queues the anonymization of the account after checking the password.
+// Accounts without one (social login only) re-authenticate instead: sessionID, the session of
+// the request, must have signed in within erasureReauthWindow.
+// Only customers without orders in progress can erase their account.
+func (s *privacyService) RequestErasure(userID string, sessionID string, req *domain.EraseAccountRequest) (*domain.PrivacyRequest, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.PasswordHash != "" {
+		if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+			return nil, ErrWrongPassword
+		}
+	} else if err := s.checkRecentSignIn(userID, sessionID); err != nil {
+		return nil, err
+	}
+	if user.Role != domain.RoleCustomer {
+		return nil, ErrErasureNotAllowed
+	}
+	open, err := s.orderRepo.CountByUserAndStatus(userID, openOrderStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if open > 0 {
+		return nil, ErrOpenOrders
+	}
+
+	return s.enqueue(userID, domain.PrivacyErasure)
+}
+
+// checkRecentSignIn returns ErrReauthRequired unless the user's session signed in within erasureReauthWindow
+func (s *privacyService) checkRecentSignIn(userID string, sessionID string) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || time.Since(session.CreatedAt) > erasureReauthWindow {
+		return ErrReauthRequired
+	}
+	return nil
+}
+
+// GetErasure retrieves an erasure request. Its owner can no longer log in, so only the ID is needed.
+func (s *privacyService) GetErasure(id string) (*domain.PrivacyRequest, error) {
+	request, err := s.privacyRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.Kind != domain.PrivacyErasure {
+		return nil, ErrPrivacyRequestNotFound
+	}
+	request.Archive = nil
+	return request, nil
+}
+
+// ResumeUnfinished restarts the requests interrupted by a shutdown. Both kinds are safe to run twice.
+func (s *privacyService) ResumeUnfinished() error {
+	requests, err := s.privacyRepo.ListUnfinished()
+	if err != nil {
+		return err
+	}
+	for i := range requests {
+		go s.process(&requests[i])
+	}
+	return nil
+}
+
+// enqueue creates a request and starts processing it, unless one of the same kind is already active
+func (s *privacyService) enqueue(userID string, kind string) (*domain.PrivacyRequest, error) {
+	active, err := s.privacyRepo.GetActive(userID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	request := &domain.PrivacyRequest{
+		UserID: userID,
+		Kind:   kind,
+		Status: domain.PrivacyPending,
+	}
+	if err := s.privacyRepo.Create(request); err != nil {
+		return nil, err
+	}
+
+	job := *request
+	go s.process(&job)
+	return request, nil
+}
+
+// process runs a request to completion and records the outcome
+func (s *privacyService) process(request *domain.PrivacyRequest) {
+	started := time.Now()
+	request.Status = domain.PrivacyRunning
+	request.StartedAt = &started
+	if err := s.privacyRepo.Update(request); err != nil {
+		log.Printf("failed to start privacy request %s: %v", request.ID, err)
+		return
+	}
+
+	var notify *mail.Message
+	var err error
+	switch request.Kind {
+	case domain.PrivacyExport:
+		notify, err = s.export(request)
+	case domain.PrivacyErasure:
+		notify, err = s.erase(request)
+	default:
+		err = fmt.Errorf("unknown privacy request kind %q", request.Kind)
+	}
+
+	completed := time.Now()
+	request.CompletedAt = &completed
+	if err != nil {
+		log.Printf("privacy request %s (%s) failed: %v", request.ID, request.Kind, err)
+		message := err.Error()
+		request.Status = domain.PrivacyFailed
+		request.Error = &message
+	} else {
+		request.Status = domain.PrivacyCompleted
+	}
+	if err := s.privacyRepo.Update(request); err != nil {
+		log.Printf("failed to finish privacy request %s: %v", request.ID, err)
+		return
+	}
+
+	if err == nil && notify != nil && s.mailer != nil {
+		if err := s.mailer.Send(*notify); err != nil {
+			log.Printf("failed to send %q to %s: %v", notify.Subject, notify.To, err)
+		}
+	}
+}
+
+// export builds the archive of an export request
+func (s *privacyService) export(request *domain.PrivacyRequest) (*mail.Message, error) {
+	user, err := s.userRepo.GetByID(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.ErasedAt != nil {
+		return nil, errors.New("user no longer exists")
+	}
+
+	data := &domain.DataExport{
+		ExportedAt: time.Now(),
+		Profile:    user.ToResponse(),
+		Orders:     []domain.OrderResponse{},
+	}
+	if data.Addresses, err = s.addressRepo.ListByUser(user.ID); err != nil {
+		return nil, err
+	}
+	for offset := 0; ; offset += exportBatchSize {
+		orders, total, err := s.orderRepo.GetByUserID(user.ID, exportBatchSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for i := range orders {
+			data.Orders = append(data.Orders, *orders[i].ToResponse())
+		}
+		if len(orders) == 0 || int64(offset+len(orders)) >= total {
+			break
+		}
+	}
+	if data.AuditLog, _, err = s.auditRepo.ListByUser(user.ID, -1, -1); err != nil {
+		return nil, err
+	}
+	if data.SellerApplication, err = s.storeRepo.GetLatestApplication(user.ID); err != nil {
+		return nil, err
+	}
+	if data.Store, err = s.storeRepo.GetStoreBySellerID(user.ID); err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(exportTTL)
+	request.Archive = archive.Bytes()
+	request.ExpiresAt = &expires
+
+	return &mail.Message{
+		To:      user.Email,
+		Subject: "Seus dados estão prontos",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"A cópia dos seus dados que você pediu está pronta. Baixe-a pelo seu perfil em %s/profile.\n\n"+
+			"O arquivo fica disponível por 7 dias.\n",
+			user.Name, s.frontendURL),
+	}, nil
+}
+
+// erase anonymizes the account of an erasure request
+func (s *privacyService) erase(request *domain.PrivacyRequest) (*mail.Message, error) {
+	user, err := s.userRepo.GetByID(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user no longer exists")
+	}
+	if user.ErasedAt != nil {
+		return nil, nil
+	}
+
+	if err := s.privacyRepo.EraseUser(user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Sent to the address the account had, which is no longer stored
+	return &mail.Message{
+		To:      user.Email,
+		Subject: "Sua conta foi excluída",
+		Body: fmt.Sprintf("Olá, %s.\n\n"+
+			"Conforme solicitado, seus dados pessoais foram removidos. "+
+			"Mantemos apenas os registros fiscais dos seus pedidos, exigidos por lei.\n",
+			user.Name),
+	}, nil
+}
+
+// activeUser loads a user that may still make requests
+func (s *privacyService) activeUser(userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
+	return user, nil
+}
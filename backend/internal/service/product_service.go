@@ -3,25 +3,34 @@ package service
 import (
 	"ecommerce/internal/domain"
 	"ecommerce/internal/repository"
+	"errors"
+	"fmt"
 )
 
+// ErrProductNotFound is returned when a product does not exist or belongs to another seller
+var ErrProductNotFound = errors.New("product not found")
+
 type ProductService interface {
-	List(page int, perPage int) (interface{}, error)
-	GetByID(id string) (*domain.Product, error)
+	List(page int, perPage int, currency string) (interface{}, error)
+	ListBySeller(sellerID string, page int, perPage int, currency string) (interface{}, error)
+	GetByID(id string, currency string) (*domain.Product, error)
+	SetPrices(sellerID string, productID string, req *domain.SetProductPricesRequest) (*domain.Product, error)
 	Create(product *domain.Product) error
 	Update(product *domain.Product) error
 	Delete(id string) error
 }
 
 type productService struct {
-	repo repository.ProductRepository
+	repo      repository.ProductRepository
+	converter CurrencyConverter // optional, products without a price list have no display price without it
 }
 
-func NewProductService(repo repository.ProductRepository) ProductService {
-	return &productService{repo: repo}
+func NewProductService(repo repository.ProductRepository, converter CurrencyConverter) ProductService {
+	return &productService{repo: repo, converter: converter}
 }
 
-func (s *productService) List(page int, perPage int) (interface{}, error) {
+// List returns a page of products with their display price in currency
+func (s *productService) List(page int, perPage int, currency string) (interface{}, error) {
 	if perPage <= 0 {
 		perPage = 20
 	}
@@ -33,6 +42,27 @@ func (s *productService) List(page int, perPage int) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	return s.page(items, total, page, perPage, currency)
+}
+
+// ListBySeller returns a page of a seller's products, inactive ones included, with their
+// display price in currency
+func (s *productService) ListBySeller(sellerID string, page int, perPage int, currency string) (interface{}, error) {
+	page, perPage = normalizePage(page, perPage)
+	items, total, err := s.repo.FindBySellerID(sellerID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return s.page(items, total, page, perPage, currency)
+}
+
+// page prices the items in currency and wraps them with their pagination
+func (s *productService) page(items []domain.Product, total int64, page int, perPage int, currency string) (interface{}, error) {
+	for i := range items {
+		if err := applyDisplayPrice(s.converter, &items[i], currency); err != nil {
+			return nil, err
+		}
+	}
 	return map[string]interface{}{
 		"items": items,
 		"pagination": map[string]int{
@@ -44,11 +74,71 @@ func (s *productService) List(page int, perPage int) (interface{}, error) {
 	}, nil
 }
 
-// GetByID retrieves a product by ID (implement in repo if not exists)
-func (s *productService) GetByID(id string) (*domain.Product, error) {
-	// This would require a GetByID method in the repository
-	// For now, returning a placeholder error
-	return nil, nil
+// GetByID retrieves a product by ID with its display price in currency.
+// Products of suspended sellers are not found.
+func (s *productService) GetByID(id string, currency string) (*domain.Product, error) {
+	product, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.SellerSuspended {
+		return nil, ErrProductNotFound
+	}
+	if err := applyDisplayPrice(s.converter, product, currency); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// SetPrices replaces the price lists of one of the seller's products.
+// The product's own currency is priced by Product.Price and cannot appear in the list.
+func (s *productService) SetPrices(sellerID string, productID string, req *domain.SetProductPricesRequest) (*domain.Product, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.SellerID != sellerID {
+		return nil, ErrProductNotFound
+	}
+
+	problems := domain.NewValidationError()
+	seen := make(map[string]bool)
+	prices := make([]domain.ProductPrice, 0, len(req.Prices))
+	for i, input := range req.Prices {
+		field := fmt.Sprintf("prices[%d]", i)
+		currency := domain.NormalizeCurrency(input.Currency)
+
+		switch {
+		case !domain.IsSupportedCurrency(currency):
+			problems.Add(field+".currency", "unsupported currency: "+input.Currency)
+			continue
+		case currency == product.Currency:
+			problems.Add(field+".currency", "the product is already priced in "+currency)
+			continue
+		case seen[currency]:
+			problems.Add(field+".currency", "duplicate currency: "+currency)
+			continue
+		}
+		seen[currency] = true
+
+		if input.Price.Amount <= 0 {
+			problems.Add(field+".price", "must be greater than zero")
+		}
+		price := domain.ProductPrice{Currency: currency, Price: input.Price.In(currency)}
+		if input.CompareAtPrice != nil {
+			compareAt := input.CompareAtPrice.In(currency)
+			price.CompareAtPrice = &compareAt
+		}
+		prices = append(prices, price)
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetPrices(product.ID, prices); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(product.ID)
 }
 
 // Create creates a new product (implement in repo if not exists)
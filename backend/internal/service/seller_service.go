@@ -0,0 +1,333 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/mail"
+	"ecommerce/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// maxSlugAttempts bounds the numeric suffixes tried when a store slug is taken
+const maxSlugAttempts = 50
+
+var (
+	// ErrApplicationNotFound is returned when a seller application does not exist
+	ErrApplicationNotFound = errors.New("seller application not found")
+	// ErrApplicationPending is returned when applying while a previous application awaits review
+	ErrApplicationPending = errors.New("a seller application is already awaiting review")
+	// ErrAlreadySeller is returned when a seller or admin applies to sell
+	ErrAlreadySeller = errors.New("only customers can apply to sell")
+	// ErrApplicationReviewed is returned when approving or rejecting an application twice
+	ErrApplicationReviewed = errors.New("seller application was already reviewed")
+	// ErrApplicantIneligible is returned when approving an application whose applicant was promoted,
+	// suspended or erased since applying
+	ErrApplicantIneligible = errors.New("applicant is no longer an active customer")
+	// ErrStoreNotFound is returned when a store does not exist
+	ErrStoreNotFound = errors.New("store not found")
+)
+
+// SellerService defines seller onboarding and store profile operations
+type SellerService interface {
+	Apply(user *domain.User, req *domain.SellerApplicationRequest) (*domain.SellerApplication, error)
+	GetMyApplication(userID string) (*domain.SellerApplication, error)
+	ListApplications(status string, page int, perPage int) (interface{}, error)
+	Approve(adminID string, applicationID string) (*domain.SellerApplication, error)
+	Reject(adminID string, applicationID string, reason string) (*domain.SellerApplication, error)
+	GetMyStore(sellerID string) (*domain.Store, error)
+	UpdateMyStore(sellerID string, req *domain.UpdateStoreRequest) (*domain.Store, error)
+	GetStorePage(slug string, page int, perPage int, currency string) (*domain.StorePublicResponse, error)
+}
+
+type sellerService struct {
+	storeRepo     repository.StoreRepository
+	productRepo   repository.ProductRepository
+	addressLookup AddressLookup     // optional, addresses are validated as typed without it
+	converter     CurrencyConverter // optional, see productService
+	mailer        mail.Mailer
+	frontendURL   string
+}
+
+// NewSellerService creates a new seller service.
+// Applicants are notified of the review by email, with links to frontendURL.
+func NewSellerService(storeRepo repository.StoreRepository, productRepo repository.ProductRepository, addressLookup AddressLookup, converter CurrencyConverter, mailer mail.Mailer, frontendURL string) SellerService {
+	return &sellerService{
+		storeRepo:     storeRepo,
+		productRepo:   productRepo,
+		addressLookup: addressLookup,
+		converter:     converter,
+		mailer:        mailer,
+		frontendURL:   strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// Apply records a customer's request to sell. The CNPJ, bank account and
+// business address are validated up front so admins only review complete applications.
+func (s *sellerService) Apply(user *domain.User, req *domain.SellerApplicationRequest) (*domain.SellerApplication, error) {
+	if user.Role != domain.RoleCustomer {
+		return nil, ErrAlreadySeller
+	}
+	latest, err := s.storeRepo.GetLatestApplication(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.Status == domain.ApplicationPending {
+		return nil, ErrApplicationPending
+	}
+
+	application := &domain.SellerApplication{
+		UserID:      user.ID,
+		Status:      domain.ApplicationPending,
+		StoreName:   strings.Join(strings.Fields(req.StoreName), " "),
+		LegalName:   strings.Join(strings.Fields(req.LegalName), " "),
+		Description: strings.TrimSpace(req.Description),
+		BankAccount: req.BankAccount,
+	}
+
+	problems := domain.NewValidationError()
+	if domain.Slugify(application.StoreName) == "" {
+		problems.Add("store_name", "must contain letters or digits")
+	}
+	if domain.IsValidCNPJ(req.CNPJ) {
+		application.CNPJ = domain.OnlyDigits(req.CNPJ)
+	} else {
+		problems.Add("cnpj", "must be a valid CNPJ")
+	}
+	problems.Merge("bank_account", application.BankAccount.Validate())
+
+	address := req.Address
+	if strings.TrimSpace(address.Recipient) == "" {
+		address.Recipient = application.LegalName
+	}
+	problems.Merge("address", normalizeShippingAddress(s.addressLookup, &address))
+	application.Address = &address
+
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := s.storeRepo.CreateApplication(application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// GetMyApplication retrieves the user's most recent application
+func (s *sellerService) GetMyApplication(userID string) (*domain.SellerApplication, error) {
+	application, err := s.storeRepo.GetLatestApplication(userID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	return application, nil
+}
+
+// ListApplications returns a page of applications, optionally filtered by status
+func (s *sellerService) ListApplications(status string, page int, perPage int) (interface{}, error) {
+	page, perPage = normalizePage(page, perPage)
+	items, total, err := s.storeRepo.ListApplications(status, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(items, total, page, perPage), nil
+}
+
+// Approve creates the applicant's store and upgrades them to seller
+func (s *sellerService) Approve(adminID string, applicationID string) (*domain.SellerApplication, error) {
+	application, err := s.reviewable(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, err := s.uniqueSlug(application.StoreName)
+	if err != nil {
+		return nil, err
+	}
+	store := &domain.Store{
+		SellerID:    application.UserID,
+		Name:        application.StoreName,
+		Slug:        slug,
+		Description: application.Description,
+		LegalName:   application.LegalName,
+		CNPJ:        application.CNPJ,
+		BankAccount: application.BankAccount,
+		Address:     application.Address,
+	}
+
+	application.ReviewedBy = &adminID
+	if err := s.storeRepo.ApproveApplication(application, store); err != nil {
+		if errors.Is(err, repository.ErrApplicationNotPending) {
+			return nil, ErrApplicationReviewed
+		}
+		if errors.Is(err, repository.ErrApplicantNotCustomer) {
+			return nil, ErrApplicantIneligible
+		}
+		return nil, err
+	}
+
+	if application.User != nil {
+		s.sendAsync(mail.Message{
+			To:      application.User.Email,
+			Subject: "Sua loja foi aprovada",
+			Body: fmt.Sprintf("Olá, %s.\n\n"+
+				"Sua solicitação para vender foi aprovada. A loja %s já está no ar:\n\n%s/stores/%s\n\n"+
+				"Acesse o painel do vendedor para cadastrar seus produtos.\n",
+				application.User.Name, store.Name, s.frontendURL, store.Slug),
+		})
+	}
+	return application, nil
+}
+
+// Reject closes the application with a reason shown to the applicant
+func (s *sellerService) Reject(adminID string, applicationID string, reason string) (*domain.SellerApplication, error) {
+	application, err := s.reviewable(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	application.ReviewedBy = &adminID
+	application.RejectionReason = &reason
+	if err := s.storeRepo.RejectApplication(application); err != nil {
+		if errors.Is(err, repository.ErrApplicationNotPending) {
+			return nil, ErrApplicationReviewed
+		}
+		return nil, err
+	}
+
+	if application.User != nil {
+		s.sendAsync(mail.Message{
+			To:      application.User.Email,
+			Subject: "Sua solicitação para vender foi recusada",
+			Body: fmt.Sprintf("Olá, %s.\n\n"+
+				"Sua solicitação para abrir a loja %s foi recusada pelo seguinte motivo:\n\n%s\n\n"+
+				"Você pode corrigir os dados e enviar uma nova solicitação.\n",
+				application.User.Name, application.StoreName, reason),
+		})
+	}
+	return application, nil
+}
+
+// GetMyStore retrieves the seller's own store, including private fields
+func (s *sellerService) GetMyStore(sellerID string) (*domain.Store, error) {
+	store, err := s.storeRepo.GetStoreBySellerID(sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, ErrStoreNotFound
+	}
+	return store, nil
+}
+
+// UpdateMyStore edits the public profile of the seller's store.
+// The slug is kept on rename so existing links keep working.
+func (s *sellerService) UpdateMyStore(sellerID string, req *domain.UpdateStoreRequest) (*domain.Store, error) {
+	store, err := s.GetMyStore(sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.Join(strings.Fields(*req.Name), " ")
+		if domain.Slugify(name) == "" {
+			return nil, &domain.ValidationError{Fields: map[string]string{"name": "must contain letters or digits"}}
+		}
+		store.Name = name
+	}
+	if req.LogoURL != nil {
+		if logo := strings.TrimSpace(*req.LogoURL); logo != "" {
+			store.LogoURL = &logo
+		} else {
+			store.LogoURL = nil
+		}
+	}
+	if req.Description != nil {
+		store.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.ShippingPolicy != nil {
+		store.ShippingPolicy = strings.TrimSpace(*req.ShippingPolicy)
+	}
+	if req.ReturnPolicy != nil {
+		store.ReturnPolicy = strings.TrimSpace(*req.ReturnPolicy)
+	}
+
+	if err := s.storeRepo.UpdateStore(store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// GetStorePage returns a store's public profile with a page of its active products.
+// Stores of suspended sellers are not found.
+func (s *sellerService) GetStorePage(slug string, page int, perPage int, currency string) (*domain.StorePublicResponse, error) {
+	store, err := s.storeRepo.GetStoreBySlug(strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+	if store == nil || (store.Seller != nil && !store.Seller.IsActive) {
+		return nil, ErrStoreNotFound
+	}
+
+	page, perPage = normalizePage(page, perPage)
+	products, total, err := s.productRepo.FindActiveBySellerID(store.SellerID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		if err := applyDisplayPrice(s.converter, &products[i], currency); err != nil {
+			return nil, err
+		}
+	}
+	return store.ToPublicResponse(products, total), nil
+}
+
+// reviewable loads an application that is still awaiting review
+func (s *sellerService) reviewable(applicationID string) (*domain.SellerApplication, error) {
+	application, err := s.storeRepo.GetApplication(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, ErrApplicationNotFound
+	}
+	if application.Status != domain.ApplicationPending {
+		return nil, ErrApplicationReviewed
+	}
+	return application, nil
+}
+
+// uniqueSlug derives a store slug from its name, appending -2, -3... when taken
+func (s *sellerService) uniqueSlug(name string) (string, error) {
+	base := domain.Slugify(name)
+	for i := 1; i <= maxSlugAttempts; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		taken, err := s.storeRepo.SlugExists(slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+	return "", fmt.Errorf("no free slug for store %q", name)
+}
+
+// sendAsync delivers an email in the background; failures are only logged
+func (s *sellerService) sendAsync(msg mail.Message) {
+	if s.mailer == nil {
+		return
+	}
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("failed to send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrSessionNotFound is returned when the session does not exist, is no longer active or belongs to another user
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionService defines the operations users run on their own login sessions
+type SessionService interface {
+	List(userID string, currentID string) ([]domain.Session, error)
+	Rename(userID string, id string, deviceName string) (*domain.Session, error)
+	Revoke(userID string, id string, client domain.ClientInfo) error
+	RevokeOthers(userID string, currentID string, client domain.ClientInfo) error
+}
+
+type sessionService struct {
+	sessionRepo      repository.SessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditRepo        repository.AuditRepository
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(sessionRepo repository.SessionRepository, refreshTokenRepo repository.RefreshTokenRepository, auditRepo repository.AuditRepository) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		auditRepo:        auditRepo,
+	}
+}
+
+// List retrieves the user's active sessions, marking the one the request was made with
+func (s *sessionService) List(userID string, currentID string) ([]domain.Session, error) {
+	sessions, err := s.sessionRepo.ListActive(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// Rename sets the device name of a session; an empty name clears it
+func (s *sessionService) Rename(userID string, id string, deviceName string) (*domain.Session, error) {
+	session, err := s.activeSession(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var name *string
+	if trimmed := strings.TrimSpace(deviceName); trimmed != "" {
+		name = &trimmed
+	}
+	if err := s.sessionRepo.Rename(session.ID, name); err != nil {
+		return nil, err
+	}
+	session.DeviceName = name
+	return session, nil
+}
+
+// Revoke signs a session out: its refresh token stops working and its access tokens are refused
+func (s *sessionService) Revoke(userID string, id string, client domain.ClientInfo) error {
+	session, err := s.activeSession(userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeFamily(session.ID, domain.RevokedByUser); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, userID, userID, domain.AuditSessionRevoked, map[string]string{"sessionId": session.ID}, client)
+	return nil
+}
+
+// RevokeOthers signs out every session of the user but the current one
+func (s *sessionService) RevokeOthers(userID string, currentID string, client domain.ClientInfo) error {
+	if err := s.refreshTokenRepo.RevokeUserExcept(userID, currentID, domain.RevokedByUser); err != nil {
+		return err
+	}
+	recordAudit(s.auditRepo, userID, userID, domain.AuditSessionRevoked, map[string]string{"except": currentID}, client)
+	return nil
+}
+
+// activeSession loads one of the user's active sessions
+func (s *sessionService) activeSession(userID string, id string) (*domain.Session, error) {
+	session, err := s.sessionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || !session.IsActive(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
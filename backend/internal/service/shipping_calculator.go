@@ -0,0 +1,123 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"errors"
+	"math"
+	"slices"
+)
+
+// defaultItemGrams is used for products without a registered weight
+const defaultItemGrams = 500
+
+// ShippingLine describes one order line for freight calculation
+type ShippingLine struct {
+	Product  *domain.Product
+	Quantity int
+}
+
+// ShippingCalculator lists the delivery options for a set of lines.
+// Options are returned cheapest first.
+type ShippingCalculator interface {
+	Options(destination *domain.ShippingAddress, lines []ShippingLine, subtotal domain.Money) ([]domain.ShippingOption, error)
+}
+
+// flatRate is the price of one delivery method: a base fee plus a fee per kg
+type flatRate struct {
+	base          domain.Money
+	perKg         domain.Money
+	estimatedDays int
+}
+
+type flatRateMethod struct {
+	method        string
+	name          string
+	intrastate    flatRate
+	interstate    flatRate
+	international *flatRate    // nil when the method does not deliver abroad
+	freeAbove     domain.Money // Subtotal from which domestic delivery is free, zero disables
+}
+
+type flatRateShipping struct {
+	originState string
+	methods     []flatRateMethod
+}
+
+// NewFlatRateShipping creates a weight-based calculator with standard and express delivery,
+// priced by whether the destination is in the same state goods ship from. Only standard
+// delivery ships abroad, never for free.
+func NewFlatRateShipping(originState string, freeShippingThreshold domain.Money) (ShippingCalculator, error) {
+	if !domain.IsValidState(originState) {
+		return nil, errors.New("invalid shipping origin state: " + originState)
+	}
+
+	return &flatRateShipping{
+		originState: originState,
+		methods: []flatRateMethod{
+			{
+				method:        "standard",
+				name:          "Entrega padrão",
+				intrastate:    flatRate{base: domain.Cents(1590), perKg: domain.Cents(200), estimatedDays: 5},
+				interstate:    flatRate{base: domain.Cents(2490), perKg: domain.Cents(400), estimatedDays: 8},
+				international: &flatRate{base: domain.Cents(14990), perKg: domain.Cents(6000), estimatedDays: 20},
+				freeAbove:     freeShippingThreshold,
+			},
+			{
+				method:     "express",
+				name:       "Entrega expressa",
+				intrastate: flatRate{base: domain.Cents(2990), perKg: domain.Cents(400), estimatedDays: 2},
+				interstate: flatRate{base: domain.Cents(4990), perKg: domain.Cents(800), estimatedDays: 4},
+			},
+		},
+	}, nil
+}
+
+// Options prices every method for the destination
+func (s *flatRateShipping) Options(destination *domain.ShippingAddress, lines []ShippingLine, subtotal domain.Money) ([]domain.ShippingOption, error) {
+	if destination == nil {
+		return nil, errors.New("a destination is required to quote shipping")
+	}
+	domestic := destination.IsDomestic()
+	if domestic && !domain.IsValidState(destination.State) {
+		return nil, errors.New("a valid destination state is required to quote shipping")
+	}
+
+	// Product.Weight is in kg; freight is priced per gram to keep the arithmetic in integers
+	var grams int64
+	for _, line := range lines {
+		itemGrams := int64(defaultItemGrams)
+		if line.Product != nil && line.Product.Weight != nil && *line.Product.Weight > 0 {
+			itemGrams = int64(math.Round(*line.Product.Weight * 1000))
+		}
+		grams += itemGrams * int64(line.Quantity)
+	}
+
+	options := make([]domain.ShippingOption, 0, len(s.methods))
+	for _, m := range s.methods {
+		rate := m.interstate
+		switch {
+		case !domestic && m.international == nil:
+			continue
+		case !domestic:
+			rate = *m.international
+		case destination.State == s.originState:
+			rate = m.intrastate
+		}
+
+		price := rate.base.Add(rate.perKg.MulRatio(grams, 1000))
+		if domestic && !m.freeAbove.IsZero() && subtotal.Cmp(m.freeAbove) >= 0 {
+			price = domain.NewMoney(0, price.Currency)
+		}
+		options = append(options, domain.ShippingOption{
+			Method:        m.method,
+			Name:          m.name,
+			Price:         price,
+			EstimatedDays: rate.estimatedDays,
+		})
+	}
+
+	slices.SortStableFunc(options, func(a, b domain.ShippingOption) int {
+		return a.Price.Cmp(b.Price)
+	})
+	return options, nil
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/oidc"
+	"ecommerce/internal/repository"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+const oidcStateTTL = 10 * time.Minute // How long the provider's redirect can take to come back
+
+var (
+	// ErrUnknownProvider is returned for social login providers that are not configured
+	ErrUnknownProvider = errors.New("unknown login provider")
+	// ErrInvalidOIDCState is returned for unknown, expired or already used authorization states
+	ErrInvalidOIDCState = errors.New("invalid or expired login state, start again")
+	// ErrSocialLoginFailed is returned when the provider refuses the code or its ID token is invalid
+	ErrSocialLoginFailed = errors.New("the provider did not confirm the login")
+	// ErrIdentityEmailMissing is returned when a new identity has no email to create the account with
+	ErrIdentityEmailMissing = errors.New("the provider did not share an email address")
+	// ErrIdentityEmailUnverified is returned when the identity's email belongs to an account but the
+	// provider did not verify it, so it cannot be linked automatically
+	ErrIdentityEmailUnverified = errors.New("an account with this email exists, log in with your password to use it")
+)
+
+// SocialLoginService defines OIDC login operations.
+// An identity is matched by provider and subject; a new identity is linked to the account
+// with the same email when the provider verified it, or gets a new customer account.
+type SocialLoginService interface {
+	Providers() []string
+	Start(provider string) (*domain.OIDCAuthorization, error)
+	Callback(provider string, req *domain.OIDCCallbackRequest, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error)
+	ListIdentities(userID string) ([]domain.UserIdentity, error)
+}
+
+type socialLoginService struct {
+	providers    map[string]*oidc.Provider
+	identityRepo repository.IdentityRepository
+	userRepo     repository.UserRepository
+	auditRepo    repository.AuditRepository
+	authService  AuthService
+	redirectURL  string
+}
+
+// NewSocialLoginService creates a new social login service.
+// redirectURL is the frontend page providers send the browser back to.
+func NewSocialLoginService(providers []*oidc.Provider, identityRepo repository.IdentityRepository, userRepo repository.UserRepository, auditRepo repository.AuditRepository, authService AuthService, redirectURL string) SocialLoginService {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &socialLoginService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		auditRepo:    auditRepo,
+		authService:  authService,
+		redirectURL:  redirectURL,
+	}
+}
+
+// Providers lists the configured provider names
+func (s *socialLoginService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start stores a new state, nonce and PKCE verifier and returns the provider's authorization URL
+func (s *socialLoginService) Start(provider string) (*domain.OIDCAuthorization, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	stateValue, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(s.redirectURL, stateValue, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	state := &domain.OIDCLoginState{
+		Provider:     provider,
+		StateHash:    hashToken(stateValue),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := s.identityRepo.CreateState(state); err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCAuthorization{
+		Provider:         provider,
+		AuthorizationURL: authURL,
+		ExpiresAt:        state.ExpiresAt,
+	}, nil
+}
+
+// Callback redeems the provider's code, verifies the ID token and signs the linked user in.
+// The state is used up by the first attempt.
+func (s *socialLoginService) Callback(provider string, req *domain.OIDCCallbackRequest, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, nil, ErrUnknownProvider
+	}
+
+	state, err := s.identityRepo.ConsumeState(hashToken(req.State))
+	if err != nil {
+		return nil, nil, err
+	}
+	if state == nil || state.Provider != provider || !time.Now().Before(state.ExpiresAt) {
+		return nil, nil, ErrInvalidOIDCState
+	}
+
+	claims, err := p.Exchange(req.Code, state.CodeVerifier, s.redirectURL, state.Nonce)
+	if err != nil {
+		log.Printf("%s login failed: %v", provider, err)
+		return nil, nil, ErrSocialLoginFailed
+	}
+
+	user, err := s.resolveUser(provider, claims, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.authService.SignIn(user, client)
+}
+
+// ListIdentities retrieves the identities linked to a user
+func (s *socialLoginService) ListIdentities(userID string) ([]domain.UserIdentity, error) {
+	return s.identityRepo.ListByUser(userID)
+}
+
+// resolveUser finds the user of an identity, linking it to an account on first login
+func (s *socialLoginService) resolveUser(provider string, claims *oidc.Claims, client domain.ClientInfo) (*domain.User, error) {
+	identity, err := s.identityRepo.Get(provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || user.ErasedAt != nil {
+			return nil, ErrSocialLoginFailed
+		}
+		if err := s.identityRepo.TouchLogin(identity.ID); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" {
+		return nil, ErrIdentityEmailMissing
+	}
+	user, err := s.userRepo.GetByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	details := map[string]string{"provider": provider}
+	switch {
+	case user != nil && !claims.IsEmailVerified():
+		return nil, ErrIdentityEmailUnverified
+	case user != nil:
+		// The provider proved control of the mailbox, which is also what our own verification link proves.
+		// An unverified account may have been registered by someone else with that address: the
+		// mailbox owner takes it over (see IdentityRepository.ClaimAccount).
+		if user.EmailVerifiedAt == nil {
+			if err := s.identityRepo.ClaimAccount(user.ID, now); err != nil {
+				return nil, err
+			}
+			if user, err = s.userRepo.GetByID(user.ID); err != nil {
+				return nil, err
+			}
+			details["credentialsReset"] = "true"
+		}
+	default:
+		// Social accounts have no password until the user sets one through /api/auth/forgot-password
+		user = &domain.User{
+			Name:     socialName(claims.Name, email),
+			Email:    email,
+			Role:     domain.RoleCustomer,
+			IsActive: true,
+		}
+		if claims.IsEmailVerified() {
+			user.EmailVerifiedAt = &now
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, err
+		}
+	}
+
+	identity = &domain.UserIdentity{
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		return nil, err
+	}
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditIdentityLinked, details, client)
+	return user, nil
+}
+
+// socialName picks the display name of a new social account
+func socialName(name string, email string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+	return truncate(name, 255)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/oidc"
+	"ecommerce/internal/repository"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// signInRecorder stands in for AuthService, recording who social login signs in
+type signInRecorder struct {
+	AuthService
+	user *domain.User
+}
+
+func (a *signInRecorder) SignIn(user *domain.User, client domain.ClientInfo) (*domain.LoginResponse, *domain.LoginChallenge, error) {
+	a.user = user
+	return &domain.LoginResponse{}, nil, nil
+}
+
+type socialLoginFixture struct {
+	service  SocialLoginService
+	auth     *signInRecorder
+	db       *gorm.DB
+	userRepo repository.UserRepository
+}
+
+// newSocialLoginFixture wires the service to an embedded local OIDC provider and a sqlite database
+func newSocialLoginFixture(t *testing.T) *socialLoginFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	err = db.AutoMigrate(&domain.User{}, &domain.UserIdentity{}, &domain.OIDCLoginState{}, &domain.RefreshToken{}, &domain.Session{},
+		&domain.AuditLog{}, &domain.RecoveryCode{}, &domain.APIKey{}, &domain.StoreMember{})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	var local *oidc.LocalProvider
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		local.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	if local, err = oidc.NewLocalProvider(server.URL); err != nil {
+		t.Fatalf("NewLocalProvider: %v", err)
+	}
+
+	f := &socialLoginFixture{auth: &signInRecorder{}, db: db, userRepo: repository.NewUserRepository(db)}
+	f.service = NewSocialLoginService(
+		[]*oidc.Provider{oidc.NewProvider(local.Config(), server.Client())},
+		repository.NewIdentityRepository(db),
+		f.userRepo,
+		repository.NewAuditRepository(db),
+		f.auth,
+		"http://app.test/auth/callback/local",
+	)
+	return f
+}
+
+// authorize starts a login and signs in at the provider as email, returning the callback parameters
+func (f *socialLoginFixture) authorize(t *testing.T, email string) *domain.OIDCCallbackRequest {
+	t.Helper()
+	start, err := f.service.Start("local")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(start.AuthorizationURL + "&login_hint=" + url.QueryEscape(email))
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d, location %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	return &domain.OIDCCallbackRequest{Code: location.Query().Get("code"), State: location.Query().Get("state")}
+}
+
+// login runs the whole flow as email and returns the user signed in
+func (f *socialLoginFixture) login(t *testing.T, email string) *domain.User {
+	t.Helper()
+	if _, _, err := f.service.Callback("local", f.authorize(t, email), domain.ClientInfo{}); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	return f.auth.user
+}
+
+func TestSocialLoginCreatesAccount(t *testing.T) {
+	f := newSocialLoginFixture(t)
+
+	user := f.login(t, "ana@example.com")
+	if user.Email != "ana@example.com" || user.Role != domain.RoleCustomer || !user.IsEmailVerified() {
+		t.Errorf("signed in %+v, want a verified customer ana@example.com", user)
+	}
+
+	again := f.login(t, "ana@example.com")
+	if again.ID != user.ID {
+		t.Errorf("second login signed in %s, want the linked account %s", again.ID, user.ID)
+	}
+}
+
+func TestSocialLoginRejectsReusedOrForeignState(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	req := f.authorize(t, "ana@example.com")
+
+	forged := &domain.OIDCCallbackRequest{Code: req.Code, State: "forged"}
+	if _, _, err := f.service.Callback("local", forged, domain.ClientInfo{}); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("forged state: error = %v, want ErrInvalidOIDCState", err)
+	}
+
+	if _, _, err := f.service.Callback("local", req, domain.ClientInfo{}); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if _, _, err := f.service.Callback("local", req, domain.ClientInfo{}); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("reused state: error = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestSocialLoginLinksVerifiedAccount(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	verifiedAt := time.Now()
+	existing := &domain.User{Name: "Ana", Email: "ana@example.com", PasswordHash: "hash", Role: domain.RoleSeller, IsActive: true, EmailVerifiedAt: &verifiedAt}
+	if err := f.userRepo.Create(existing); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	user := f.login(t, "ana@example.com")
+	if user.ID != existing.ID || user.Role != domain.RoleSeller {
+		t.Fatalf("signed in %+v, want the existing seller account", user)
+	}
+	stored, _ := f.userRepo.GetByID(existing.ID)
+	if stored.PasswordHash != "hash" {
+		t.Error("linking a verified account cleared its password")
+	}
+}
+
+func TestSocialLoginTakesOverUnverifiedAccount(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	squatter := &domain.User{Name: "Someone", Email: "ana@example.com", PasswordHash: "hash", Role: domain.RoleCustomer, IsActive: true}
+	if err := f.userRepo.Create(squatter); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	// What the squatter set up: a session, an identity of a provider that did not verify the
+	// email, recovery codes, an API key and a store membership
+	setup := []interface{}{
+		&domain.RefreshToken{UserID: squatter.ID, TokenHash: "token-hash", ExpiresAt: time.Now().Add(time.Hour)},
+		&domain.UserIdentity{UserID: squatter.ID, Provider: "other", Subject: "squatter", Email: "ana@example.com"},
+		&domain.RecoveryCode{UserID: squatter.ID, CodeHash: "code-hash"},
+		&domain.APIKey{UserID: squatter.ID, Name: "key", Prefix: "sk_squatter", KeyHash: "key-hash"},
+		&domain.StoreMember{StoreID: "store-1", Email: "ana@example.com", UserID: &squatter.ID, Role: domain.StoreRoleManager, AcceptedAt: &time.Time{}},
+	}
+	for _, row := range setup {
+		if err := f.db.Create(row).Error; err != nil {
+			t.Fatalf("create %T: %v", row, err)
+		}
+	}
+	f.db.Model(squatter).Updates(map[string]interface{}{"totp_secret": "SECRET", "two_factor_enabled_at": time.Now()})
+
+	user := f.login(t, "ana@example.com")
+	if user.ID != squatter.ID {
+		t.Fatalf("signed in %s, want the account with the email %s", user.ID, squatter.ID)
+	}
+
+	stored, _ := f.userRepo.GetByID(squatter.ID)
+	if stored.PasswordHash != "" || stored.HasTwoFactor() || !stored.IsEmailVerified() {
+		t.Errorf("account after takeover: password hash %q, 2FA %v, verified %v; want no password, no 2FA and a verified email",
+			stored.PasswordHash, stored.HasTwoFactor(), stored.IsEmailVerified())
+	}
+	if user.PasswordHash != "" {
+		t.Error("the user signed in still carries the squatter's password")
+	}
+
+	var token domain.RefreshToken
+	f.db.First(&token, "user_id = ?", squatter.ID)
+	if token.RevokedAt == nil || token.RevokedReason != domain.RevokedIdentityLinked {
+		t.Errorf("refresh token after takeover: revoked at %v, reason %q; want revoked as %q", token.RevokedAt, token.RevokedReason, domain.RevokedIdentityLinked)
+	}
+	var key domain.APIKey
+	f.db.First(&key, "user_id = ?", squatter.ID)
+	if key.RevokedAt == nil {
+		t.Error("the squatter's API key is still active")
+	}
+
+	var identities []domain.UserIdentity
+	f.db.Where("user_id = ?", squatter.ID).Find(&identities)
+	if len(identities) != 1 || identities[0].Provider != "local" {
+		t.Errorf("identities after takeover: %+v, want only the mailbox owner's local identity", identities)
+	}
+	for _, model := range []interface{}{&domain.RecoveryCode{}, &domain.StoreMember{}} {
+		var count int64
+		f.db.Model(model).Where("user_id = ?", squatter.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("%T rows left after takeover: %d, want 0", model, count)
+		}
+	}
+}
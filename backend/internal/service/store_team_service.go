@@ -0,0 +1,337 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"ecommerce/internal/mail"
+	"ecommerce/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// memberRoleProblem is the validation message for an invalid team member role
+const memberRoleProblem = "must be manager, catalog_editor, fulfilment or analyst"
+
+var (
+	// ErrStoreAccessDenied is returned when the user is neither the owner nor a member of the requested store
+	ErrStoreAccessDenied = errors.New("you are not a member of this store")
+	// ErrStoreMemberNotFound is returned when the member or invitation does not exist in the store
+	ErrStoreMemberNotFound = errors.New("store member not found")
+	// ErrStoreMemberExists is returned when the email is already a member or invited
+	ErrStoreMemberExists = errors.New("this email is already a member of the store or invited to it")
+	// ErrStoreTeamFull is returned when the store reached MaxStoreMembers
+	ErrStoreTeamFull = fmt.Errorf("a store can have at most %d members and invitations", domain.MaxStoreMembers)
+	// ErrInvitationNotFound is returned when the invitation does not exist or is addressed to another email
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationEmailNotVerified is returned when accepting an invitation before verifying the account's email
+	ErrInvitationEmailNotVerified = errors.New("verify your email address before accepting invitations")
+)
+
+// StoreTeamService defines store team operations: which store a request acts for, the
+// owner's and managers' management of members, and invited users' side of invitations
+type StoreTeamService interface {
+	ResolveAccess(user *domain.User, storeID string) (*domain.StoreAccess, error)
+	ListMyStores(user *domain.User) ([]domain.StoreAccess, error)
+	LeaveStore(user *domain.User, storeID string) error
+	ListMembers(storeID string) ([]domain.StoreMember, error)
+	Invite(access *domain.StoreAccess, inviter *domain.User, req *domain.InviteStoreMemberRequest) (*domain.StoreMember, error)
+	ChangeRole(storeID string, memberID string, role string) (*domain.StoreMember, error)
+	RemoveMember(storeID string, memberID string) error
+	ListInvitations(user *domain.User) ([]domain.StoreInvitation, error)
+	AcceptInvitation(user *domain.User, invitationID string) (*domain.StoreAccess, error)
+	DeclineInvitation(user *domain.User, invitationID string) error
+}
+
+type storeTeamService struct {
+	memberRepo  repository.StoreMemberRepository
+	storeRepo   repository.StoreRepository
+	userRepo    repository.UserRepository
+	mailer      mail.Mailer
+	frontendURL string
+}
+
+// NewStoreTeamService creates a new store team service.
+// Invitations are emailed with a link to frontendURL.
+func NewStoreTeamService(memberRepo repository.StoreMemberRepository, storeRepo repository.StoreRepository, userRepo repository.UserRepository, mailer mail.Mailer, frontendURL string) StoreTeamService {
+	return &storeTeamService{
+		memberRepo:  memberRepo,
+		storeRepo:   storeRepo,
+		userRepo:    userRepo,
+		mailer:      mailer,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// ResolveAccess returns the store the user acts for. An empty storeID picks the seller's
+// own store, or else the only store the user is a member of; nil means there is none to
+// pick. Stores of suspended sellers cannot be acted for by their members.
+func (s *storeTeamService) ResolveAccess(user *domain.User, storeID string) (*domain.StoreAccess, error) {
+	if storeID == "" {
+		if user.Role == domain.RoleSeller {
+			store, err := s.storeRepo.GetStoreBySellerID(user.ID)
+			if err != nil {
+				return nil, err
+			}
+			if store != nil {
+				return domain.NewStoreAccess(store, domain.StoreRoleOwner), nil
+			}
+		}
+		stores, err := s.memberStores(user.ID)
+		if err != nil || len(stores) != 1 {
+			return nil, err
+		}
+		return &stores[0], nil
+	}
+
+	store, err := s.storeRepo.GetStoreByID(storeID)
+	if err != nil {
+		return nil, err
+	}
+	if store == nil {
+		return nil, ErrStoreAccessDenied
+	}
+	if store.SellerID == user.ID {
+		if user.Role != domain.RoleSeller {
+			return nil, ErrStoreAccessDenied
+		}
+		return domain.NewStoreAccess(store, domain.StoreRoleOwner), nil
+	}
+	member, err := s.memberRepo.GetAccepted(store.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || !sellerActive(store) {
+		return nil, ErrStoreAccessDenied
+	}
+	return domain.NewStoreAccess(store, member.Role), nil
+}
+
+// ListMyStores lists the stores the user can act for: their own first, then memberships
+func (s *storeTeamService) ListMyStores(user *domain.User) ([]domain.StoreAccess, error) {
+	stores := []domain.StoreAccess{}
+	if user.Role == domain.RoleSeller {
+		store, err := s.storeRepo.GetStoreBySellerID(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if store != nil {
+			stores = append(stores, *domain.NewStoreAccess(store, domain.StoreRoleOwner))
+		}
+	}
+	memberships, err := s.memberStores(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return append(stores, memberships...), nil
+}
+
+// LeaveStore ends the user's membership of a store
+func (s *storeTeamService) LeaveStore(user *domain.User, storeID string) error {
+	member, err := s.memberRepo.GetAccepted(storeID, user.ID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrStoreAccessDenied
+	}
+	return s.memberRepo.Delete(member.ID)
+}
+
+// ListMembers lists a store's members and pending invitations
+func (s *storeTeamService) ListMembers(storeID string) ([]domain.StoreMember, error) {
+	return s.memberRepo.ListByStore(storeID)
+}
+
+// Invite records an invitation for an email and emails it. The account with that email
+// accepts it, whether it exists already or is created later.
+func (s *storeTeamService) Invite(access *domain.StoreAccess, inviter *domain.User, req *domain.InviteStoreMemberRequest) (*domain.StoreMember, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	problems := domain.NewValidationError()
+	if !domain.IsValidMemberRole(req.Role) {
+		problems.Add("role", memberRoleProblem)
+	}
+	owner, err := s.userRepo.GetByID(access.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil && strings.EqualFold(owner.Email, email) {
+		problems.Add("email", "is the store owner's")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.memberRepo.GetByStoreAndEmail(access.StoreID, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrStoreMemberExists
+	}
+	count, err := s.memberRepo.CountByStore(access.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= domain.MaxStoreMembers {
+		return nil, ErrStoreTeamFull
+	}
+
+	member := &domain.StoreMember{
+		StoreID:     access.StoreID,
+		Email:       email,
+		Role:        req.Role,
+		InvitedByID: inviter.ID,
+	}
+	if err := s.memberRepo.Create(member); err != nil {
+		return nil, err
+	}
+
+	s.sendAsync(mail.Message{
+		To:      email,
+		Subject: fmt.Sprintf("Convite para a equipe da loja %s", access.Name),
+		Body: fmt.Sprintf("Olá.\n\n"+
+			"%s convidou você para fazer parte da equipe da loja %s.\n\n"+
+			"Entre (ou crie sua conta) com este email para aceitar o convite:\n\n%s/seller/invitations\n",
+			inviter.Name, access.Name, s.frontendURL),
+	})
+	return member, nil
+}
+
+// ChangeRole gives a member or invitation another store role
+func (s *storeTeamService) ChangeRole(storeID string, memberID string, role string) (*domain.StoreMember, error) {
+	if !domain.IsValidMemberRole(role) {
+		problems := domain.NewValidationError()
+		problems.Add("role", memberRoleProblem)
+		return nil, problems
+	}
+	member, err := s.storeMember(storeID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	member.Role = role
+	member.UpdatedAt = time.Now()
+	if err := s.memberRepo.Update(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember removes a member from the store, or cancels an invitation
+func (s *storeTeamService) RemoveMember(storeID string, memberID string) error {
+	member, err := s.storeMember(storeID, memberID)
+	if err != nil {
+		return err
+	}
+	return s.memberRepo.Delete(member.ID)
+}
+
+// ListInvitations lists the invitations addressed to the user's email
+func (s *storeTeamService) ListInvitations(user *domain.User) ([]domain.StoreInvitation, error) {
+	members, err := s.memberRepo.ListPendingByEmail(strings.ToLower(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	invitations := make([]domain.StoreInvitation, 0, len(members))
+	for _, member := range members {
+		if member.Store == nil {
+			continue
+		}
+		invitations = append(invitations, domain.StoreInvitation{
+			ID:        member.ID,
+			StoreID:   member.StoreID,
+			StoreName: member.Store.Name,
+			StoreSlug: member.Store.Slug,
+			Role:      member.Role,
+			CreatedAt: member.CreatedAt,
+		})
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation makes the user a member of the inviting store. The account's email
+// must be verified: otherwise anyone could register with the invited address.
+func (s *storeTeamService) AcceptInvitation(user *domain.User, invitationID string) (*domain.StoreAccess, error) {
+	member, err := s.invitation(user, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsEmailVerified() {
+		return nil, ErrInvitationEmailNotVerified
+	}
+
+	now := time.Now()
+	member.UserID = &user.ID
+	member.AcceptedAt = &now
+	member.UpdatedAt = now
+	if err := s.memberRepo.Update(member); err != nil {
+		return nil, err
+	}
+	return domain.NewStoreAccess(member.Store, member.Role), nil
+}
+
+// DeclineInvitation deletes an invitation addressed to the user
+func (s *storeTeamService) DeclineInvitation(user *domain.User, invitationID string) error {
+	member, err := s.invitation(user, invitationID)
+	if err != nil {
+		return err
+	}
+	return s.memberRepo.Delete(member.ID)
+}
+
+// memberStores lists the stores the user is a member of, leaving out those of suspended or demoted sellers
+func (s *storeTeamService) memberStores(userID string) ([]domain.StoreAccess, error) {
+	members, err := s.memberRepo.ListAcceptedByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	stores := make([]domain.StoreAccess, 0, len(members))
+	for _, member := range members {
+		if member.Store != nil && sellerActive(member.Store) {
+			stores = append(stores, *domain.NewStoreAccess(member.Store, member.Role))
+		}
+	}
+	return stores, nil
+}
+
+// sellerActive reports whether the store's owner is still an active seller, so its team can act for it
+func sellerActive(store *domain.Store) bool {
+	return store.Seller != nil && store.Seller.IsActive && store.Seller.Role == domain.RoleSeller
+}
+
+// storeMember loads a member or invitation of the store
+func (s *storeTeamService) storeMember(storeID string, memberID string) (*domain.StoreMember, error) {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.StoreID != storeID {
+		return nil, ErrStoreMemberNotFound
+	}
+	return member, nil
+}
+
+// invitation loads a pending invitation addressed to the user's email
+func (s *storeTeamService) invitation(user *domain.User, invitationID string) (*domain.StoreMember, error) {
+	member, err := s.memberRepo.GetByID(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || !member.IsPending() || member.Store == nil || !strings.EqualFold(member.Email, user.Email) {
+		return nil, ErrInvitationNotFound
+	}
+	return member, nil
+}
+
+// sendAsync delivers mail in the background; failures are logged
+func (s *storeTeamService) sendAsync(msg mail.Message) {
+	if s.mailer == nil {
+		return
+	}
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("failed to send %q to %s: %v", msg.Subject, msg.To, err)
+		}
+	}()
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"ecommerce/internal/domain"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// TaxInput describes one order line to be taxed
+type TaxInput struct {
+	Product          *domain.Product
+	Quantity         int
+	UnitPrice        domain.Money
+	OriginState      string // Empty uses the calculator's default origin
+	DestinationState string
+}
+
+// TaxCalculator computes the taxes contained in an order line
+type TaxCalculator interface {
+	Calculate(line TaxInput) (*domain.TaxBreakdown, error)
+}
+
+//go:embed data/icms_rates.json
+var bundledICMSRates []byte
+
+// ICMSRateTable is the data-driven rate configuration for ICMS.
+// All rates are percentages (18 means 18%).
+type ICMSRateTable struct {
+	Version       string             `json:"version"`
+	InternalRates map[string]float64 `json:"internal_rates"` // Alíquota interna por UF
+	FCPRates      map[string]float64 `json:"fcp_rates"`      // Fundo de Combate à Pobreza por UF de destino
+	Interstate    struct {
+		DefaultRate         float64  `json:"default_rate"`
+		ReducedRate         float64  `json:"reduced_rate"` // S/SE (except ES) to N/NE/CO/ES
+		ReducedOrigins      []string `json:"reduced_origins"`
+		ReducedDestinations []string `json:"reduced_destinations"`
+		ImportedRate        float64  `json:"imported_rate"` // Resolução SF 13/2012
+		ImportedOrigins     []int    `json:"imported_origins"`
+	} `json:"interstate"`
+	// NCMInternalRates overrides internal rates by NCM prefix; the longest prefix wins
+	NCMInternalRates map[string]map[string]float64 `json:"ncm_internal_rates"`
+}
+
+// LoadICMSRateTable parses and sanity-checks a rate table
+func LoadICMSRateTable(r io.Reader) (*ICMSRateTable, error) {
+	var table ICMSRateTable
+	if err := json.NewDecoder(r).Decode(&table); err != nil {
+		return nil, fmt.Errorf("invalid ICMS rate table: %w", err)
+	}
+
+	for uf := range domain.BrazilianStates {
+		if _, ok := table.InternalRates[uf]; !ok {
+			return nil, errors.New("ICMS rate table is missing the internal rate for " + uf)
+		}
+	}
+	if table.Interstate.DefaultRate <= 0 {
+		return nil, errors.New("ICMS rate table is missing the default interstate rate")
+	}
+	return &table, nil
+}
+
+// BundledICMSRateTable returns the rate table shipped with the binary
+func BundledICMSRateTable() (*ICMSRateTable, error) {
+	return LoadICMSRateTable(bytes.NewReader(bundledICMSRates))
+}
+
+type icmsCalculator struct {
+	table         *ICMSRateTable
+	defaultOrigin string
+}
+
+// NewICMSCalculator creates a calculator for sales to final consumers (non-contributors),
+// applying the interstate rate plus DIFAL (EC 87/2015) when origin and destination differ
+func NewICMSCalculator(table *ICMSRateTable, defaultOrigin string) (TaxCalculator, error) {
+	origin := strings.ToUpper(defaultOrigin)
+	if !domain.IsValidState(origin) {
+		return nil, errors.New("invalid ICMS origin state: " + defaultOrigin)
+	}
+	return &icmsCalculator{table: table, defaultOrigin: origin}, nil
+}
+
+// Calculate computes ICMS, DIFAL and FCP for one line
+func (c *icmsCalculator) Calculate(line TaxInput) (*domain.TaxBreakdown, error) {
+	origin := strings.ToUpper(line.OriginState)
+	if origin == "" {
+		origin = c.defaultOrigin
+	}
+	destination := strings.ToUpper(line.DestinationState)
+	if !domain.IsValidState(origin) || !domain.IsValidState(destination) {
+		return nil, fmt.Errorf("cannot compute ICMS from %q to %q", origin, destination)
+	}
+
+	ncm := ""
+	taxOrigin := domain.TaxOriginNational
+	if line.Product != nil {
+		ncm = line.Product.NCM
+		taxOrigin = line.Product.TaxOrigin
+	}
+
+	base := line.UnitPrice.Mul(int64(line.Quantity))
+	destinationRate := c.internalRate(destination, ncm)
+	breakdown := &domain.TaxBreakdown{
+		NCM:              ncm,
+		OriginState:      origin,
+		DestinationState: destination,
+		Base:             base,
+	}
+
+	if origin == destination {
+		breakdown.ICMSRate = destinationRate
+	} else {
+		breakdown.ICMSRate = c.interstateRate(origin, destination, taxOrigin)
+		breakdown.DIFALRate = roundRate(math.Max(destinationRate-breakdown.ICMSRate, 0))
+	}
+	breakdown.FCPRate = c.table.FCPRates[destination]
+
+	breakdown.ICMSAmount = base.Percent(breakdown.ICMSRate)
+	breakdown.DIFALAmount = base.Percent(breakdown.DIFALRate)
+	breakdown.FCPAmount = base.Percent(breakdown.FCPRate)
+	breakdown.TotalTax = breakdown.ICMSAmount.Add(breakdown.DIFALAmount).Add(breakdown.FCPAmount)
+
+	return breakdown, nil
+}
+
+// internalRate returns the destination's internal rate, honouring NCM overrides
+func (c *icmsCalculator) internalRate(uf, ncm string) float64 {
+	bestLen := -1
+	rate := c.table.InternalRates[uf]
+	for prefix, rates := range c.table.NCMInternalRates {
+		override, ok := rates[uf]
+		if ok && strings.HasPrefix(ncm, prefix) && len(prefix) > bestLen {
+			bestLen = len(prefix)
+			rate = override
+		}
+	}
+	return rate
+}
+
+// interstateRate returns the rate due to the origin state on an interstate sale
+func (c *icmsCalculator) interstateRate(origin, destination string, taxOrigin int) float64 {
+	rules := c.table.Interstate
+	if rules.ImportedRate > 0 && slices.Contains(rules.ImportedOrigins, taxOrigin) {
+		return rules.ImportedRate
+	}
+	if slices.Contains(rules.ReducedOrigins, origin) && slices.Contains(rules.ReducedDestinations, destination) {
+		return rules.ReducedRate
+	}
+	return rules.DefaultRate
+}
+
+// roundRate rounds a percentage to two decimal places
+func roundRate(rate float64) float64 {
+	return math.Round(rate*100) / 100
+}
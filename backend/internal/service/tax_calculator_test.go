@@ -0,0 +1,136 @@
+package service
+
+import (
+	"ecommerce/internal/domain"
+	"testing"
+)
+
+// newTestICMSCalculator returns a calculator from SP over the bundled table with the rates
+// of the states under test pinned, so the expectations do not follow rate updates
+func newTestICMSCalculator(t *testing.T) TaxCalculator {
+	t.Helper()
+	table, err := BundledICMSRateTable()
+	if err != nil {
+		t.Fatalf("BundledICMSRateTable: %v", err)
+	}
+	table.InternalRates["SP"] = 18
+	table.InternalRates["RJ"] = 20
+	table.InternalRates["BA"] = 20.5
+	table.InternalRates["PR"] = 10
+	table.FCPRates = map[string]float64{"RJ": 2}
+	table.NCMInternalRates = map[string]map[string]float64{
+		"22":   {"SP": 22},
+		"2203": {"SP": 25},
+	}
+
+	calculator, err := NewICMSCalculator(table, "SP")
+	if err != nil {
+		t.Fatalf("NewICMSCalculator: %v", err)
+	}
+	return calculator
+}
+
+func TestICMSCalculator(t *testing.T) {
+	calculator := newTestICMSCalculator(t)
+
+	tests := []struct {
+		name                         string
+		product                      *domain.Product
+		quantity                     int
+		unitPrice                    int64
+		origin, destination          string
+		icmsRate, difalRate, fcpRate float64
+		icms, difal, fcp, total      int64
+	}{
+		{
+			name: "same state rounds half a cent up", quantity: 1, unitPrice: 25, destination: "SP",
+			icmsRate: 18, icms: 5, total: 5, // 0.045
+		},
+		{
+			name: "same state rounds below half a cent down", quantity: 3, unitPrice: 3333, destination: "SP",
+			icmsRate: 18, icms: 1800, total: 1800, // 17.9982
+		},
+		{
+			name: "interstate default rate with DIFAL and FCP", quantity: 1, unitPrice: 1005, destination: "rj",
+			icmsRate: 12, difalRate: 8, fcpRate: 2,
+			icms: 121, difal: 80, fcp: 20, total: 221, // 1.206, 0.804, 0.201
+		},
+		{
+			name: "interstate reduced rate to the northeast", quantity: 2, unitPrice: 37, destination: "BA",
+			icmsRate: 7, difalRate: 13.5,
+			icms: 5, difal: 10, total: 15, // 0.0518, 0.0999
+		},
+		{
+			name: "imported goods pay the 4% interstate rate", product: &domain.Product{TaxOrigin: domain.TaxOriginForeignDirect},
+			quantity: 1, unitPrice: 1000, destination: "RJ",
+			icmsRate: 4, difalRate: 16, fcpRate: 2,
+			icms: 40, difal: 160, fcp: 20, total: 220,
+		},
+		{
+			name: "DIFAL is never negative", quantity: 1, unitPrice: 1000, origin: "RJ", destination: "PR",
+			icmsRate: 12, icms: 120, total: 120,
+		},
+		{
+			name: "longest NCM prefix wins", product: &domain.Product{NCM: "22030000"},
+			quantity: 1, unitPrice: 10000, destination: "SP",
+			icmsRate: 25, icms: 2500, total: 2500,
+		},
+		{
+			name: "shorter NCM prefix applies when the longer does not match", product: &domain.Product{NCM: "22021000"},
+			quantity: 1, unitPrice: 10000, destination: "SP",
+			icmsRate: 22, icms: 2200, total: 2200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calculator.Calculate(TaxInput{
+				Product:          tt.product,
+				Quantity:         tt.quantity,
+				UnitPrice:        domain.Cents(tt.unitPrice),
+				OriginState:      tt.origin,
+				DestinationState: tt.destination,
+			})
+			if err != nil {
+				t.Fatalf("Calculate: %v", err)
+			}
+			if got.ICMSRate != tt.icmsRate || got.DIFALRate != tt.difalRate || got.FCPRate != tt.fcpRate {
+				t.Errorf("rates = ICMS %v, DIFAL %v, FCP %v; want %v, %v, %v",
+					got.ICMSRate, got.DIFALRate, got.FCPRate, tt.icmsRate, tt.difalRate, tt.fcpRate)
+			}
+			if got.ICMSAmount.Amount != tt.icms || got.DIFALAmount.Amount != tt.difal || got.FCPAmount.Amount != tt.fcp || got.TotalTax.Amount != tt.total {
+				t.Errorf("amounts = ICMS %d, DIFAL %d, FCP %d, total %d; want %d, %d, %d, %d",
+					got.ICMSAmount.Amount, got.DIFALAmount.Amount, got.FCPAmount.Amount, got.TotalTax.Amount,
+					tt.icms, tt.difal, tt.fcp, tt.total)
+			}
+			if want := tt.unitPrice * int64(tt.quantity); got.Base.Amount != want {
+				t.Errorf("base = %d, want %d", got.Base.Amount, want)
+			}
+		})
+	}
+}
+
+func TestICMSCalculatorRejectsUnknownStates(t *testing.T) {
+	calculator := newTestICMSCalculator(t)
+	for _, destination := range []string{"", "XX"} {
+		if _, err := calculator.Calculate(TaxInput{Quantity: 1, UnitPrice: domain.Cents(100), DestinationState: destination}); err == nil {
+			t.Errorf("Calculate to %q succeeded, want an error", destination)
+		}
+	}
+}
+
+func TestRoundRate(t *testing.T) {
+	tests := []struct {
+		rate, want float64
+	}{
+		{8, 8},
+		{20.5 - 7, 13.5},
+		{17.999999, 18},
+		{0.125, 0.13},
+		{0.1 + 0.2, 0.3},
+	}
+	for _, tt := range tests {
+		if got := roundRate(tt.rate); got != tt.want {
+			t.Errorf("roundRate(%v) = %v, want %v", tt.rate, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors ("12345678901234567890"), base32 encoded
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	// RFC 6238 appendix B, truncated to six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/30); got != tt.want {
+			t.Errorf("totpCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	// 1111111111 is step 37037037, whose code is 050471; the step before has 081804
+	now := time.Unix(1111111111, 0)
+	key, _ := totpEncoding.DecodeString(rfc6238Secret)
+	const current = int64(37037037)
+	codeAt := func(step int64) string { return totpCode(key, step) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfc6238Secret, "050471", 0, current, true},
+		{"previous step within skew", rfc6238Secret, "081804", 0, current - 1, true},
+		{"next step within skew", rfc6238Secret, codeAt(current + 1), 0, current + 1, true},
+		{"two steps back is outside skew", rfc6238Secret, codeAt(current - 2), 0, 0, false},
+		{"two steps ahead is outside skew", rfc6238Secret, codeAt(current + 2), 0, 0, false},
+		{"replayed code", rfc6238Secret, "050471", current, 0, false},
+		{"code older than the last one used", rfc6238Secret, "081804", current, 0, false},
+		{"later code after an earlier one was used", rfc6238Secret, "050471", current - 1, current, true},
+		{"spaces are ignored", rfc6238Secret, " 050 471 ", 0, current, true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "050471", 0, current, true},
+		{"wrong code", rfc6238Secret, "123456", 0, 0, false},
+		{"too short", rfc6238Secret, "05047", 0, 0, false},
+		{"too long", rfc6238Secret, "0504710", 0, 0, false},
+		{"invalid secret", "not base32!", "050471", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := verifyTOTP(tt.secret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("verifyTOTP = %d, %v; want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTOTPSecretRoundTrip(t *testing.T) {
+	secret, err := newTOTPSecret()
+	if err != nil {
+		t.Fatalf("newTOTPSecret: %v", err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes (%v), want 20", secret, len(key), err)
+	}
+
+	now := time.Now()
+	code := totpCode(key, now.Unix()/int64(totpPeriod.Seconds()))
+	step, ok := verifyTOTP(secret, code, now, 0)
+	if !ok {
+		t.Fatalf("verifyTOTP rejected the current code of a new secret")
+	}
+	if _, ok := verifyTOTP(secret, code, now, step); ok {
+		t.Error("verifyTOTP accepted the same code twice")
+	}
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"ecommerce/internal/domain"
+	"ecommerce/internal/repository"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// qrCodeSize is the width and height of the enrolment QR code, in pixels
+const qrCodeSize = 256
+
+var (
+	// ErrTwoFactorAlreadyEnabled is returned when enrolling while two-factor is on
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnrolled is returned when confirming before enrolment started
+	ErrTwoFactorNotEnrolled = errors.New("start two-factor enrolment first")
+	// ErrTwoFactorNotEnabled is returned when disabling or renewing codes while two-factor is off
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrInvalidTwoFactorCode is returned for wrong, reused or expired TOTP and recovery codes
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTwoFactorRequired is returned when disabling two-factor on a role that must use it
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for your role")
+)
+
+// TwoFactorService defines TOTP enrolment, code verification and the per-role requirement
+type TwoFactorService interface {
+	Status(userID string) (*domain.TwoFactorStatus, error)
+	Enroll(userID string, password string) (*domain.TwoFactorEnrollment, error)
+	Confirm(userID string, code string, client domain.ClientInfo) ([]string, error)
+	Disable(userID string, req *domain.DisableTwoFactorRequest, client domain.ClientInfo) error
+	RenewRecoveryCodes(userID string, code string, client domain.ClientInfo) ([]string, error)
+	Verify(user *domain.User, code string, client domain.ClientInfo) (bool, error)
+	IsRequired(role string) (bool, error)
+	ListRolePolicies() ([]domain.RolePolicy, error)
+	SetRolePolicy(adminID string, role string, requireTwoFactor bool, client domain.ClientInfo) (*domain.RolePolicy, error)
+}
+
+type twoFactorService struct {
+	userRepo      repository.UserRepository
+	twoFactorRepo repository.TwoFactorRepository
+	auditRepo     repository.AuditRepository
+	issuer        string // Shown as the account's name in authenticator apps
+}
+
+// NewTwoFactorService creates a new two-factor service
+func NewTwoFactorService(userRepo repository.UserRepository, twoFactorRepo repository.TwoFactorRepository, auditRepo repository.AuditRepository, issuer string) TwoFactorService {
+	return &twoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		auditRepo:     auditRepo,
+		issuer:        issuer,
+	}
+}
+
+// Status describes the user's two-factor setup
+func (s *twoFactorService) Status(userID string) (*domain.TwoFactorStatus, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.IsRequired(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.TwoFactorStatus{
+		Enabled:   user.HasTwoFactor(),
+		EnabledAt: user.TwoFactorEnabledAt,
+		Required:  required,
+	}
+	if status.Enabled {
+		if status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountRecoveryCodes(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll generates a new secret after checking the password. Two-factor is only
+// turned on by Confirm, once the user proved their authenticator has the secret.
+func (s *twoFactorService) Enroll(userID string, password string) (*domain.TwoFactorEnrollment, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrWrongPassword
+	}
+	if user.HasTwoFactor() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	uri := totpURI(s.issuer, user.Email, secret)
+	qrCode, err := qrCodePNG(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = &secret
+	user.TOTPLastStep = 0
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+// Confirm turns two-factor on with a code from the enrolled authenticator.
+// The recovery codes are returned in plain text this once.
+func (s *twoFactorService) Confirm(userID string, code string, client domain.ClientInfo) ([]string, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.HasTwoFactor() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	step, ok := verifyTOTP(*user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := s.issueRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.TOTPLastStep = step
+	user.TwoFactorEnabledAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditTwoFactorEnabled, nil, client)
+	return codes, nil
+}
+
+// Disable turns two-factor off after checking the password and a code.
+// Roles that require two-factor cannot turn it off.
+func (s *twoFactorService) Disable(userID string, req *domain.DisableTwoFactorRequest, client domain.ClientInfo) error {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		return ErrWrongPassword
+	}
+	if !user.HasTwoFactor() {
+		return ErrTwoFactorNotEnabled
+	}
+	required, err := s.IsRequired(user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+	ok, err := s.Verify(user, req.Code, client)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPSecret = nil
+	user.TOTPLastStep = 0
+	user.TwoFactorEnabledAt = nil
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.DeleteRecoveryCodes(user.ID); err != nil {
+		return err
+	}
+
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditTwoFactorDisabled, nil, client)
+	return nil
+}
+
+// RenewRecoveryCodes replaces the recovery codes after checking a TOTP code
+func (s *twoFactorService) RenewRecoveryCodes(userID string, code string, client domain.ClientInfo) ([]string, error) {
+	user, err := s.activeUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.HasTwoFactor() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	step, ok := verifyTOTP(*user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	user.TOTPLastStep = step
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	codes, err := s.issueRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditRecoveryCodesRenewed, nil, client)
+	return codes, nil
+}
+
+// Verify checks a TOTP code, or a recovery code, which is then used up
+func (s *twoFactorService) Verify(user *domain.User, code string, client domain.ClientInfo) (bool, error) {
+	if !user.HasTwoFactor() {
+		return false, nil
+	}
+
+	if step, ok := verifyTOTP(*user.TOTPSecret, code, time.Now(), user.TOTPLastStep); ok {
+		user.TOTPLastStep = step
+		user.UpdatedAt = time.Now()
+		return true, s.userRepo.Update(user)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	used, err := s.twoFactorRepo.ConsumeRecoveryCode(user.ID, hashToken(normalized))
+	if err != nil || !used {
+		return false, err
+	}
+	recordAudit(s.auditRepo, user.ID, user.ID, domain.AuditRecoveryCodeUsed, nil, client)
+	return true, nil
+}
+
+// IsRequired reports whether admins require two-factor for the role
+func (s *twoFactorService) IsRequired(role string) (bool, error) {
+	policy, err := s.twoFactorRepo.GetRolePolicy(role)
+	if err != nil || policy == nil {
+		return false, err
+	}
+	return policy.RequireTwoFactor, nil
+}
+
+// ListRolePolicies returns the policy of every role, defaults included
+func (s *twoFactorService) ListRolePolicies() ([]domain.RolePolicy, error) {
+	saved, err := s.twoFactorRepo.ListRolePolicies()
+	if err != nil {
+		return nil, err
+	}
+	byRole := make(map[string]domain.RolePolicy, len(saved))
+	for _, policy := range saved {
+		byRole[policy.Role] = policy
+	}
+
+	roles := []string{domain.RoleCustomer, domain.RoleSeller, domain.RoleAdmin}
+	policies := make([]domain.RolePolicy, len(roles))
+	for i, role := range roles {
+		policy, ok := byRole[role]
+		if !ok {
+			policy = domain.RolePolicy{Role: role}
+		}
+		policies[i] = policy
+	}
+	return policies, nil
+}
+
+// SetRolePolicy changes whether a role must use two-factor.
+// Users of the role who did not enrol keep signing in, but are sent to enrol before using their role.
+func (s *twoFactorService) SetRolePolicy(adminID string, role string, requireTwoFactor bool, client domain.ClientInfo) (*domain.RolePolicy, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !domain.IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	policy := &domain.RolePolicy{
+		Role:             role,
+		RequireTwoFactor: requireTwoFactor,
+		UpdatedBy:        &adminID,
+		UpdatedAt:        time.Now(),
+	}
+	if err := s.twoFactorRepo.SaveRolePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	details := map[string]string{"role": role, "requireTwoFactor": "false"}
+	if requireTwoFactor {
+		details["requireTwoFactor"] = "true"
+	}
+	recordAudit(s.auditRepo, adminID, adminID, domain.AuditRolePolicyUpdated, details, client)
+	return policy, nil
+}
+
+// issueRecoveryCodes replaces the user's recovery codes and returns them in plain text
+func (s *twoFactorService) issueRecoveryCodes(userID string) ([]string, error) {
+	plain := make([]string, domain.RecoveryCodeCount)
+	records := make([]domain.RecoveryCode, domain.RecoveryCodeCount)
+	for i := range plain {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		plain[i] = code
+		records[i] = domain.RecoveryCode{UserID: userID, CodeHash: hashToken(normalizeRecoveryCode(code))}
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, records); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// activeUser loads a user that may still change their account
+func (s *twoFactorService) activeUser(userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive {
+		return nil, ErrAccountSuspended
+	}
+	return user, nil
+}
+
+// newRecoveryCode generates a code formatted as "xxxxx-xxxxx" (50 random bits)
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	const alphabet = "abcdefghijklmnopqrstuvwxyz234567"
+	code := make([]byte, 0, 11)
+	for i, v := range b {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		code = append(code, alphabet[v&31])
+	}
+	return string(code), nil
+}
+
+// normalizeRecoveryCode lowercases a typed recovery code and drops separators, or returns "" when it cannot be one
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	if len(code) != 10 {
+		return ""
+	}
+	return code
+}
+
+// qrCodePNG renders content as a QR code PNG
+func qrCodePNG(content string) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+	if code, err = barcode.Scale(code, qrCodeSize, qrCodeSize); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
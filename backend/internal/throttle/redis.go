@@ -0,0 +1,243 @@
+package throttle
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisTimeout  = 3 * time.Second
+	redisPoolSize = 8
+	redisPrefix   = "throttle:"
+)
+
+// RedisStore keeps counters in Redis, or any server speaking its protocol (Valkey,
+// KeyDB, Dragonfly), so every API instance sees the same attempts. It only needs
+// INCR, PEXPIRE, SET PX, PTTL and DEL, spoken over a small connection pool.
+type RedisStore struct {
+	addr     string
+	password string
+	db       int
+	pool     chan *redisConn
+}
+
+// NewRedisStore connects to redis://[:password@]host:port[/db] and checks the server answers
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "redis" || u.Host == "" {
+		return nil, fmt.Errorf("throttle: invalid Redis URL %q", rawURL)
+	}
+
+	s := &RedisStore{
+		addr: u.Host,
+		pool: make(chan *redisConn, redisPoolSize),
+	}
+	if password, ok := u.User.Password(); ok {
+		s.password = password
+	}
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		if s.db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("throttle: invalid Redis database %q", path)
+		}
+	}
+
+	if _, err := s.do([]string{"PING"}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Incr adds one to a counter and sets its TTL, in one round trip
+func (s *RedisStore) Incr(key string, ttl time.Duration) (int64, error) {
+	replies, err := s.do(
+		[]string{"INCR", redisPrefix + key},
+		[]string{"PEXPIRE", redisPrefix + key, strconv.FormatInt(ttl.Milliseconds(), 10)},
+	)
+	if err != nil {
+		return 0, err
+	}
+	count, ok := replies[0].(int64)
+	if !ok {
+		return 0, errors.New("throttle: unexpected INCR reply")
+	}
+	return count, nil
+}
+
+// Block marks a key as blocked for d
+func (s *RedisStore) Block(key string, d time.Duration) error {
+	_, err := s.do([]string{"SET", redisPrefix + key, "1", "PX", strconv.FormatInt(d.Milliseconds(), 10)})
+	return err
+}
+
+// BlockedFor returns the remaining block of a key
+func (s *RedisStore) BlockedFor(key string) (time.Duration, error) {
+	replies, err := s.do([]string{"PTTL", redisPrefix + key})
+	if err != nil {
+		return 0, err
+	}
+	ms, ok := replies[0].(int64)
+	if !ok {
+		return 0, errors.New("throttle: unexpected PTTL reply")
+	}
+	if ms <= 0 {
+		// -2: no such key; -1: no TTL, which this store never sets
+		return 0, nil
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Reset deletes counters and blocks
+func (s *RedisStore) Reset(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, redisPrefix+key)
+	}
+	_, err := s.do(args)
+	return err
+}
+
+// do sends the commands in a single write (pipelined) and reads one reply per command.
+// A connection that failed is dropped instead of going back to the pool.
+func (s *RedisStore) do(commands ...[]string) ([]any, error) {
+	conn, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := conn.do(commands)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		conn.Close()
+		return nil, fmt.Errorf("throttle: redis: %w", err)
+	}
+	s.put(conn)
+	if err != nil {
+		return nil, fmt.Errorf("throttle: redis: %w", err)
+	}
+	return replies, nil
+}
+
+func (s *RedisStore) get() (*redisConn, error) {
+	select {
+	case conn := <-s.pool:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", s.addr, redisTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("throttle: redis: %w", err)
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+
+	var setup [][]string
+	if s.password != "" {
+		setup = append(setup, []string{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+	if len(setup) > 0 {
+		if _, err := conn.do(setup); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("throttle: redis: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (s *RedisStore) put(conn *redisConn) {
+	select {
+	case s.pool <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// redisError is an error reply sent by the server; the connection stays usable
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// redisConn is one connection speaking RESP2
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *redisConn) do(commands [][]string) ([]any, error) {
+	if err := c.SetDeadline(time.Now().Add(redisTimeout)); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, args := range commands {
+		fmt.Fprintf(&b, "*%d\r\n", len(args))
+		for _, arg := range args {
+			fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if _, err := c.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+
+	// Every reply is read even after an error reply, so the connection stays in sync
+	replies := make([]any, len(commands))
+	var firstErr error
+	for i := range commands {
+		reply, err := c.readReply()
+		var serverErr redisError
+		if err != nil && !errors.As(err, &serverErr) {
+			return nil, err
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// readReply reads a simple string, error, integer or bulk string reply
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	}
+	return nil, fmt.Errorf("unsupported reply %q", line)
+}
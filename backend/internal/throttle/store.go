@@ -0,0 +1,98 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Store keeps attempt counters and blocks shared by every API instance.
+// Keys are opaque to the store; entries disappear once their TTL runs out.
+type Store interface {
+	// Incr adds one to a counter and (re)sets its TTL, returning the new count
+	Incr(key string, ttl time.Duration) (int64, error)
+	// Block marks a key as blocked for d, replacing any earlier block
+	Block(key string, d time.Duration) error
+	// BlockedFor returns how long a key stays blocked, 0 when it is not
+	BlockedFor(key string) (time.Duration, error)
+	// Reset deletes counters and blocks
+	Reset(keys ...string) error
+}
+
+// memorySweepInterval is how often MemoryStore drops expired entries
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// MemoryStore keeps counters in process memory (development, single instance)
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+// Incr adds one to a counter and sets its TTL
+func (m *MemoryStore) Incr(key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.sweep(now)
+
+	entry := m.entries[key]
+	if !now.Before(entry.expiresAt) {
+		entry.count = 0
+	}
+	entry.count++
+	entry.expiresAt = now.Add(ttl)
+	m.entries[key] = entry
+	return entry.count, nil
+}
+
+// Block marks a key as blocked for d
+func (m *MemoryStore) Block(key string, d time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{count: 1, expiresAt: time.Now().Add(d)}
+	return nil
+}
+
+// BlockedFor returns the remaining block of a key
+func (m *MemoryStore) BlockedFor(key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remaining := time.Until(m.entries[key].expiresAt)
+	if remaining < 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+// Reset deletes counters and blocks
+func (m *MemoryStore) Reset(keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// sweep drops expired entries, at most once per memorySweepInterval. Callers hold mu.
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < memorySweepInterval {
+		return
+	}
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -18,6 +18,16 @@ func ErrorResponse(message string, details string) map[string]interface{} {
 	}
 }
 
+// ValidationErrorResponse creates an error response carrying field-level problems
+func ValidationErrorResponse(message string, fields map[string]string) map[string]interface{} {
+	return map[string]interface{}{
+		"success": false,
+		"message": message,
+		"error":   "validation failed",
+		"fields":  fields,
+	}
+}
+
 // PaginatedResponse creates a paginated response
 func PaginatedResponse(items interface{}, total int64, page int, perPage int) map[string]interface{} {
 	totalPages := (int(total) + perPage - 1) / perPage
@@ -0,0 +1,42 @@
+-- Store money as integer minor units (centavos) instead of NUMERIC major units.
+-- NUMERIC(10, 2) holds at most two decimals, so multiplying by 100 is exact.
+
+ALTER TABLE products
+    ALTER COLUMN price TYPE BIGINT USING (price * 100)::BIGINT,
+    ALTER COLUMN compare_at_price TYPE BIGINT USING (compare_at_price * 100)::BIGINT,
+    ALTER COLUMN cost_price TYPE BIGINT USING (cost_price * 100)::BIGINT;
+
+ALTER TABLE orders
+    ALTER COLUMN total_amount TYPE BIGINT USING (total_amount * 100)::BIGINT,
+    ALTER COLUMN shipping_fee TYPE BIGINT USING (shipping_fee * 100)::BIGINT,
+    ALTER COLUMN discount_amount TYPE BIGINT USING (discount_amount * 100)::BIGINT;
+
+ALTER TABLE order_items
+    ALTER COLUMN price_at_time TYPE BIGINT USING (price_at_time * 100)::BIGINT;
+
+-- Columns created by the application (GORM AutoMigrate) rather than by these scripts:
+-- the tax breakdown, cart and invoice amounts. Like config.RunDataMigrations, convert
+-- those that exist and are still in major units.
+DO $$
+DECLARE
+    col RECORD;
+BEGIN
+    FOR col IN
+        SELECT table_name, column_name FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND data_type IN ('numeric', 'real', 'double precision')
+          AND (table_name, column_name) IN (
+              ('orders', 'tax_amount'),
+              ('order_items', 'tax_base'),
+              ('order_items', 'tax_icms_amount'),
+              ('order_items', 'tax_difal_amount'),
+              ('order_items', 'tax_fcp_amount'),
+              ('order_items', 'tax_total_tax'),
+              ('cart_items', 'price_at_add'),
+              ('invoices', 'total_amount')
+          )
+    LOOP
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN %I TYPE BIGINT USING (%I * 100)::BIGINT',
+            col.table_name, col.column_name, col.column_name);
+    END LOOP;
+END $$;
@@ -0,0 +1,48 @@
+-- Seller onboarding: customers apply to sell, an admin approval creates their store.
+
+CREATE TABLE IF NOT EXISTS seller_applications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    store_name VARCHAR(100) NOT NULL,
+    legal_name VARCHAR(100) NOT NULL,
+    cnpj CHAR(14) NOT NULL,
+    description TEXT,
+    bank_bank_code CHAR(3),
+    bank_branch VARCHAR(10),
+    bank_number VARCHAR(20),
+    bank_holder_name VARCHAR(100),
+    bank_holder_document VARCHAR(14),
+    address JSONB,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    rejection_reason TEXT,
+    store_id UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_seller_applications_user ON seller_applications(user_id);
+CREATE INDEX IF NOT EXISTS idx_seller_applications_status ON seller_applications(status);
+
+-- One public store per seller
+CREATE TABLE IF NOT EXISTS stores (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seller_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(120) NOT NULL UNIQUE,
+    logo_url VARCHAR(255),
+    description TEXT,
+    shipping_policy TEXT,
+    return_policy TEXT,
+    legal_name VARCHAR(100),
+    cnpj CHAR(14),
+    bank_bank_code CHAR(3),
+    bank_branch VARCHAR(10),
+    bank_number VARCHAR(20),
+    bank_holder_name VARCHAR(100),
+    bank_holder_document VARCHAR(14),
+    address JSONB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- Admin role: admins suspend accounts instead of deleting them.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
@@ -0,0 +1,7 @@
+-- Products of suspended sellers are hidden from the storefront.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS seller_suspended BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_products_seller_suspended ON products(seller_suspended);
+
+UPDATE products SET seller_suspended = TRUE
+WHERE seller_id IN (SELECT id FROM users WHERE is_active = FALSE);
@@ -0,0 +1,19 @@
+-- LGPD data-subject requests (export and erasure), processed in the background.
+
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    kind VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    archive BYTEA,
+    expires_at TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_user_id ON privacy_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_status ON privacy_requests(status);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP;
@@ -0,0 +1,24 @@
+-- Login sessions: one per login, shared by its refresh token family and access tokens (jti).
+-- Users list and revoke them at /api/me/sessions.
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    device_name VARCHAR(100),
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_reason VARCHAR(50) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Existing logins become sessions so their access tokens stay valid
+INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at, expires_at)
+SELECT rt.family_id::uuid, rt.user_id::uuid, rt.user_agent, rt.ip_address, rt.created_at, rt.created_at, rt.expires_at
+FROM refresh_tokens rt
+WHERE rt.revoked_at IS NULL AND rt.expires_at > NOW()
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Admin impersonation: a session acting as a user records the admin behind it.
+-- Every request made with it is audited (impersonation.request) on the user with the admin as actor.
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS actor_id UUID REFERENCES users(id);
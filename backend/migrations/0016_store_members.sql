@@ -0,0 +1,19 @@
+-- Store teams: sellers invite users by email to work for their store with a store role
+-- (manager, catalog_editor, fulfilment or analyst). user_id and accepted_at are set when the
+-- account with the invited email accepts; until then the row is a pending invitation.
+
+CREATE TABLE IF NOT EXISTS store_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id),
+    email VARCHAR(255) NOT NULL,
+    user_id UUID REFERENCES users(id),
+    role VARCHAR(20) NOT NULL,
+    invited_by_id UUID NOT NULL REFERENCES users(id),
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (store_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS idx_store_members_email ON store_members(email);
+CREATE INDEX IF NOT EXISTS idx_store_members_user_id ON store_members(user_id);
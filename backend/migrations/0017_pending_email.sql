@@ -0,0 +1,3 @@
+-- Email changes: the requested address waits here until the link sent to it is opened.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255);
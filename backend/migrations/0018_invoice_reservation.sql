@@ -0,0 +1,8 @@
+-- NF-e issuing: reserving a number inserts the invoice as 'pending' in the same transaction,
+-- and a seller can hold only one pending or authorized invoice per order. Rejected invoices
+-- stay as history. Invoices still pending have no access key yet, so its uniqueness only
+-- covers generated keys. Like config.RunDataMigrations, the full access key index is replaced.
+
+DROP INDEX IF EXISTS idx_invoices_access_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_access_key ON invoices(access_key) WHERE access_key <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_open ON invoices(order_id, seller_id) WHERE status <> 'rejected';
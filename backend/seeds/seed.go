@@ -1,10 +1,15 @@
 package seeds
 
 import (
+	"crypto/rand"
 	"ecommerce/internal/domain"
+	"encoding/hex"
 	"log"
+	"time"
 
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Run seeds basic data
@@ -24,7 +29,7 @@ func Run(db *gorm.DB) {
 		{"name": "Calçados", "slug": "calcados"},
 		{"name": "Sandálias", "slug": "sandalias"},
 		{"name": "Tênis", "slug": "tenis"},
-		{"name": "Sapatos", "sapatos"},
+		{"name": "Sapatos", "slug": "sapatos"},
 	}
 	for _, c := range categories {
 		if err := db.Exec("INSERT INTO categories (name, slug) VALUES (?, ?) ON CONFLICT (slug) DO NOTHING", c["name"], c["slug"]).Error; err != nil {
@@ -32,13 +37,16 @@ func Run(db *gorm.DB) {
 		}
 	}
 
+	// Demo store owning the catalog below
+	sellerID := seedDemoStore(db)
+
 	// Products
 	products := []domain.Product{
 		{
 			Name:          "Vestido Midi Minimalista Preto",
 			Slug:          "vestido-midi-minimalista-preto",
 			Description:   "Vestido midi elegante em tecido premium com corte minimalista. Perfeito para ocasiões especiais e uso profissional. Detalhes em costura francesa e forro interno.",
-			Price:         459.90,
+			Price:         domain.Cents(45990),
 			StockQuantity: 45,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -47,7 +55,7 @@ func Run(db *gorm.DB) {
 			Name:          "Blazer Alfaiataria Oversized Caramelo",
 			Slug:          "blazer-alfaiataria-oversized-caramelo",
 			Description:   "Blazer oversized em alfaiataria italiana. Corte estruturado com ombros marcados e botões dourados. Forro em viscose premium.",
-			Price:         689.90,
+			Price:         domain.Cents(68990),
 			StockQuantity: 30,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -56,7 +64,7 @@ func Run(db *gorm.DB) {
 			Name:          "Calça Wide Leg Linho Off-White",
 			Slug:          "calca-wide-leg-linho-off-white",
 			Description:   "Calça pantalona em linho europeu com caimento wide leg. Cós alto, bolsos laterais e acabamento em pespontos contrastantes.",
-			Price:         389.90,
+			Price:         domain.Cents(38990),
 			StockQuantity: 60,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -65,7 +73,7 @@ func Run(db *gorm.DB) {
 			Name:          "Blusa Gola Alta Tricot Creme",
 			Slug:          "blusa-gola-alta-tricot-creme",
 			Description:   "Blusa em tricot premium com gola alta. Manga longa, textura canelada e caimento justo. Ideal para compor looks sofisticados.",
-			Price:         259.90,
+			Price:         domain.Cents(25990),
 			StockQuantity: 80,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -74,7 +82,7 @@ func Run(db *gorm.DB) {
 			Name:          "Saia Midi Plissada Navy",
 			Slug:          "saia-midi-plissada-navy",
 			Description:   "Saia midi plissada em crepe com movimento fluido. Cós embutido, zíper lateral invisível e forro em seda sintética.",
-			Price:         349.90,
+			Price:         domain.Cents(34990),
 			StockQuantity: 50,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -83,7 +91,7 @@ func Run(db *gorm.DB) {
 			Name:          "Vestido Longo Festa Veludo Verde Esmeralda",
 			Slug:          "vestido-longo-festa-veludo-verde",
 			Description:   "Vestido longo em veludo molhado com decote V profundo. Fenda lateral, alças ajustáveis e caimento sereia. Perfeito para eventos noturnos.",
-			Price:         799.90,
+			Price:         domain.Cents(79990),
 			StockQuantity: 20,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -92,7 +100,7 @@ func Run(db *gorm.DB) {
 			Name:          "Camisa Oversized Linho Branco",
 			Slug:          "camisa-oversized-linho-branco",
 			Description:   "Camisa oversized em linho puro com corte relaxado. Botões de madrepérola, mangas dobráveis e bolso no peito.",
-			Price:         319.90,
+			Price:         domain.Cents(31990),
 			StockQuantity: 70,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -101,7 +109,7 @@ func Run(db *gorm.DB) {
 			Name:          "Jeans Mom Fit Cintura Alta Azul Escuro",
 			Slug:          "jeans-mom-fit-cintura-alta",
 			Description:   "Jeans mom fit em denim premium com lavagem escura. Cintura alta, cinco bolsos e acabamento em barra dobrada.",
-			Price:         279.90,
+			Price:         domain.Cents(27990),
 			StockQuantity: 90,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -110,7 +118,7 @@ func Run(db *gorm.DB) {
 			Name:          "Conjunto Tricot Cropped + Saia Nude",
 			Slug:          "conjunto-tricot-cropped-saia-nude",
 			Description:   "Conjunto em tricot premium: top cropped manga curta + saia midi. Textura canelada e caimento estruturado.",
-			Price:         489.90,
+			Price:         domain.Cents(48990),
 			StockQuantity: 40,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -119,7 +127,7 @@ func Run(db *gorm.DB) {
 			Name:          "Vestido Tubinho Crepe Vinho",
 			Slug:          "vestido-tubinho-crepe-vinho",
 			Description:   "Vestido tubinho em crepe stretch com manga 3/4. Zíper lateral invisível, forro completo e comprimento midi.",
-			Price:         399.90,
+			Price:         domain.Cents(39990),
 			StockQuantity: 55,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -128,7 +136,7 @@ func Run(db *gorm.DB) {
 			Name:          "Blazer Cropped Tweed Rosa Claro",
 			Slug:          "blazer-cropped-tweed-rosa",
 			Description:   "Blazer cropped em tweed bouclê com detalhes em franjas. Botões revestidos em tecido e forro em cetim.",
-			Price:         729.90,
+			Price:         domain.Cents(72990),
 			StockQuantity: 25,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -137,7 +145,7 @@ func Run(db *gorm.DB) {
 			Name:          "Calça Reta Alfaiataria Preta",
 			Slug:          "calca-reta-alfaiataria-preta",
 			Description:   "Calça de alfaiataria com corte reto e vinco marcado. Cós médio, bolsos laterais e passantes para cinto.",
-			Price:         339.90,
+			Price:         domain.Cents(33990),
 			StockQuantity: 65,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -146,7 +154,7 @@ func Run(db *gorm.DB) {
 			Name:          "Body Gola Careca Manga Longa Preto",
 			Slug:          "body-gola-careca-manga-longa-preto",
 			Description:   "Body básico em malha suplex com alta elasticidade. Gola careca, manga longa e fechamento em colchetes.",
-			Price:         149.90,
+			Price:         domain.Cents(14990),
 			StockQuantity: 100,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -155,7 +163,7 @@ func Run(db *gorm.DB) {
 			Name:          "Saia Lápis Couro Ecológico Marrom",
 			Slug:          "saia-lapis-couro-ecologico-marrom",
 			Description:   "Saia lápis em couro ecológico de alta qualidade. Fenda traseira, zíper lateral e forro em malha.",
-			Price:         289.90,
+			Price:         domain.Cents(28990),
 			StockQuantity: 45,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -164,7 +172,7 @@ func Run(db *gorm.DB) {
 			Name:          "Vestido Chemise Linho Listrado",
 			Slug:          "vestido-chemise-linho-listrado",
 			Description:   "Vestido chemise em linho com listras verticais. Botões frontais, cinto amarração e bolsos laterais.",
-			Price:         379.90,
+			Price:         domain.Cents(37990),
 			StockQuantity: 50,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -173,7 +181,7 @@ func Run(db *gorm.DB) {
 			Name:          "Blusa Ombro a Ombro Renda Branca",
 			Slug:          "blusa-ombro-ombro-renda-branca",
 			Description:   "Blusa ciganinha em renda guipir com elástico nos ombros. Manga bufante e forro em malha delicada.",
-			Price:         219.90,
+			Price:         domain.Cents(21990),
 			StockQuantity: 60,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -182,7 +190,7 @@ func Run(db *gorm.DB) {
 			Name:          "Calça Jogger Crepe Bege",
 			Slug:          "calca-jogger-crepe-bege",
 			Description:   "Calça jogger em crepe premium com elástico na cintura e punhos. Bolsos laterais e cordão de ajuste.",
-			Price:         299.90,
+			Price:         domain.Cents(29990),
 			StockQuantity: 70,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -191,7 +199,7 @@ func Run(db *gorm.DB) {
 			Name:          "Macaquinho Linho Curto Terracota",
 			Slug:          "macaquinho-linho-curto-terracota",
 			Description:   "Macaquinho em linho puro com alças reguláveis. Decote quadrado, elástico na cintura e bolsos laterais.",
-			Price:         329.90,
+			Price:         domain.Cents(32990),
 			StockQuantity: 40,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -200,7 +208,7 @@ func Run(db *gorm.DB) {
 			Name:          "Vestido Longo Babados Floral",
 			Slug:          "vestido-longo-babados-floral",
 			Description:   "Vestido longo em viscose com estampa floral romântica. Babados em camadas, decote V e amarração nas costas.",
-			Price:         449.90,
+			Price:         domain.Cents(44990),
 			StockQuantity: 35,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -209,7 +217,7 @@ func Run(db *gorm.DB) {
 			Name:          "Colete Tricot Sem Manga Caramelo",
 			Slug:          "colete-tricot-sem-manga-caramelo",
 			Description:   "Colete em tricot premium sem mangas com decote V. Textura canelada e acabamento em barra reta.",
-			Price:         189.90,
+			Price:         domain.Cents(18990),
 			StockQuantity: 75,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -218,7 +226,7 @@ func Run(db *gorm.DB) {
 			Name:          "Saia Curta Jeans Destroyed",
 			Slug:          "saia-curta-jeans-destroyed",
 			Description:   "Saia jeans com efeito destroyed e barra desfiada. Botões frontais, bolsos funcionais e lavagem clara.",
-			Price:         199.90,
+			Price:         domain.Cents(19990),
 			StockQuantity: 80,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -227,7 +235,7 @@ func Run(db *gorm.DB) {
 			Name:          "Blazer Alongado Xadrez Cinza",
 			Slug:          "blazer-alongado-xadrez-cinza",
 			Description:   "Blazer alongado em tecido xadrez príncipe de gales. Corte estruturado, botões forrados e bolsos chapados.",
-			Price:         659.90,
+			Price:         domain.Cents(65990),
 			StockQuantity: 30,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -236,7 +244,7 @@ func Run(db *gorm.DB) {
 			Name:          "Calça Cargo Sarja Verde Militar",
 			Slug:          "calca-cargo-sarja-verde-militar",
 			Description:   "Calça cargo em sarja resistente com múltiplos bolsos utilitários. Cós com elástico e cordão de ajuste.",
-			Price:         269.90,
+			Price:         domain.Cents(26990),
 			StockQuantity: 85,
 			IsActive:      true,
 			IsFeatured:    false,
@@ -245,7 +253,7 @@ func Run(db *gorm.DB) {
 			Name:          "Vestido Festa Paetês Dourado",
 			Slug:          "vestido-festa-paetes-dourado",
 			Description:   "Vestido curto todo bordado em paetês dourados. Alças finas, decote reto e forro em malha stretch.",
-			Price:         889.90,
+			Price:         domain.Cents(88990),
 			StockQuantity: 15,
 			IsActive:      true,
 			IsFeatured:    true,
@@ -253,6 +261,7 @@ func Run(db *gorm.DB) {
 	}
 
 	for _, p := range products {
+		p.SellerID = sellerID
 		if err := db.Create(&p).Error; err != nil {
 			log.Printf("seed product error (%s): %v\n", p.Slug, err)
 		} else {
@@ -260,5 +269,84 @@ func Run(db *gorm.DB) {
 		}
 	}
 
+	// Products seeded before stores existed have no seller and cannot be managed or ordered
+	if sellerID != "" {
+		if err := db.Model(&domain.Product{}).Where("seller_id = '' OR seller_id IS NULL").Update("seller_id", sellerID).Error; err != nil {
+			log.Println("seed product seller error:", err)
+		}
+	}
+
+	// Display exchange rates, existing rates are left untouched
+	rates := []domain.ExchangeRate{
+		{Base: "BRL", Quote: "EUR", Rate: 0.16},
+	}
+	for _, rate := range rates {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rate).Error; err != nil {
+			log.Printf("seed exchange rate error (%s/%s): %v\n", rate.Base, rate.Quote, err)
+		}
+	}
+
 	log.Println("✅ Seed completed successfully!")
 }
+
+// seedDemoStore creates the demo seller and their store once and returns the seller ID.
+// The seller gets a random password; use the password reset flow to sign in as them.
+func seedDemoStore(db *gorm.DB) string {
+	const email = "loja@example.com"
+
+	var seller domain.User
+	err := db.Where("email = ?", email).First(&seller).Error
+	if err == gorm.ErrRecordNotFound {
+		password := make([]byte, 24)
+		if _, err := rand.Read(password); err != nil {
+			log.Println("seed seller error:", err)
+			return ""
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(password)), bcrypt.DefaultCost)
+		if err != nil {
+			log.Println("seed seller error:", err)
+			return ""
+		}
+		now := time.Now()
+		seller = domain.User{
+			Name:            "Ateliê Demo",
+			Email:           email,
+			PasswordHash:    string(hash),
+			Role:            domain.RoleSeller,
+			IsActive:        true,
+			EmailVerifiedAt: &now,
+		}
+		if err := db.Create(&seller).Error; err != nil {
+			log.Println("seed seller error:", err)
+			return ""
+		}
+	} else if err != nil {
+		log.Println("seed seller error:", err)
+		return ""
+	}
+
+	store := domain.Store{
+		SellerID:       seller.ID,
+		Name:           "Ateliê Demo",
+		Slug:           "atelie-demo",
+		Description:    "Moda feminina e masculina com tecidos premium e produção nacional.",
+		ShippingPolicy: "Envio em até 2 dias úteis após a confirmação do pagamento.",
+		ReturnPolicy:   "Trocas e devoluções em até 7 dias após o recebimento, conforme o Código de Defesa do Consumidor.",
+		LegalName:      "Ateliê Demo Comércio de Roupas Ltda",
+		CNPJ:           "11222333000181",
+		Address: &domain.ShippingAddress{
+			Recipient:    "Ateliê Demo Comércio de Roupas Ltda",
+			Street:       "Avenida Paulista",
+			Number:       "1000",
+			Neighborhood: "Bela Vista",
+			City:         "São Paulo",
+			State:        "SP",
+			PostalCode:   "01310-100",
+			Country:      domain.DefaultCountry,
+		},
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&store).Error; err != nil {
+		log.Println("seed store error:", err)
+	}
+	return seller.ID
+}
@@ -5,6 +5,7 @@ export type User = {
   email: string
   name: string
   role: 'customer' | 'seller' | 'admin'
+  permissions?: string[]
   avatar_url?: string
   created_at?: string
   updated_at?: string
@@ -24,32 +25,72 @@ export type RegisterRequest = {
 export type AuthResponse = {
   user: User
   token: string
+  expiresAt: string
+  refreshToken: string
+  refreshExpiresAt: string
 }
 
 /**
- * Login user
+ * Returned by login instead of a session when the account has two-factor authentication
  */
-export async function login(data: LoginRequest): Promise<AuthResponse> {
-  const response = await api.post<any, ApiResponse<AuthResponse>>('/auth/login', data)
+export type LoginChallenge = {
+  twoFactorRequired: true
+  challengeToken: string
+  expiresAt: string
+}
+
+export function isLoginChallenge(response: AuthResponse | LoginChallenge): response is LoginChallenge {
+  return (response as LoginChallenge).twoFactorRequired === true
+}
+
+/**
+ * Store the session tokens returned by login, register and refresh
+ */
+function saveSession(session: AuthResponse): void {
+  if (session.token) {
+    localStorage.setItem('token', session.token)
+  }
+  if (session.refreshToken) {
+    localStorage.setItem('refreshToken', session.refreshToken)
+  }
+}
+
+/**
+ * Login user. Accounts with two-factor authentication get a challenge to pass to completeLogin.
+ */
+export async function login(data: LoginRequest): Promise<AuthResponse | LoginChallenge> {
+  const response = await api.post<any, ApiResponse<AuthResponse | LoginChallenge>>('/auth/login', data)
   
-  // Save token to localStorage
-  if (response.data.token) {
-    localStorage.setItem('token', response.data.token)
+  // Save tokens to localStorage
+  if (!isLoginChallenge(response.data)) {
+    saveSession(response.data)
   }
   
   return response.data
 }
 
+/**
+ * Finish a two-factor login with a TOTP or recovery code
+ */
+export async function completeLogin(challengeToken: string, code: string): Promise<AuthResponse> {
+  const response = await api.post<any, ApiResponse<AuthResponse>>('/auth/login/2fa', {
+    challenge_token: challengeToken,
+    code,
+  })
+
+  saveSession(response.data)
+
+  return response.data
+}
+
 /**
  * Register new user
  */
 export async function register(data: RegisterRequest): Promise<AuthResponse> {
   const response = await api.post<any, ApiResponse<AuthResponse>>('/auth/register', data)
   
-  // Save token to localStorage
-  if (response.data.token) {
-    localStorage.setItem('token', response.data.token)
-  }
+  // Save tokens to localStorage
+  saveSession(response.data)
   
   return response.data
 }
@@ -63,9 +104,14 @@ export async function getMe(): Promise<User> {
 }
 
 /**
- * Logout user (client-side only)
+ * Logout user, revoking the session on the server when still possible
  */
 export function logout(): void {
+  const token = localStorage.getItem('token')
+  if (token) {
+    api.post('/auth/logout', null, { headers: { Authorization: `Bearer ${token}` } }).catch(() => undefined)
+  }
   localStorage.removeItem('token')
+  localStorage.removeItem('refreshToken')
   localStorage.removeItem('auth-store')
 }
@@ -28,16 +28,59 @@ api.interceptors.request.use(
   }
 )
 
+// Single in-flight refresh shared by every request that got a 401
+let refreshing: Promise<string | null> | null = null
+
+/**
+ * Exchange the stored refresh token for a new access token.
+ * Refresh tokens are single use, so the new pair replaces the stored one.
+ */
+function refreshAccessToken(): Promise<string | null> {
+  const refreshToken = localStorage.getItem('refreshToken')
+  if (!refreshToken) {
+    return Promise.resolve(null)
+  }
+
+  if (!refreshing) {
+    refreshing = axios
+      .post(`${baseURL}/auth/refresh`, { refresh_token: refreshToken })
+      .then((response) => {
+        const { token, refreshToken: nextRefreshToken } = response.data.data
+        localStorage.setItem('token', token)
+        localStorage.setItem('refreshToken', nextRefreshToken)
+        return token as string
+      })
+      .catch(() => null)
+      .finally(() => {
+        refreshing = null
+      })
+  }
+  return refreshing
+}
+
 // Response Interceptor: Handle errors globally
 api.interceptors.response.use(
   (response) => {
     // Return data directly for cleaner usage
     return response.data
   },
-  (error: AxiosError<any>) => {
+  async (error: AxiosError<any>) => {
+    const request = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined
+
+    // Access tokens are short-lived: refresh once and replay the request
+    if (error.response?.status === 401 && request && !request._retried && !request.url?.includes('/auth/')) {
+      request._retried = true
+      const token = await refreshAccessToken()
+      if (token) {
+        request.headers.Authorization = `Bearer ${token}`
+        return api(request)
+      }
+    }
+
     // Handle 401 Unauthorized
     if (error.response?.status === 401) {
       localStorage.removeItem('token')
+      localStorage.removeItem('refreshToken')
       localStorage.removeItem('auth-store')
       
       // Only redirect if not already on login page
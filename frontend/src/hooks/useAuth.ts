@@ -8,5 +8,6 @@ export function useAuth() {
   const logout = useAuthStore((s) => s.logout)
   const isAuthenticated = !!token
   const isSeller = user?.role === 'seller'
-  return { user, token, login, register, logout, isAuthenticated, isSeller }
+  const can = (permission: string) => !!user?.permissions?.includes(permission)
+  return { user, token, login, register, logout, isAuthenticated, isSeller, can }
 }
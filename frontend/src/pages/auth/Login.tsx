@@ -8,12 +8,16 @@ import { Link, useNavigate, useLocation } from 'react-router-dom'
 import ImageWithFallback from '../../components/ui/ImageWithFallback'
 import { useState } from 'react'
 import { toast } from 'react-toastify'
+import type { LoginChallenge } from '../../api/auth'
 
 export default function Login() {
   const login = useAuthStore((state) => state.login)
+  const completeLogin = useAuthStore((state) => state.completeLogin)
   const navigate = useNavigate()
   const location = useLocation()
   const [isLoading, setIsLoading] = useState(false)
+  const [challenge, setChallenge] = useState<LoginChallenge | null>(null)
+  const [code, setCode] = useState('')
   const form = useForm({ 
     resolver: zodResolver(loginSchema),
     mode: 'onBlur'
@@ -24,7 +28,11 @@ export default function Login() {
   const onSubmit = form.handleSubmit(async (data) => {
     try {
       setIsLoading(true)
-      await login(data.email, data.password)
+      const pending = await login(data.email, data.password)
+      if (pending) {
+        setChallenge(pending)
+        return
+      }
       toast.success('Login realizado com sucesso!')
       navigate(from, { replace: true })
     } catch (err: any) {
@@ -34,6 +42,8 @@ export default function Login() {
         toast.error('E-mail ou senha incorretos.')
       } else if (status === 400) {
         toast.error('Dados inválidos. Verifique os campos.')
+      } else if (status === 429) {
+        toast.error('Muitas tentativas. Aguarde alguns minutos e tente novamente.')
       } else {
         toast.error('Erro ao conectar com o servidor.')
       }
@@ -43,6 +53,28 @@ export default function Login() {
     }
   })
 
+  // Second step for accounts with two-factor authentication; a challenge allows a single attempt
+  const onSubmitCode = async (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!challenge) return
+    try {
+      setIsLoading(true)
+      await completeLogin(challenge.challengeToken, code.trim())
+      toast.success('Login realizado com sucesso!')
+      navigate(from, { replace: true })
+    } catch (err: any) {
+      if (err?.response?.status === 401) {
+        toast.error('Código inválido. Entre novamente.')
+      } else {
+        toast.error('Erro ao conectar com o servidor.')
+      }
+      setChallenge(null)
+      setCode('')
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
   return (
     <div className="min-h-screen bg-[#FAFAFA] flex items-center justify-center p-4">
       <div className="w-full max-w-5xl">
@@ -70,6 +102,22 @@ export default function Login() {
               <p className="text-sm text-neutral-500">Faça login com sua conta para acessar alguns recursos.</p>
             </div>
 
+            {challenge ? (
+            <form className="space-y-4" onSubmit={onSubmitCode}>
+              <p className="text-sm text-neutral-600">
+                Digite o código do seu aplicativo autenticador ou um código de recuperação.
+              </p>
+              <Input
+                placeholder="Código"
+                autoComplete="one-time-code"
+                value={code}
+                onChange={(e) => setCode(e.target.value)}
+              />
+              <Button className="w-full h-11 font-medium" type="submit" disabled={isLoading || !code.trim()}>
+                {isLoading ? 'Verificando...' : 'Verificar'}
+              </Button>
+            </form>
+            ) : (
             <form className="space-y-4" onSubmit={onSubmit}>
               <div className="space-y-1">
                 <Input 
@@ -120,6 +168,7 @@ export default function Login() {
                 )}
               </Button>
             </form>
+            )}
 
             <div className="text-sm text-neutral-600 text-center">
               Não tem uma conta? <Link to="/auth/register" className="font-medium underline text-neutral-900">Cadastre-se</Link>
@@ -7,6 +7,7 @@ export type User = {
   email: string
   name: string
   role: 'customer' | 'seller' | 'admin'
+  permissions?: string[]
   avatar_url?: string
   created_at?: string
 }
@@ -17,7 +18,8 @@ type AuthState = {
   isAuthenticated: boolean
   
   // Actions
-  login: (email: string, password: string) => Promise<void>
+  login: (email: string, password: string) => Promise<authApi.LoginChallenge | null>
+  completeLogin: (challengeToken: string, code: string) => Promise<void>
   register: (name: string, email: string, password: string) => Promise<void>
   logout: () => void
   setUser: (user: User) => void
@@ -33,11 +35,24 @@ export const useAuthStore = create<AuthState>()(
       
       login: async (email: string, password: string) => {
         const response = await authApi.login({ email, password })
+        if (authApi.isLoginChallenge(response)) {
+          return response
+        }
         set({ 
           user: response.user, 
           token: response.token,
           isAuthenticated: true 
         })
+        return null
+      },
+
+      completeLogin: async (challengeToken: string, code: string) => {
+        const response = await authApi.completeLogin(challengeToken, code)
+        set({
+          user: response.user,
+          token: response.token,
+          isAuthenticated: true
+        })
       },
       
       register: async (name: string, email: string, password: string) => {